@@ -0,0 +1,201 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/infrastructure/redis"
+)
+
+// deadLetterBatchSize 批量重新入队或清除时单次处理的死信数
+const deadLetterBatchSize = 100
+
+// ErrNotDeadLettered 任务不在死信队列中
+var ErrNotDeadLettered = errors.New("task is not in dead letter queue")
+
+// DeadLetterPage 死信分页结果
+type DeadLetterPage struct {
+	DeadLetters []*model.DeadLetter
+	Total       int64
+	NextCursor  int64 // 下一页游标，0 表示没有下一页
+}
+
+// DeadLetterService 死信服务，负责浏览、重新入队与清除重试耗尽的任务
+type DeadLetterService struct {
+	deadLetterRepo repository.DeadLetterRepository
+	taskRepo       repository.TaskRepository
+	taskLogRepo    repository.TaskLogRepository
+	taskConfigRepo repository.TaskConfigRepository
+	queueManager   *redis.QueueManager
+}
+
+// NewDeadLetterService 创建死信服务
+func NewDeadLetterService(
+	deadLetterRepo repository.DeadLetterRepository,
+	taskRepo repository.TaskRepository,
+	taskLogRepo repository.TaskLogRepository,
+	taskConfigRepo repository.TaskConfigRepository,
+	queueManager *redis.QueueManager,
+) *DeadLetterService {
+	return &DeadLetterService{
+		deadLetterRepo: deadLetterRepo,
+		taskRepo:       taskRepo,
+		taskLogRepo:    taskLogRepo,
+		taskConfigRepo: taskConfigRepo,
+		queueManager:   queueManager,
+	}
+}
+
+// ListDeadLetters 分页查询死信，query.Limit 不大于 0 时使用默认分页大小
+func (s *DeadLetterService) ListDeadLetters(ctx context.Context, query repository.DeadLetterQuery) (*DeadLetterPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultListPageSize
+	}
+	if query.Limit > maxListPageSize {
+		query.Limit = maxListPageSize
+	}
+
+	// 多取一条用于判断是否还有下一页
+	pageSize := query.Limit
+	query.Limit++
+
+	deadLetters, total, err := s.deadLetterRepo.List(ctx, &query)
+	if err != nil {
+		return nil, fmt.Errorf("list dead letters failed: %w", err)
+	}
+
+	page := &DeadLetterPage{DeadLetters: deadLetters, Total: total}
+	if len(deadLetters) > pageSize {
+		page.DeadLetters = deadLetters[:pageSize]
+		page.NextCursor = page.DeadLetters[pageSize-1].ID
+	}
+
+	return page, nil
+}
+
+// GetDeadLetter 获取死信记录及对应的任务
+func (s *DeadLetterService) GetDeadLetter(ctx context.Context, taskID string) (*model.DeadLetter, *model.Task, error) {
+	deadLetter, err := s.deadLetterRepo.GetByTaskID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeadLetterNotFound) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrNotDeadLettered, taskID)
+		}
+		return nil, nil, fmt.Errorf("get dead letter failed: %w", err)
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get task failed: %w", err)
+	}
+	return deadLetter, task, nil
+}
+
+// RequeueDeadLetter 将死信任务重置为待处理并放回就绪队列，重试次数从 0 开始
+// payload 不为 nil 时替换任务参数，用于修正导致失败的输入；新参数与创建任务时一样按 payload schema 校验，
+// 不满足时返回 *model.PayloadValidationError
+func (s *DeadLetterService) RequeueDeadLetter(ctx context.Context, taskID string, payload map[string]interface{}) (*model.Task, error) {
+	_, task, err := s.GetDeadLetter(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload != nil {
+		config, err := s.taskConfigRepo.GetByType(ctx, task.TaskType)
+		if err != nil {
+			return nil, fmt.Errorf("get task config failed: %w", err)
+		}
+		if err := config.ValidatePayload(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	fromStatus := task.Status
+	message := "Task requeued from dead letter queue"
+	if payload != nil {
+		task.Payload = payload
+		message = "Task requeued from dead letter queue with edited payload"
+	}
+
+	if err := requeueTask(ctx, s.taskRepo, s.deadLetterRepo, s.queueManager, task, true); err != nil {
+		return nil, err
+	}
+
+	_ = s.taskLogRepo.Create(ctx, model.NewStateChangeLog(taskID, fromStatus, model.StatusPending, "", message))
+	return task, nil
+}
+
+// RequeueDeadLetters 将满足条件的死信任务全部重新入队，返回重新入队的数量
+// 单个任务失败时记录并跳过，不影响其余任务
+func (s *DeadLetterService) RequeueDeadLetters(ctx context.Context, query repository.DeadLetterQuery) (int, error) {
+	requeued := 0
+	err := s.eachDeadLetterBatch(ctx, query, func(deadLetters []*model.DeadLetter) error {
+		for _, deadLetter := range deadLetters {
+			if _, err := s.RequeueDeadLetter(ctx, deadLetter.TaskID, nil); err != nil {
+				_ = s.taskLogRepo.Create(ctx, model.NewInfoLog(deadLetter.TaskID, fmt.Sprintf("Requeue from dead letter queue failed: %v", err)))
+				continue
+			}
+			requeued++
+		}
+		return nil
+	})
+	return requeued, err
+}
+
+// PurgeDeadLetters 清除满足条件的死信记录，任务本身保留为失败状态，返回清除的数量
+func (s *DeadLetterService) PurgeDeadLetters(ctx context.Context, query repository.DeadLetterQuery) (int64, error) {
+	var purged int64
+	err := s.eachDeadLetterBatch(ctx, query, func(deadLetters []*model.DeadLetter) error {
+		taskIDs := make([]string, 0, len(deadLetters))
+		for _, deadLetter := range deadLetters {
+			taskIDs = append(taskIDs, deadLetter.TaskID)
+		}
+
+		deleted, err := s.deadLetterRepo.Delete(ctx, taskIDs)
+		if err != nil {
+			return fmt.Errorf("delete dead letters failed: %w", err)
+		}
+		purged += deleted
+
+		for _, taskID := range taskIDs {
+			_ = s.queueManager.RemoveDeadLetter(ctx, taskID)
+		}
+		return nil
+	})
+	return purged, err
+}
+
+// eachDeadLetterBatch 按游标分批遍历满足条件的死信，处理过程中删除记录不影响遍历
+func (s *DeadLetterService) eachDeadLetterBatch(ctx context.Context, query repository.DeadLetterQuery, fn func([]*model.DeadLetter) error) error {
+	query.Limit = deadLetterBatchSize
+	query.BeforeID = 0
+
+	for {
+		deadLetters, _, err := s.deadLetterRepo.List(ctx, &query)
+		if err != nil {
+			return fmt.Errorf("list dead letters failed: %w", err)
+		}
+		if len(deadLetters) == 0 {
+			return nil
+		}
+
+		if err := fn(deadLetters); err != nil {
+			return err
+		}
+
+		if len(deadLetters) < query.Limit {
+			return nil
+		}
+		query.BeforeID = deadLetters[len(deadLetters)-1].ID
+	}
+}
+
+// moveToDeadLetter 记录重试耗尽的任务并放入死信队列
+func moveToDeadLetter(ctx context.Context, deadLetterRepo repository.DeadLetterRepository, queueManager *redis.QueueManager, task *model.Task) error {
+	if err := deadLetterRepo.Create(ctx, model.NewDeadLetter(task)); err != nil {
+		return fmt.Errorf("create dead letter failed: %w", err)
+	}
+	return queueManager.PushDeadLetter(ctx, task.TaskID)
+}
@@ -0,0 +1,266 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/infrastructure/redis"
+)
+
+// deadLetterFixture 死信测试环境，预置三条死信：
+// task-1 example_task/connection refused，task-2 example_task/invalid payload，task-3 report_task/connection refused
+type deadLetterFixture struct {
+	service      *DeadLetterService
+	taskRepo     repository.TaskRepository
+	taskLogRepo  repository.TaskLogRepository
+	queueManager *redis.QueueManager
+}
+
+func newDeadLetterFixture(t *testing.T) *deadLetterFixture {
+	t.Helper()
+
+	taskService, queueManager, _ := newTestTaskService(t)
+	deadLetterRepo := taskService.deadLetterRepo
+	f := &deadLetterFixture{
+		service:      NewDeadLetterService(deadLetterRepo, taskService.taskRepo, taskService.taskLogRepo, taskService.taskConfigRepo, queueManager),
+		taskRepo:     taskService.taskRepo,
+		taskLogRepo:  taskService.taskLogRepo,
+		queueManager: queueManager,
+	}
+
+	ctx := context.Background()
+	for _, seed := range []struct{ taskID, taskType, errorMsg string }{
+		{"task-1", "example_task", "dial tcp: connection refused"},
+		{"task-2", "example_task", "invalid payload: missing url"},
+		{"task-3", "report_task", "dial tcp: connection refused"},
+	} {
+		task := &model.Task{
+			TaskID:     seed.taskID,
+			TaskType:   seed.taskType,
+			Status:     model.StatusPending,
+			Payload:    map[string]interface{}{"url": ""},
+			MaxRetry:   2,
+			RetryCount: 2,
+		}
+		if err := f.taskRepo.Create(ctx, task); err != nil {
+			t.Fatalf("create task failed: %v", err)
+		}
+		task.MarkAsFailed(seed.errorMsg)
+		if err := moveToDeadLetter(ctx, deadLetterRepo, queueManager, task); err != nil {
+			t.Fatalf("moveToDeadLetter() error = %v", err)
+		}
+	}
+	return f
+}
+
+// deadLetterIDs 返回分页结果中的任务ID
+func deadLetterIDs(page *DeadLetterPage) []string {
+	ids := make([]string, 0, len(page.DeadLetters))
+	for _, deadLetter := range page.DeadLetters {
+		ids = append(ids, deadLetter.TaskID)
+	}
+	return ids
+}
+
+func TestDeadLetterService_ListDeadLetters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query repository.DeadLetterQuery
+		want  []string
+	}{
+		{
+			name: "newest first",
+			want: []string{"task-3", "task-2", "task-1"},
+		},
+		{
+			name:  "filter by task type",
+			query: repository.DeadLetterQuery{TaskType: "example_task"},
+			want:  []string{"task-2", "task-1"},
+		},
+		{
+			name:  "filter by error",
+			query: repository.DeadLetterQuery{ErrorContains: "connection refused"},
+			want:  []string{"task-3", "task-1"},
+		},
+		{
+			name:  "filter by task type and error",
+			query: repository.DeadLetterQuery{TaskType: "report_task", ErrorContains: "invalid"},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDeadLetterFixture(t)
+
+			page, err := f.service.ListDeadLetters(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("ListDeadLetters() error = %v", err)
+			}
+			if got := deadLetterIDs(page); !slices.Equal(got, tt.want) {
+				t.Errorf("ListDeadLetters() = %v, want %v", got, tt.want)
+			}
+			if page.Total != int64(len(tt.want)) {
+				t.Errorf("Total = %d, want %d", page.Total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestDeadLetterService_RequeueDeadLetter(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     map[string]interface{}
+		wantPayload string
+	}{
+		{
+			name:        "keep payload",
+			wantPayload: "",
+		},
+		{
+			name:        "edited payload",
+			payload:     map[string]interface{}{"url": "http://example.com"},
+			wantPayload: "http://example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDeadLetterFixture(t)
+			ctx := context.Background()
+
+			task, err := f.service.RequeueDeadLetter(ctx, "task-1", tt.payload)
+			if err != nil {
+				t.Fatalf("RequeueDeadLetter() error = %v", err)
+			}
+			if task.Status != model.StatusPending || task.RetryCount != 0 || task.ErrorMsg != "" {
+				t.Errorf("task = %s/%d/%q, want PENDING with retries reset", task.Status, task.RetryCount, task.ErrorMsg)
+			}
+			if got, _ := task.Payload["url"].(string); got != tt.wantPayload {
+				t.Errorf("payload url = %q, want %q", got, tt.wantPayload)
+			}
+
+			if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueNormal); n != 1 {
+				t.Errorf("ready queue length = %d, want 1", n)
+			}
+			if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueDead); n != 2 {
+				t.Errorf("dead letter queue length = %d, want 2", n)
+			}
+			if _, _, err := f.service.GetDeadLetter(ctx, "task-1"); !errors.Is(err, ErrNotDeadLettered) {
+				t.Errorf("GetDeadLetter() error = %v, want ErrNotDeadLettered", err)
+			}
+
+			// 不在死信队列中的任务不能重复入队
+			if _, err := f.service.RequeueDeadLetter(ctx, "task-1", nil); !errors.Is(err, ErrNotDeadLettered) {
+				t.Errorf("second RequeueDeadLetter() error = %v, want ErrNotDeadLettered", err)
+			}
+		})
+	}
+}
+
+func TestDeadLetterService_RequeueDeadLetterPayloadSchema(t *testing.T) {
+	f := newDeadLetterFixture(t)
+	ctx := context.Background()
+
+	schema, err := model.ParsePayloadSchema([]byte(`{"required": ["url"], "properties": {"url": {"type": "string", "minLength": 1}}}`))
+	if err != nil {
+		t.Fatalf("ParsePayloadSchema() error = %v", err)
+	}
+	config, err := f.service.taskConfigRepo.GetByType(ctx, "example_task")
+	if err != nil {
+		t.Fatalf("GetByType() error = %v", err)
+	}
+	config.PayloadSchema = schema
+
+	// 修改后的 payload 不满足 schema 时拒绝，任务仍留在死信队列
+	_, err = f.service.RequeueDeadLetter(ctx, "task-1", map[string]interface{}{"url": ""})
+	var validationErr *model.PayloadValidationError
+	if !errors.As(err, &validationErr) || validationErr.Errors[0].Field != "url" {
+		t.Fatalf("RequeueDeadLetter() error = %v, want field error on url", err)
+	}
+	if _, _, err := f.service.GetDeadLetter(ctx, "task-1"); err != nil {
+		t.Errorf("task should stay dead-lettered, GetDeadLetter() error = %v", err)
+	}
+	if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueNormal); n != 0 {
+		t.Errorf("ready queue length = %d, want 0", n)
+	}
+
+	if _, err := f.service.RequeueDeadLetter(ctx, "task-1", map[string]interface{}{"url": "http://example.com"}); err != nil {
+		t.Fatalf("RequeueDeadLetter() error = %v", err)
+	}
+}
+
+func TestDeadLetterService_BulkOperations(t *testing.T) {
+	tests := []struct {
+		name          string
+		requeue       bool
+		query         repository.DeadLetterQuery
+		wantAffected  int64
+		wantRemaining []string
+		wantReady     int64
+	}{
+		{
+			name:          "requeue by error",
+			requeue:       true,
+			query:         repository.DeadLetterQuery{ErrorContains: "connection refused"},
+			wantAffected:  2,
+			wantRemaining: []string{"task-2"},
+			wantReady:     2,
+		},
+		{
+			name:          "purge by task type",
+			query:         repository.DeadLetterQuery{TaskType: "example_task"},
+			wantAffected:  2,
+			wantRemaining: []string{"task-3"},
+		},
+		{
+			name:          "purge by task ids",
+			query:         repository.DeadLetterQuery{TaskIDs: []string{"task-1", "task-3", "missing"}},
+			wantAffected:  2,
+			wantRemaining: []string{"task-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDeadLetterFixture(t)
+			ctx := context.Background()
+
+			var affected int64
+			if tt.requeue {
+				n, err := f.service.RequeueDeadLetters(ctx, tt.query)
+				if err != nil {
+					t.Fatalf("RequeueDeadLetters() error = %v", err)
+				}
+				affected = int64(n)
+			} else {
+				n, err := f.service.PurgeDeadLetters(ctx, tt.query)
+				if err != nil {
+					t.Fatalf("PurgeDeadLetters() error = %v", err)
+				}
+				affected = n
+			}
+			if affected != tt.wantAffected {
+				t.Errorf("affected = %d, want %d", affected, tt.wantAffected)
+			}
+
+			page, err := f.service.ListDeadLetters(ctx, repository.DeadLetterQuery{})
+			if err != nil {
+				t.Fatalf("ListDeadLetters() error = %v", err)
+			}
+			if got := deadLetterIDs(page); !slices.Equal(got, tt.wantRemaining) {
+				t.Errorf("remaining = %v, want %v", got, tt.wantRemaining)
+			}
+			if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueDead); n != int64(len(tt.wantRemaining)) {
+				t.Errorf("dead letter queue length = %d, want %d", n, len(tt.wantRemaining))
+			}
+			if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueNormal); n != tt.wantReady {
+				t.Errorf("ready queue length = %d, want %d", n, tt.wantReady)
+			}
+		})
+	}
+}
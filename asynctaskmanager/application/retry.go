@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/infrastructure/redis"
+)
+
+// retryDelay 根据任务类型的重试策略计算下一次重试的等待时间
+// 配置不可用时返回 0，即立即重试
+func retryDelay(ctx context.Context, configRepo repository.TaskConfigRepository, task *model.Task) time.Duration {
+	config, err := configRepo.GetByType(ctx, task.TaskType)
+	if err != nil {
+		log.Printf("get task config %s failed, retry immediately: %v", task.TaskType, err)
+		return 0
+	}
+	return config.CalculateRetryDelay(task.RetryCount)
+}
+
+// requeueTask 将已结束的任务重置为待处理并推入就绪队列，任务在死信队列中时一并移出
+// 先移除残留的取消标记（例如取消请求送达前任务已失败），否则 Worker 取到任务后会立即取消
+// 工作流节点任务不允许单独重新入队：节点已按任务终态推进，重跑的结果不会再回到工作流
+func requeueTask(
+	ctx context.Context,
+	taskRepo repository.TaskRepository,
+	deadLetterRepo repository.DeadLetterRepository,
+	queueManager *redis.QueueManager,
+	task *model.Task,
+	resetRetries bool,
+) error {
+	if isWorkflowNodeTask(task) {
+		return fmt.Errorf("%w: task %s belongs to a workflow node", ErrTaskNotRetryable, task.TaskID)
+	}
+
+	if err := queueManager.RemoveCancelMark(ctx, task.TaskID); err != nil {
+		return fmt.Errorf("remove cancel mark failed: %w", err)
+	}
+
+	task.Requeue(resetRetries)
+	if err := taskRepo.Update(ctx, task); err != nil {
+		return fmt.Errorf("update task failed: %w", err)
+	}
+
+	if err := queueManager.PushTask(ctx, task); err != nil {
+		return fmt.Errorf("push task to queue failed: %w", err)
+	}
+
+	if _, err := deadLetterRepo.Delete(ctx, []string{task.TaskID}); err != nil {
+		return fmt.Errorf("delete dead letter failed: %w", err)
+	}
+	_ = queueManager.RemoveDeadLetter(ctx, task.TaskID)
+	return nil
+}
@@ -2,8 +2,10 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"time"
 
 	"bamboo/asynctaskmanager/domain/model"
@@ -12,37 +14,64 @@ import (
 	"bamboo/asynctaskmanager/infrastructure/redis"
 )
 
+const (
+	// delayedPromoteBatch 单次移动延迟任务的上限
+	delayedPromoteBatch = 100
+	// dispatchBatchSize 单次从就绪队列取出任务的上限
+	dispatchBatchSize = 100
+	// dispatchBackoff 没有空闲容量或整批任务都没有可用 Worker 时的初始退避时间，连续退避时逐次翻倍
+	dispatchBackoff = 100 * time.Millisecond
+	// dispatchMaxBackoff 连续退避的上限
+	dispatchMaxBackoff = 2 * time.Second
+)
+
 // SchedulerService 调度服务
 type SchedulerService struct {
 	taskRepo         repository.TaskRepository
 	taskLogRepo      repository.TaskLogRepository
+	taskConfigRepo   repository.TaskConfigRepository
+	tenantConfigRepo repository.TenantConfigRepository
+	deadLetterRepo   repository.DeadLetterRepository
 	workerRepo       repository.WorkerRepository
 	leaderElection   *redis.LeaderElection
 	queueManager     *redis.QueueManager
+	limiter          *redis.ConcurrencyLimiter
+	rateLimiter      *redis.RateLimiter
 	loadBalancer     service.LoadBalancer
-	scanInterval     time.Duration
+	dispatchWait     time.Duration
 	heartbeatTimeout time.Duration
 }
 
 // NewSchedulerService 创建调度服务
+// dispatchWait 为就绪队列为空时单次阻塞等待的最长时间
 func NewSchedulerService(
 	taskRepo repository.TaskRepository,
 	taskLogRepo repository.TaskLogRepository,
+	taskConfigRepo repository.TaskConfigRepository,
+	tenantConfigRepo repository.TenantConfigRepository,
+	deadLetterRepo repository.DeadLetterRepository,
 	workerRepo repository.WorkerRepository,
 	leaderElection *redis.LeaderElection,
 	queueManager *redis.QueueManager,
+	limiter *redis.ConcurrencyLimiter,
+	rateLimiter *redis.RateLimiter,
 	loadBalancer service.LoadBalancer,
-	scanInterval time.Duration,
+	dispatchWait time.Duration,
 	heartbeatTimeout time.Duration,
 ) *SchedulerService {
 	return &SchedulerService{
 		taskRepo:         taskRepo,
 		taskLogRepo:      taskLogRepo,
+		taskConfigRepo:   taskConfigRepo,
+		tenantConfigRepo: tenantConfigRepo,
+		deadLetterRepo:   deadLetterRepo,
 		workerRepo:       workerRepo,
 		leaderElection:   leaderElection,
 		queueManager:     queueManager,
+		limiter:          limiter,
+		rateLimiter:      rateLimiter,
 		loadBalancer:     loadBalancer,
-		scanInterval:     scanInterval,
+		dispatchWait:     dispatchWait,
 		heartbeatTimeout: heartbeatTimeout,
 	}
 }
@@ -58,15 +87,20 @@ func (s *SchedulerService) Start(ctx context.Context) error {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
-			acquired, err := s.leaderElection.TryAcquire(ctx)
+			token, acquired, err := s.leaderElection.TryAcquire(ctx)
 			if err != nil {
 				log.Printf("try acquire leader failed: %v", err)
 				continue
 			}
 
 			if acquired {
-				log.Println("became leader, starting schedule loop")
-				return s.runAsLeader(ctx)
+				log.Printf("became leader with fencing token %d, starting schedule loop", token)
+				if err := s.runAsLeader(ctx); err != nil && ctx.Err() == nil {
+					// 失去 Leader 身份后回到跟随者状态，继续参与选举
+					log.Printf("leadership ended: %v", err)
+					continue
+				}
+				return ctx.Err()
 			}
 		}
 	}
@@ -74,13 +108,35 @@ func (s *SchedulerService) Start(ctx context.Context) error {
 
 // runAsLeader 作为 Leader 运行
 func (s *SchedulerService) runAsLeader(ctx context.Context) error {
-	scanTicker := time.NewTicker(s.scanInterval)
 	renewTicker := time.NewTicker(3 * time.Second)
 	timeoutTicker := time.NewTicker(30 * time.Second)
+	delayedTicker := time.NewTicker(1 * time.Second)
+	reapTicker := time.NewTicker(10 * time.Second)
 
-	defer scanTicker.Stop()
 	defer renewTicker.Stop()
 	defer timeoutTicker.Stop()
+	defer delayedTicker.Stop()
+	defer reapTicker.Stop()
+
+	if err := s.queueManager.KeepAlive(ctx, s.leaderElection.SchedulerID()); err != nil {
+		log.Printf("keep consumer alive failed: %v", err)
+	}
+	if err := s.syncTenantQuanta(ctx); err != nil {
+		log.Printf("sync tenant quanta failed: %v", err)
+	}
+
+	// 分发循环独立运行，任期结束时等待其退出，避免与下一任期重叠
+	dispatchCtx, stopDispatch := context.WithCancel(ctx)
+	dispatchErr := make(chan error, 1)
+	dispatchDone := make(chan struct{})
+	go func() {
+		defer close(dispatchDone)
+		dispatchErr <- s.dispatchLoop(dispatchCtx)
+	}()
+	defer func() {
+		stopDispatch()
+		<-dispatchDone
+	}()
 
 	for {
 		select {
@@ -88,17 +144,18 @@ func (s *SchedulerService) runAsLeader(ctx context.Context) error {
 			_ = s.leaderElection.Release(ctx)
 			return ctx.Err()
 
+		case err := <-dispatchErr:
+			// 已有新的 Leader，停止调度
+			return fmt.Errorf("lost leadership: %w", err)
+
 		case <-renewTicker.C:
 			// 续约 Leader 锁
 			if err := s.leaderElection.Renew(ctx); err != nil {
 				log.Printf("renew leader lock failed: %v", err)
 				return fmt.Errorf("lost leadership")
 			}
-
-		case <-scanTicker.C:
-			// 扫描并调度任务
-			if err := s.scanAndSchedule(ctx); err != nil {
-				log.Printf("scan and schedule failed: %v", err)
+			if err := s.queueManager.KeepAlive(ctx, s.leaderElection.SchedulerID()); err != nil {
+				log.Printf("keep consumer alive failed: %v", err)
 			}
 
 		case <-timeoutTicker.C:
@@ -106,37 +163,178 @@ func (s *SchedulerService) runAsLeader(ctx context.Context) error {
 			if err := s.checkTimeoutTasks(ctx); err != nil {
 				log.Printf("check timeout tasks failed: %v", err)
 			}
+
+		case <-delayedTicker.C:
+			// 移动到期的延迟任务
+			if err := s.promoteDelayedTasks(ctx); err != nil {
+				log.Printf("promote delayed tasks failed: %v", err)
+			}
+
+		case <-reapTicker.C:
+			// 回收失效 Worker 的任务
+			if err := s.recoverExpiredWorkers(ctx); err != nil {
+				log.Printf("recover expired workers failed: %v", err)
+			}
+
+			// 回收失效消费者未确认的任务
+			if n, err := s.queueManager.ReapDeadConsumers(ctx); err != nil {
+				log.Printf("reap dead consumers failed: %v", err)
+			} else if n > 0 {
+				log.Printf("requeued %d unacked tasks from dead consumers", n)
+			}
+
+			// 校正并发槽位，放回可调度的暂存任务
+			if err := s.reconcileConcurrency(ctx); err != nil {
+				log.Printf("reconcile concurrency slots failed: %v", err)
+			}
+
+			// 同步租户调度配额，使配置变更生效
+			if err := s.syncTenantQuanta(ctx); err != nil {
+				log.Printf("sync tenant quanta failed: %v", err)
+			}
+		}
+	}
+}
+
+// dispatchLoop 阻塞消费就绪队列并批量分配任务，直到 ctx 结束或因 fencing token 过期失去 Leader 身份
+// 取出任务前先检查 Worker 的空闲容量，单批取出的任务数不超过空闲容量；没有空闲容量时不取任务，按指数退避等待
+func (s *SchedulerService) dispatchLoop(ctx context.Context) error {
+	consumerID := s.leaderElection.SchedulerID()
+
+	var backoff time.Duration
+	for ctx.Err() == nil {
+		free, err := s.freeCapacity(ctx)
+		if err != nil {
+			log.Printf("check worker capacity failed: %v", err)
+		}
+		if free == 0 {
+			backoff = nextBackoff(backoff)
+			sleepContext(ctx, backoff)
+			continue
+		}
+
+		taskIDs, err := s.queueManager.WaitTasks(ctx, consumerID, min(free, dispatchBatchSize), s.dispatchWait)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("wait ready tasks failed: %v", err)
+			}
+			sleepContext(ctx, dispatchBackoff)
+			continue
+		}
+		if len(taskIDs) == 0 {
+			continue
+		}
+
+		deferred, err := s.scheduleBatch(ctx, taskIDs)
+		if err != nil {
+			return err
+		}
+
+		// 整批都没有可用 Worker 时退避，避免反复取出放回的任务
+		if deferred == len(taskIDs) {
+			backoff = nextBackoff(backoff)
+			sleepContext(ctx, backoff)
+			continue
 		}
+		backoff = 0
 	}
+
+	return ctx.Err()
 }
 
-// scanAndSchedule 扫描并调度任务
-func (s *SchedulerService) scanAndSchedule(ctx context.Context) error {
-	// 从队列获取任务
-	taskID, err := s.queueManager.PopTask(ctx)
+// freeCapacity 健康 Worker 的空闲容量之和
+func (s *SchedulerService) freeCapacity(ctx context.Context) (int, error) {
+	workers, err := s.workerRepo.FindHealthy(ctx, s.heartbeatTimeout)
 	if err != nil {
-		return nil // 队列为空
+		return 0, fmt.Errorf("find healthy workers failed: %w", err)
+	}
+
+	free := 0
+	for _, worker := range workers {
+		if worker.CanAcceptTask() {
+			free += worker.Capacity - worker.CurrentLoad
+		}
+	}
+	return free, nil
+}
+
+// nextBackoff 连续退避时的下一次退避时间，从 dispatchBackoff 开始翻倍，不超过 dispatchMaxBackoff
+func nextBackoff(backoff time.Duration) time.Duration {
+	if backoff == 0 {
+		return dispatchBackoff
+	}
+	return min(backoff*2, dispatchMaxBackoff)
+}
+
+// dispatchCache 单个批次内按任务类型缓存的 Worker 列表与任务配置，以及按租户缓存的租户配置
+type dispatchCache struct {
+	workers map[string][]*model.Worker
+	configs map[string]*model.TaskConfig
+	tenants map[string]*model.TenantConfig
+}
+
+// scheduleBatch 分配一批任务，返回因暂无可用 Worker 被放回队列的任务数
+// 同一批次内按任务类型复用 Worker 列表，并在本地累加负载以遵守容量限制
+// 被放回的任务按取出顺序回到各自就绪队列的出队端，不打乱先进先出顺序
+// 仅在 fencing token 过期时返回错误
+func (s *SchedulerService) scheduleBatch(ctx context.Context, taskIDs []string) (int, error) {
+	consumerID := s.leaderElection.SchedulerID()
+	cache := &dispatchCache{
+		workers: make(map[string][]*model.Worker),
+		configs: make(map[string]*model.TaskConfig),
+		tenants: make(map[string]*model.TenantConfig),
+	}
+
+	var deferred []*model.Task
+	var staleErr error
+	for _, taskID := range taskIDs {
+		task, err := s.scheduleTask(ctx, taskID, cache)
+
+		// 分配、暂存或跳过均视为处理完成，需要放回的任务批次结束后统一放回
+		if task != nil {
+			deferred = append(deferred, task)
+		} else if err := s.queueManager.AckTask(ctx, consumerID, taskID); err != nil {
+			log.Printf("ack task failed: %v", err)
+		}
+
+		if err != nil {
+			if errors.Is(err, repository.ErrStaleFencingToken) {
+				staleErr = err
+				continue
+			}
+			log.Printf("schedule task %s failed: %v", taskID, err)
+		}
 	}
 
+	if err := s.queueManager.ReturnTasks(ctx, consumerID, deferred); err != nil {
+		log.Printf("return deferred tasks failed: %v", err)
+	}
+
+	return len(deferred), staleErr
+}
+
+// scheduleTask 将任务分配给 Worker，任务因暂无可用 Worker 需要放回就绪队列时返回该任务，由调用方放回
+// 租户或任务类型已达并发上限时暂存任务，等待同租户或同类型任务结束后再调度，不影响其他租户与类型
+func (s *SchedulerService) scheduleTask(ctx context.Context, taskID string, cache *dispatchCache) (*model.Task, error) {
 	// 获取任务详情
 	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
-		log.Printf("get task failed: %v", err)
-		return err
+		return nil, fmt.Errorf("get task failed: %w", err)
 	}
 
 	// 检查任务状态
 	if task.Status != model.StatusPending {
-		return nil
+		return nil, nil
 	}
 
 	// 获取支持该任务类型的 Worker
-	workers, err := s.workerRepo.FindByTaskType(ctx, task.TaskType)
-	if err != nil {
-		log.Printf("find workers failed: %v", err)
-		// 重新放回队列
-		_ = s.queueManager.PushTask(ctx, taskID, task.Priority)
-		return err
+	workers, ok := cache.workers[task.TaskType]
+	if !ok {
+		workers, err = s.workerRepo.FindByTaskType(ctx, task.TaskType)
+		if err != nil {
+			return task, fmt.Errorf("find workers failed: %w", err)
+		}
+		cache.workers[task.TaskType] = workers
 	}
 
 	// 过滤健康的 Worker
@@ -148,37 +346,60 @@ func (s *SchedulerService) scanAndSchedule(ctx context.Context) error {
 	}
 
 	if len(healthyWorkers) == 0 {
-		log.Printf("no available workers for task %s", taskID)
-		// 重新放回队列
-		_ = s.queueManager.PushTask(ctx, taskID, task.Priority)
-		return nil
+		return task, nil
 	}
 
 	// 负载均衡选择 Worker
 	worker, err := s.loadBalancer.Select(healthyWorkers, taskID)
 	if err != nil {
-		log.Printf("select worker failed: %v", err)
-		// 重新放回队列
-		_ = s.queueManager.PushTask(ctx, taskID, task.Priority)
-		return err
+		return task, fmt.Errorf("select worker failed: %w", err)
 	}
 
-	// 更新任务状态
+	config := s.taskConfig(ctx, task.TaskType, cache)
+
+	// 按任务类型与租户限流，令牌不足时按预约时间延迟分发
+	throttled, err := s.throttle(ctx, task, config, s.tenantConfig(ctx, task.Tenant, cache))
+	if err != nil {
+		return task, err
+	}
+	if throttled {
+		return nil, nil
+	}
+
+	// 依次占用租户与任务类型的并发槽位，已达上限时暂存
+	acquired, err := s.acquireSlots(ctx, task, config, cache)
+	if err != nil {
+		return task, err
+	}
+	if !acquired {
+		return nil, nil
+	}
+
+	// 更新任务状态，携带本任期 token，过期 Leader 的分配会被拒绝
 	task.MarkAsProcessing(worker.WorkerID)
+	task.FencingToken = s.leaderElection.Token()
 	if err := s.taskRepo.Update(ctx, task); err != nil {
-		log.Printf("update task failed: %v", err)
-		return err
+		// 放回队列交由当前 Leader 处理
+		_ = s.limiter.ReleaseTask(ctx, task)
+		_ = s.queueManager.PushTask(ctx, task)
+		return nil, fmt.Errorf("update task failed: %w", err)
 	}
 
 	// 分配任务给 Worker
 	if err := s.queueManager.PushToWorkerQueue(ctx, worker.WorkerID, taskID); err != nil {
-		log.Printf("push to worker queue failed: %v", err)
-		return err
+		// 撤销分配并放回队列，否则任务停留在 PROCESSING 且占用并发槽位直到超时
+		_ = s.limiter.ReleaseTask(ctx, task)
+		task.Unassign()
+		if updateErr := s.taskRepo.Update(ctx, task); updateErr != nil {
+			return nil, fmt.Errorf("push to worker queue failed: %w, reset task failed: %w", err, updateErr)
+		}
+		_ = s.queueManager.PushTask(ctx, task)
+		return nil, fmt.Errorf("push to worker queue failed: %w", err)
 	}
 
-	// 更新 Worker 负载
+	// 更新 Worker 负载，Worker 处理结束时扣减
 	worker.AcceptTask()
-	if err := s.workerRepo.UpdateLoad(ctx, worker.WorkerID, worker.CurrentLoad); err != nil {
+	if err := s.workerRepo.IncrLoad(ctx, worker.WorkerID, 1); err != nil {
 		log.Printf("update worker load failed: %v", err)
 	}
 
@@ -194,6 +415,306 @@ func (s *SchedulerService) scanAndSchedule(ctx context.Context) error {
 
 	log.Printf("scheduled task %s to worker %s", taskID, worker.WorkerID)
 
+	return nil, nil
+}
+
+// taskConfig 查询任务类型的配置并缓存到本批次，不存在时返回 nil
+func (s *SchedulerService) taskConfig(ctx context.Context, taskType string, cache *dispatchCache) *model.TaskConfig {
+	config, ok := cache.configs[taskType]
+	if !ok {
+		config, _ = s.taskConfigRepo.GetByType(ctx, taskType)
+		cache.configs[taskType] = config
+	}
+	return config
+}
+
+// tenantConfig 查询租户的配置并缓存到本批次，不存在时返回 nil
+func (s *SchedulerService) tenantConfig(ctx context.Context, tenant string, cache *dispatchCache) *model.TenantConfig {
+	config, ok := cache.tenants[tenant]
+	if !ok {
+		config, _ = s.tenantConfigRepo.GetByTenant(ctx, tenant)
+		cache.tenants[tenant] = config
+	}
+	return config
+}
+
+// acquireSlots 依次占用租户与任务类型的并发槽位，返回是否全部占用成功
+// 未能占用时任务已暂存到已满的并发范围；任务类型已满时先释放刚占用的租户槽位
+// 出错时已占用的槽位均已释放
+func (s *SchedulerService) acquireSlots(ctx context.Context, task *model.Task, config *model.TaskConfig, cache *dispatchCache) (bool, error) {
+	tenantScope := redis.TenantConcurrencyScope(task.Tenant)
+	acquired, err := s.limiter.Acquire(ctx, tenantScope, task.TaskID, maxInFlight(s.tenantConfig(ctx, task.Tenant, cache)))
+	if err != nil {
+		return false, err
+	}
+	if !acquired {
+		return false, s.limiter.Park(ctx, tenantScope, task)
+	}
+
+	typeScope := redis.TaskTypeConcurrencyScope(task.TaskType)
+	acquired, err = s.limiter.Acquire(ctx, typeScope, task.TaskID, maxConcurrent(config))
+	if err == nil && !acquired {
+		err = s.limiter.Park(ctx, typeScope, task)
+	}
+	if err != nil || !acquired {
+		_ = s.limiter.Release(ctx, tenantScope, task.TaskID)
+	}
+	return acquired && err == nil, err
+}
+
+// throttle 依次从任务类型与租户的令牌桶取令牌，任一令牌不足时将任务放入延迟队列并记录限流日志
+// 租户令牌不足时归还已取得的任务类型令牌，被租户限流的任务不占用任务类型的配额
+// 返回任务是否被限流；出错时任务未被处理，由调用方放回就绪队列
+func (s *SchedulerService) throttle(ctx context.Context, task *model.Task, config *model.TaskConfig, tenantConfig *model.TenantConfig) (bool, error) {
+	typeLimited := config != nil && config.RateLimit > 0
+	if typeLimited {
+		wait, err := s.rateLimiter.Reserve(ctx, redis.TaskTypeRateScope(task.TaskType), task.TaskID, config.RateLimit, config.RateBurst)
+		if err != nil {
+			return false, err
+		}
+		if wait > 0 {
+			limit := fmt.Sprintf("%s rate limit (%g/s, burst %d)", task.TaskType, config.RateLimit, config.RateBurst)
+			return s.delayThrottled(ctx, task, limit, wait)
+		}
+	}
+
+	if tenantConfig == nil || tenantConfig.RateLimit <= 0 {
+		return false, nil
+	}
+
+	wait, err := s.rateLimiter.Reserve(ctx, redis.TenantRateScope(task.Tenant), task.TaskID, tenantConfig.RateLimit, tenantConfig.RateBurst)
+	if err == nil && wait <= 0 {
+		return false, nil
+	}
+	if typeLimited {
+		if refundErr := s.rateLimiter.Refund(ctx, redis.TaskTypeRateScope(task.TaskType), config.RateBurst); refundErr != nil {
+			log.Printf("refund rate limit token of task type %s failed: %v", task.TaskType, refundErr)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+
+	limit := fmt.Sprintf("tenant %s rate limit (%g/s, burst %d)", task.Tenant, tenantConfig.RateLimit, tenantConfig.RateBurst)
+	return s.delayThrottled(ctx, task, limit, wait)
+}
+
+// delayThrottled 将被限流的任务按等待时间放入延迟队列并记录限流日志，返回任务是否已延迟
+func (s *SchedulerService) delayThrottled(ctx context.Context, task *model.Task, limit string, wait time.Duration) (bool, error) {
+	if err := s.queueManager.PushDelayedTask(ctx, task, time.Now().Add(wait)); err != nil {
+		return false, err
+	}
+
+	logEntry := model.NewInfoLog(
+		task.TaskID,
+		fmt.Sprintf("Task throttled by %s, dispatch delayed %s", limit, wait),
+	)
+	_ = s.taskLogRepo.Create(ctx, logEntry)
+	return true, nil
+}
+
+// maxConcurrent 任务类型的并发上限，未配置时不限制
+func maxConcurrent(config *model.TaskConfig) int {
+	if config == nil {
+		return 0
+	}
+	return config.MaxConcurrent
+}
+
+// maxInFlight 租户的并发上限，未配置时不限制
+func maxInFlight(config *model.TenantConfig) int {
+	if config == nil {
+		return 0
+	}
+	return config.MaxInFlight
+}
+
+// sleepContext 等待 d 或 ctx 结束
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// promoteDelayedTasks 将到期的延迟任务移入就绪队列
+func (s *SchedulerService) promoteDelayedTasks(ctx context.Context) error {
+	for {
+		n, err := s.queueManager.PromoteDueTasks(ctx, time.Now(), delayedPromoteBatch)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			log.Printf("promoted %d delayed tasks", n)
+		}
+		if n < delayedPromoteBatch {
+			return nil
+		}
+	}
+}
+
+// reconcileConcurrency 释放已不在执行中的任务占用的槽位，并按剩余槽位放回暂存任务
+// 用于兜底 Worker 崩溃或释放失败导致的槽位泄漏
+func (s *SchedulerService) reconcileConcurrency(ctx context.Context) error {
+	scopes, err := s.limiter.Types(ctx)
+	if err != nil {
+		return fmt.Errorf("list concurrency types failed: %w", err)
+	}
+
+	for _, scope := range scopes {
+		running, err := s.limiter.Running(ctx, scope)
+		if err != nil {
+			log.Printf("list running tasks of %s failed: %v", scope, err)
+			continue
+		}
+
+		inUse := 0
+		for _, taskID := range running {
+			task, err := s.taskRepo.GetByID(ctx, taskID)
+			if err == nil && task.Status == model.StatusProcessing {
+				inUse++
+				continue
+			}
+			if err := s.limiter.Release(ctx, scope, taskID); err != nil {
+				log.Printf("release stale slot of task %s failed: %v", taskID, err)
+			}
+		}
+
+		// 上限被调低或取消时按最新配置放回
+		free := math.MaxInt32
+		if limit := s.concurrencyLimit(ctx, scope); limit > 0 {
+			free = limit - inUse
+		}
+		if free <= 0 {
+			continue
+		}
+		if _, err := s.limiter.Unpark(ctx, scope, free); err != nil {
+			log.Printf("unpark %s tasks failed: %v", scope, err)
+		}
+	}
+
+	return nil
+}
+
+// concurrencyLimit 并发范围当前配置的上限，未配置时返回 0
+func (s *SchedulerService) concurrencyLimit(ctx context.Context, scope string) int {
+	if tenant, ok := redis.ParseTenantConcurrencyScope(scope); ok {
+		config, err := s.tenantConfigRepo.GetByTenant(ctx, tenant)
+		if err != nil {
+			return 0
+		}
+		return config.MaxInFlight
+	}
+
+	config, err := s.taskConfigRepo.GetByType(ctx, scope)
+	if err != nil {
+		return 0
+	}
+	return config.MaxConcurrent
+}
+
+// syncTenantQuanta 将租户配置中的调度配额同步到 Redis，供出队时按租户轮询
+func (s *SchedulerService) syncTenantQuanta(ctx context.Context) error {
+	configs, err := s.tenantConfigRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("find tenant configs failed: %w", err)
+	}
+
+	quanta := make(map[string]int, len(configs))
+	for _, config := range configs {
+		quanta[config.Tenant] = config.EffectiveQuantum()
+	}
+	return s.queueManager.SetTenantQuanta(ctx, quanta)
+}
+
+// recoverExpiredWorkers 将心跳过期 Worker 的任务转移回全局优先级队列
+func (s *SchedulerService) recoverExpiredWorkers(ctx context.Context) error {
+	workerIDs, err := s.workerRepo.FindExpired(ctx, s.heartbeatTimeout)
+	if err != nil {
+		return fmt.Errorf("find expired workers failed: %w", err)
+	}
+
+	for _, workerID := range workerIDs {
+		if err := s.failoverWorker(ctx, workerID); err != nil {
+			log.Printf("failover worker %s failed: %v", workerID, err)
+			continue
+		}
+		if err := s.workerRepo.Purge(ctx, workerID); err != nil {
+			log.Printf("purge worker %s failed: %v", workerID, err)
+		}
+	}
+
+	return nil
+}
+
+// failoverWorker 清空 Worker 队列，并将其处理中的任务按失败处理：可以重试时重置后重新入队，重试次数耗尽时进入死信队列
+func (s *SchedulerService) failoverWorker(ctx context.Context, workerID string) error {
+	// 先清空 Redis 中的 Worker 队列，避免 Worker 恢复后重复执行
+	if _, err := s.queueManager.DrainWorkerQueue(ctx, workerID); err != nil {
+		return err
+	}
+
+	tasks, err := s.taskRepo.FindProcessingTasks(ctx)
+	if err != nil {
+		return fmt.Errorf("find processing tasks failed: %w", err)
+	}
+
+	recovered, deadLettered := 0, 0
+	for _, task := range tasks {
+		if task.WorkerID != workerID {
+			continue
+		}
+
+		task.MarkAsFailed(fmt.Sprintf("Worker %s heartbeat expired", workerID))
+		retry := task.CanRetry()
+		if retry {
+			task.MarkAsRetrying()
+		}
+		task.FencingToken = s.leaderElection.Token()
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			log.Printf("reset task %s failed: %v", task.TaskID, err)
+			continue
+		}
+		if err := s.limiter.ReleaseTask(ctx, task); err != nil {
+			log.Printf("release concurrency slot of task %s failed: %v", task.TaskID, err)
+		}
+
+		if !retry {
+			// 达到最大重试次数
+			logEntry := model.NewErrorLog(
+				task.TaskID,
+				workerID,
+				"Worker heartbeat expired and max retry reached",
+				task.ErrorMsg,
+			).WithTransition(model.StatusProcessing, model.StatusFailed)
+			_ = s.taskLogRepo.Create(ctx, logEntry)
+
+			if err := moveToDeadLetter(ctx, s.deadLetterRepo, s.queueManager, task); err != nil {
+				log.Printf("move task %s to dead letter queue failed: %v", task.TaskID, err)
+			}
+			deadLettered++
+			continue
+		}
+
+		if err := s.queueManager.PushTask(ctx, task); err != nil {
+			log.Printf("requeue task %s failed: %v", task.TaskID, err)
+		}
+
+		logEntry := model.NewStateChangeLog(
+			task.TaskID,
+			model.StatusProcessing,
+			model.StatusPending,
+			workerID,
+			fmt.Sprintf("Worker %s heartbeat expired, task requeued for failover (retry %d/%d)", workerID, task.RetryCount, task.MaxRetry),
+		)
+		_ = s.taskLogRepo.Create(ctx, logEntry)
+		recovered++
+	}
+
+	log.Printf("worker %s expired, %d tasks recovered, %d tasks moved to dead letter queue", workerID, recovered, deadLettered)
 	return nil
 }
 
@@ -209,17 +730,20 @@ func (s *SchedulerService) checkTimeoutTasks(ctx context.Context) error {
 
 		// 标记为超时
 		task.MarkAsTimeout()
+		task.FencingToken = s.leaderElection.Token()
 
 		// 判断是否需要重试
 		if task.CanRetry() {
-			task.MarkAsRetrying()
+			// 按重试策略计算退避时间，放入延迟队列等待到期
+			delay := retryDelay(ctx, s.taskConfigRepo, task)
+			task.ScheduleRetry(time.Now().Add(delay))
 			if err := s.taskRepo.Update(ctx, task); err != nil {
 				log.Printf("update timeout task failed: %v", err)
 				continue
 			}
 
 			// 重新推送到队列
-			if err := s.queueManager.PushTask(ctx, task.TaskID, task.Priority); err != nil {
+			if err := s.queueManager.PushDelayedTask(ctx, task, task.ScheduledAt); err != nil {
 				log.Printf("push timeout task to queue failed: %v", err)
 			}
 
@@ -227,8 +751,8 @@ func (s *SchedulerService) checkTimeoutTasks(ctx context.Context) error {
 			logEntry := model.NewRetryLog(
 				task.TaskID,
 				task.RetryCount,
-				fmt.Sprintf("Task timeout, retry %d/%d", task.RetryCount, task.MaxRetry),
-			)
+				fmt.Sprintf("Task timeout, retry %d/%d in %s", task.RetryCount, task.MaxRetry, delay),
+			).WithTransition(model.StatusProcessing, model.StatusPending)
 			_ = s.taskLogRepo.Create(ctx, logEntry)
 		} else {
 			// 达到最大重试次数
@@ -242,8 +766,17 @@ func (s *SchedulerService) checkTimeoutTasks(ctx context.Context) error {
 				task.WorkerID,
 				"Task timeout and max retry reached",
 				"",
-			)
+			).WithTransition(model.StatusProcessing, model.StatusTimeout)
 			_ = s.taskLogRepo.Create(ctx, logEntry)
+
+			if err := moveToDeadLetter(ctx, s.deadLetterRepo, s.queueManager, task); err != nil {
+				log.Printf("move task %s to dead letter queue failed: %v", task.TaskID, err)
+			}
+		}
+
+		// 任务已离开 PROCESSING，释放并发槽位
+		if err := s.limiter.ReleaseTask(ctx, task); err != nil {
+			log.Printf("release concurrency slot of task %s failed: %v", task.TaskID, err)
 		}
 	}
 
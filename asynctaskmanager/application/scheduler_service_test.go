@@ -0,0 +1,579 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/domain/service"
+	"bamboo/asynctaskmanager/infrastructure/memory"
+	"bamboo/asynctaskmanager/infrastructure/redis"
+)
+
+// schedulerFixture 调度器测试环境，调度器已成为 Leader
+type schedulerFixture struct {
+	mr               *miniredis.Miniredis
+	taskRepo         repository.TaskRepository
+	taskLogRepo      repository.TaskLogRepository
+	taskConfigRepo   repository.TaskConfigRepository
+	tenantConfigRepo repository.TenantConfigRepository
+	deadLetterRepo   repository.DeadLetterRepository
+	workerRepo       repository.WorkerRepository
+	queueManager     *redis.QueueManager
+	limiter          *redis.ConcurrencyLimiter
+	scheduler        *SchedulerService
+}
+
+func newSchedulerFixture(tb testing.TB, workerCapacity int) *schedulerFixture {
+	tb.Helper()
+
+	mr := miniredis.RunT(tb)
+	client := redis.NewClient(mr.Addr(), "", 0)
+	tb.Cleanup(func() { _ = client.Close() })
+
+	f := &schedulerFixture{
+		mr:               mr,
+		taskRepo:         memory.NewTaskRepository(),
+		taskLogRepo:      memory.NewTaskLogRepository(),
+		taskConfigRepo:   memory.NewTaskConfigRepository(),
+		tenantConfigRepo: memory.NewTenantConfigRepository(),
+		deadLetterRepo:   memory.NewDeadLetterRepository(),
+		workerRepo:       redis.NewWorkerRepository(client),
+		queueManager:     redis.NewQueueManager(client),
+		limiter:          redis.NewConcurrencyLimiter(client),
+	}
+
+	ctx := context.Background()
+	leaderElection := redis.NewLeaderElection(client, "scheduler-1")
+	if _, acquired, err := leaderElection.TryAcquire(ctx); err != nil || !acquired {
+		tb.Fatalf("TryAcquire() = %v, %v", acquired, err)
+	}
+
+	err := f.workerRepo.Register(ctx, &model.Worker{
+		WorkerID:       "worker-1",
+		Status:         model.WorkerOnline,
+		Capacity:       workerCapacity,
+		SupportedTypes: []string{"example_task", "report_task"},
+		LastHeartbeat:  time.Now(),
+	})
+	if err != nil {
+		tb.Fatalf("register worker failed: %v", err)
+	}
+
+	f.scheduler = NewSchedulerService(
+		f.taskRepo,
+		f.taskLogRepo,
+		f.taskConfigRepo,
+		f.tenantConfigRepo,
+		f.deadLetterRepo,
+		f.workerRepo,
+		leaderElection,
+		f.queueManager,
+		f.limiter,
+		redis.NewRateLimiter(client),
+		service.NewLeastTaskLoadBalancer(),
+		time.Second,
+		30*time.Second,
+	)
+	return f
+}
+
+// submitTasks 创建 PENDING 任务并推入就绪队列
+func (f *schedulerFixture) submitTasks(tb testing.TB, n int) []string {
+	tb.Helper()
+	return f.submitTypedTasks(tb, "example_task", n)
+}
+
+// submitTypedTasks 创建指定类型的 PENDING 任务并推入就绪队列
+func (f *schedulerFixture) submitTypedTasks(tb testing.TB, taskType string, n int) []string {
+	tb.Helper()
+	return f.submitTenantTasks(tb, model.DefaultTenant, taskType, n)
+}
+
+// submitTenantTasks 创建指定租户与类型的 PENDING 任务并推入租户的就绪队列
+func (f *schedulerFixture) submitTenantTasks(tb testing.TB, tenant, taskType string, n int) []string {
+	tb.Helper()
+
+	ctx := context.Background()
+	taskIDs := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		task := &model.Task{
+			TaskID:   fmt.Sprintf("%s-%s-%d", tenant, taskType, i),
+			TaskType: taskType,
+			Priority: model.PriorityNormal,
+			Status:   model.StatusPending,
+			Tenant:   tenant,
+		}
+		if err := f.taskRepo.Create(ctx, task); err != nil {
+			tb.Fatalf("create task failed: %v", err)
+		}
+		if err := f.queueManager.PushTask(ctx, task); err != nil {
+			tb.Fatalf("push task failed: %v", err)
+		}
+		taskIDs = append(taskIDs, task.TaskID)
+	}
+	return taskIDs
+}
+
+// assignedCount Worker 队列中已分配的任务数
+func (f *schedulerFixture) assignedCount(tb testing.TB) int64 {
+	tb.Helper()
+
+	n, err := f.queueManager.GetQueueLength(context.Background(), "worker:worker-1:queue")
+	if err != nil {
+		tb.Fatalf("get worker queue length failed: %v", err)
+	}
+	return n
+}
+
+func TestSchedulerService_DispatchLoop(t *testing.T) {
+	f := newSchedulerFixture(t, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	f.submitTasks(t, 5)
+
+	done := make(chan error, 1)
+	go func() { done <- f.scheduler.dispatchLoop(ctx) }()
+
+	// 同一批次内遵守 Worker 容量，其余任务留在就绪队列
+	waitFor(t, func() bool { return f.assignedCount(t) == 3 })
+	time.Sleep(300 * time.Millisecond)
+	if n := f.assignedCount(t); n != 3 {
+		t.Errorf("assigned = %d, want 3", n)
+	}
+	// 放回的任务在退避期间停留在就绪队列
+	waitFor(t, func() bool {
+		n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueNormal)
+		return n == 2
+	})
+
+	// Worker 释放负载后继续分配
+	if err := f.workerRepo.IncrLoad(ctx, "worker-1", -3); err != nil {
+		t.Fatalf("IncrLoad() error = %v", err)
+	}
+	waitFor(t, func() bool { return f.assignedCount(t) == 5 })
+
+	if n, _ := f.queueManager.GetProcessingLength(ctx, "scheduler-1"); n != 0 {
+		t.Errorf("scheduler processing length = %d, want 0", n)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("dispatchLoop() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSchedulerService_DispatchLoop_NoCapacity(t *testing.T) {
+	f := newSchedulerFixture(t, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := f.workerRepo.IncrLoad(ctx, "worker-1", 2); err != nil {
+		t.Fatalf("IncrLoad() error = %v", err)
+	}
+	taskIDs := f.submitTasks(t, 3)
+
+	done := make(chan error, 1)
+	go func() { done <- f.scheduler.dispatchLoop(ctx) }()
+
+	// 没有空闲容量时不取出任务
+	time.Sleep(300 * time.Millisecond)
+	if n, _ := f.queueManager.GetProcessingLength(ctx, "scheduler-1"); n != 0 {
+		t.Errorf("scheduler processing length = %d, want 0", n)
+	}
+	if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueNormal); n != 3 {
+		t.Errorf("ready queue length = %d, want 3", n)
+	}
+
+	// 释放容量后按先进先出分配
+	if err := f.workerRepo.IncrLoad(ctx, "worker-1", -2); err != nil {
+		t.Fatalf("IncrLoad() error = %v", err)
+	}
+	waitFor(t, func() bool { return f.assignedCount(t) == 2 })
+	assigned, _ := f.mr.List("worker:worker-1:queue")
+	if !slices.Equal(assigned, []string{taskIDs[1], taskIDs[0]}) {
+		t.Errorf("worker queue = %v, want [%s %s]", assigned, taskIDs[1], taskIDs[0])
+	}
+
+	cancel()
+	<-done
+}
+
+func TestSchedulerService_ScheduleBatch_ReturnsDeferredToHead(t *testing.T) {
+	f := newSchedulerFixture(t, 10)
+	ctx := context.Background()
+
+	// Worker 不支持 other_task，该类型的任务被放回
+	deferredIDs := f.submitTypedTasks(t, "other_task", 2)
+	assignedIDs := f.submitTasks(t, 1)
+
+	taskIDs, err := f.queueManager.WaitTasks(ctx, "scheduler-1", dispatchBatchSize, time.Second)
+	if err != nil || len(taskIDs) != 3 {
+		t.Fatalf("WaitTasks() = %v, %v", taskIDs, err)
+	}
+	deferred, err := f.scheduler.scheduleBatch(ctx, taskIDs)
+	if err != nil {
+		t.Fatalf("scheduleBatch() error = %v", err)
+	}
+	if deferred != 2 {
+		t.Errorf("deferred = %d, want 2", deferred)
+	}
+
+	if n, _ := f.queueManager.GetProcessingLength(ctx, "scheduler-1"); n != 0 {
+		t.Errorf("scheduler processing length = %d, want 0", n)
+	}
+	if got, _ := f.mr.List("worker:worker-1:queue"); !slices.Equal(got, assignedIDs) {
+		t.Errorf("worker queue = %v, want %v", got, assignedIDs)
+	}
+
+	// 放回的任务回到队首且保持原顺序
+	for _, want := range deferredIDs {
+		got, err := f.queueManager.ReserveTask(ctx, "scheduler-1")
+		if err != nil {
+			t.Fatalf("ReserveTask() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("ReserveTask() = %s, want %s", got, want)
+		}
+	}
+}
+
+func TestSchedulerService_MaxConcurrent(t *testing.T) {
+	f := newSchedulerFixture(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := f.taskConfigRepo.Create(ctx, &model.TaskConfig{
+		TaskType:      "example_task",
+		ExecutorType:  model.ExecutorTypeLocal,
+		MaxConcurrent: 2,
+		Enabled:       true,
+	})
+	if err != nil {
+		t.Fatalf("create task config failed: %v", err)
+	}
+
+	f.submitTasks(t, 4)
+	f.submitTypedTasks(t, "report_task", 3)
+
+	done := make(chan error, 1)
+	go func() { done <- f.scheduler.dispatchLoop(ctx) }()
+
+	// 超出上限的任务暂存，不阻塞其他类型
+	waitFor(t, func() bool { return f.assignedCount(t) == 5 })
+	time.Sleep(300 * time.Millisecond)
+	if n := f.assignedCount(t); n != 5 {
+		t.Errorf("assigned = %d, want 5", n)
+	}
+	running, _ := f.limiter.Running(ctx, "example_task")
+	if len(running) != 2 {
+		t.Fatalf("running example_task = %v, want 2", running)
+	}
+
+	// 释放一个槽位后暂存任务重新调度
+	if err := f.limiter.Release(ctx, "example_task", running[0]); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	waitFor(t, func() bool { return f.assignedCount(t) == 6 })
+
+	cancel()
+	<-done
+}
+
+func TestSchedulerService_TenantMaxInFlight(t *testing.T) {
+	f := newSchedulerFixture(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := f.tenantConfigRepo.Save(ctx, &model.TenantConfig{Tenant: "acme", Quantum: 2, MaxInFlight: 2})
+	if err != nil {
+		t.Fatalf("save tenant config failed: %v", err)
+	}
+	if err := f.scheduler.syncTenantQuanta(ctx); err != nil {
+		t.Fatalf("syncTenantQuanta() error = %v", err)
+	}
+
+	f.submitTenantTasks(t, "acme", "example_task", 4)
+	f.submitTasks(t, 2)
+
+	done := make(chan error, 1)
+	go func() { done <- f.scheduler.dispatchLoop(ctx) }()
+
+	// 超出租户上限的任务暂存，不阻塞其他租户
+	waitFor(t, func() bool { return f.assignedCount(t) == 4 })
+	time.Sleep(300 * time.Millisecond)
+	if n := f.assignedCount(t); n != 4 {
+		t.Errorf("assigned = %d, want 4", n)
+	}
+	scope := redis.TenantConcurrencyScope("acme")
+	running, _ := f.limiter.Running(ctx, scope)
+	if len(running) != 2 {
+		t.Fatalf("running acme tasks = %v, want 2", running)
+	}
+
+	// 任务结束释放槽位后暂存任务重新调度
+	task, _ := f.taskRepo.GetByID(ctx, running[0])
+	if err := f.limiter.ReleaseTask(ctx, task); err != nil {
+		t.Fatalf("ReleaseTask() error = %v", err)
+	}
+	waitFor(t, func() bool { return f.assignedCount(t) == 5 })
+
+	cancel()
+	<-done
+}
+
+func TestSchedulerService_RateLimit(t *testing.T) {
+	f := newSchedulerFixture(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := f.taskConfigRepo.Create(ctx, &model.TaskConfig{
+		TaskType:     "example_task",
+		ExecutorType: model.ExecutorTypeLocal,
+		RateLimit:    1,
+		RateBurst:    2,
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("create task config failed: %v", err)
+	}
+
+	taskIDs := f.submitTasks(t, 4)
+
+	done := make(chan error, 1)
+	go func() { done <- f.scheduler.dispatchLoop(ctx) }()
+
+	// 突发容量内立即分配，其余任务按预约时间进入延迟队列
+	waitFor(t, func() bool { return f.assignedCount(t) == 2 })
+	waitFor(t, func() bool {
+		n, _ := f.queueManager.GetDelayedQueueLength(ctx)
+		return n == 2
+	})
+
+	// 到期后由延迟队列放回并直接放行
+	if _, err := f.queueManager.PromoteDueTasks(ctx, time.Now().Add(3*time.Second), 10); err != nil {
+		t.Fatalf("PromoteDueTasks() error = %v", err)
+	}
+	waitFor(t, func() bool { return f.assignedCount(t) == 4 })
+
+	cancel()
+	<-done
+
+	throttled := 0
+	for _, taskID := range taskIDs {
+		logs, _ := f.taskLogRepo.GetByTaskID(context.Background(), taskID)
+		for _, l := range logs {
+			if l.LogType == model.LogTypeInfo && strings.Contains(l.Message, "throttled") {
+				throttled++
+			}
+		}
+	}
+	if throttled != 2 {
+		t.Errorf("throttle logs = %d, want 2", throttled)
+	}
+}
+
+func TestSchedulerService_TenantRateLimit(t *testing.T) {
+	f := newSchedulerFixture(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 只限制租户，任务类型不限流
+	err := f.tenantConfigRepo.Save(ctx, &model.TenantConfig{Tenant: "acme", RateLimit: 1, RateBurst: 2})
+	if err != nil {
+		t.Fatalf("save tenant config failed: %v", err)
+	}
+
+	acmeIDs := f.submitTenantTasks(t, "acme", "example_task", 4)
+	f.submitTasks(t, 2)
+
+	done := make(chan error, 1)
+	go func() { done <- f.scheduler.dispatchLoop(ctx) }()
+
+	// 租户突发容量内的任务与其他租户的任务立即分配，其余任务延迟分发
+	waitFor(t, func() bool { return f.assignedCount(t) == 4 })
+	waitFor(t, func() bool {
+		n, _ := f.queueManager.GetDelayedQueueLength(ctx)
+		return n == 2
+	})
+
+	if _, err := f.queueManager.PromoteDueTasks(ctx, time.Now().Add(3*time.Second), 10); err != nil {
+		t.Fatalf("PromoteDueTasks() error = %v", err)
+	}
+	waitFor(t, func() bool { return f.assignedCount(t) == 6 })
+
+	cancel()
+	<-done
+
+	throttled := 0
+	for _, taskID := range acmeIDs {
+		logs, _ := f.taskLogRepo.GetByTaskID(context.Background(), taskID)
+		for _, l := range logs {
+			if l.LogType == model.LogTypeInfo && strings.Contains(l.Message, "tenant acme rate limit") {
+				throttled++
+			}
+		}
+	}
+	if throttled != 2 {
+		t.Errorf("tenant throttle logs = %d, want 2", throttled)
+	}
+}
+
+func TestSchedulerService_TenantRateLimitRefundsTypeToken(t *testing.T) {
+	f := newSchedulerFixture(t, 10)
+	ctx := context.Background()
+
+	err := f.taskConfigRepo.Create(ctx, &model.TaskConfig{
+		TaskType:     "example_task",
+		ExecutorType: model.ExecutorTypeLocal,
+		RateLimit:    1,
+		RateBurst:    10,
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("create task config failed: %v", err)
+	}
+	if err := f.tenantConfigRepo.Save(ctx, &model.TenantConfig{Tenant: "acme", RateLimit: 1, RateBurst: 1}); err != nil {
+		t.Fatalf("save tenant config failed: %v", err)
+	}
+
+	f.submitTenantTasks(t, "acme", "example_task", 3)
+	taskIDs, err := f.queueManager.WaitTasks(ctx, "scheduler-1", dispatchBatchSize, time.Second)
+	if err != nil || len(taskIDs) != 3 {
+		t.Fatalf("WaitTasks() = %v, %v", taskIDs, err)
+	}
+	if _, err := f.scheduler.scheduleBatch(ctx, taskIDs); err != nil {
+		t.Fatalf("scheduleBatch() error = %v", err)
+	}
+
+	if n := f.assignedCount(t); n != 1 {
+		t.Errorf("assigned = %d, want 1", n)
+	}
+	if n, _ := f.queueManager.GetDelayedQueueLength(ctx); n != 2 {
+		t.Errorf("delayed queue length = %d, want 2", n)
+	}
+
+	// 被租户限流的任务归还任务类型令牌，只有已分配的任务消耗令牌
+	tokens, err := strconv.ParseFloat(f.mr.HGet("ratelimit:"+redis.TaskTypeRateScope("example_task"), "tokens"), 64)
+	if err != nil {
+		t.Fatalf("parse type bucket tokens failed: %v", err)
+	}
+	if tokens < 8.5 {
+		t.Errorf("type bucket tokens = %g, want about 9", tokens)
+	}
+}
+
+func TestSchedulerService_PushToWorkerQueueFailure(t *testing.T) {
+	f := newSchedulerFixture(t, 10)
+	ctx := context.Background()
+
+	taskID := f.submitTasks(t, 1)[0]
+	if _, err := f.queueManager.ReserveTask(ctx, "scheduler-1"); err != nil {
+		t.Fatalf("ReserveTask() error = %v", err)
+	}
+
+	// Worker 队列的键类型错误，分配时写入失败
+	if err := f.mr.Set("worker:worker-1:queue", "broken"); err != nil {
+		t.Fatalf("set worker queue key failed: %v", err)
+	}
+	if _, err := f.scheduler.scheduleBatch(ctx, []string{taskID}); err != nil {
+		t.Fatalf("scheduleBatch() error = %v", err)
+	}
+
+	// 分配被撤销：任务回到 PENDING、释放并发槽位并放回就绪队列
+	task, _ := f.taskRepo.GetByID(ctx, taskID)
+	if task.Status != model.StatusPending || task.WorkerID != "" {
+		t.Errorf("task = %s on worker %q, want PENDING and unassigned", task.Status, task.WorkerID)
+	}
+	for _, scope := range []string{redis.TaskTypeConcurrencyScope("example_task"), redis.TenantConcurrencyScope(model.DefaultTenant)} {
+		if running, _ := f.limiter.Running(ctx, scope); len(running) != 0 {
+			t.Errorf("running in %s = %v, want none", scope, running)
+		}
+	}
+	if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueNormal); n != 1 {
+		t.Errorf("normal queue length = %d, want 1", n)
+	}
+}
+
+func TestSchedulerService_FailoverWorker(t *testing.T) {
+	f := newSchedulerFixture(t, 10)
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		retryCount     int
+		wantStatus     model.TaskStatus
+		wantRetryCount int
+		wantDeadLetter bool
+	}{
+		{name: "retryable task is requeued", retryCount: 1, wantStatus: model.StatusPending, wantRetryCount: 2},
+		{name: "exhausted task is dead-lettered", retryCount: 3, wantStatus: model.StatusFailed, wantRetryCount: 3, wantDeadLetter: true},
+	}
+
+	for _, tt := range tests {
+		task := &model.Task{
+			TaskID:     tt.name,
+			TaskType:   "example_task",
+			Priority:   model.PriorityNormal,
+			Tenant:     model.DefaultTenant,
+			RetryCount: tt.retryCount,
+			MaxRetry:   3,
+		}
+		task.MarkAsProcessing("worker-1")
+		if err := f.taskRepo.Create(ctx, task); err != nil {
+			t.Fatalf("create task failed: %v", err)
+		}
+	}
+
+	if err := f.scheduler.failoverWorker(ctx, "worker-1"); err != nil {
+		t.Fatalf("failoverWorker() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, _ := f.taskRepo.GetByID(ctx, tt.name)
+			if task.Status != tt.wantStatus || task.RetryCount != tt.wantRetryCount {
+				t.Errorf("task = %s retry %d, want %s retry %d", task.Status, task.RetryCount, tt.wantStatus, tt.wantRetryCount)
+			}
+			_, err := f.deadLetterRepo.GetByTaskID(ctx, tt.name)
+			if (err == nil) != tt.wantDeadLetter {
+				t.Errorf("dead letter exists = %v, want %v", err == nil, tt.wantDeadLetter)
+			}
+		})
+	}
+
+	// 只有可以重试的任务重新入队
+	if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueNormal); n != 1 {
+		t.Errorf("normal queue length = %d, want 1", n)
+	}
+}
+
+// BenchmarkSchedulerService_Dispatch 阻塞批量消费的分配吞吐
+func BenchmarkSchedulerService_Dispatch(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	f := newSchedulerFixture(b, b.N)
+	f.submitTasks(b, b.N)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b.ResetTimer()
+	start := time.Now()
+	go func() { _ = f.scheduler.dispatchLoop(ctx) }()
+	for f.assignedCount(b) < int64(b.N) {
+		time.Sleep(time.Millisecond)
+	}
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "tasks/s")
+}
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/domain/service"
+)
+
+// DefaultResultOffloadThreshold 默认的结果转存阈值，JSON 编码后超过该字节数的结果写入结果存储
+const DefaultResultOffloadThreshold = 64 << 10
+
+// ErrTaskResultUnavailable 任务尚未成功结束，没有可读取的结果
+var ErrTaskResultUnavailable = errors.New("task result unavailable")
+
+// TaskResult 可按字节流读取的任务结果（JSON 编码），调用方负责关闭
+type TaskResult struct {
+	io.ReadCloser
+	Size int64
+}
+
+// TaskResultService 任务结果服务
+// Worker 写回结果时将超过阈值的结果转存到结果存储，任务中只保留 URI；查询时无论是否转存都按字节流返回
+type TaskResultService struct {
+	taskRepo  repository.TaskRepository
+	store     service.ResultStore
+	threshold int
+}
+
+// NewTaskResultService 创建任务结果服务
+// store 为 nil 时结果全部保存在任务中；threshold 小于等于 0 时使用 DefaultResultOffloadThreshold
+func NewTaskResultService(taskRepo repository.TaskRepository, store service.ResultStore, threshold int) *TaskResultService {
+	if threshold <= 0 {
+		threshold = DefaultResultOffloadThreshold
+	}
+	return &TaskResultService{taskRepo: taskRepo, store: store, threshold: threshold}
+}
+
+// Offload 结果超过阈值时写入结果存储并记录到任务，返回需要保存在任务中的结果（已转存时为 nil）
+// 结果按任务ID存放，任务重新执行时覆盖上一次的结果
+func (s *TaskResultService) Offload(ctx context.Context, task *model.Task, result map[string]interface{}) (map[string]interface{}, error) {
+	if s.store == nil || result == nil {
+		return result, nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result failed: %w", err)
+	}
+	if len(data) <= s.threshold {
+		return result, nil
+	}
+
+	uri, err := s.store.Put(ctx, task.TaskID+".json", data)
+	if err != nil {
+		return nil, fmt.Errorf("offload result failed: %w", err)
+	}
+	task.OffloadResult(uri, int64(len(data)))
+	return nil, nil
+}
+
+// Open 打开任务结果，任务未成功结束时返回 ErrTaskResultUnavailable
+func (s *TaskResultService) Open(ctx context.Context, taskID string) (*TaskResult, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != model.StatusSuccess {
+		return nil, fmt.Errorf("%w: task %s is %s", ErrTaskResultUnavailable, taskID, task.Status)
+	}
+
+	if !task.HasOffloadedResult() {
+		data, err := json.Marshal(task.Result)
+		if err != nil {
+			return nil, fmt.Errorf("marshal result failed: %w", err)
+		}
+		return &TaskResult{ReadCloser: io.NopCloser(bytes.NewReader(data)), Size: int64(len(data))}, nil
+	}
+
+	if s.store == nil {
+		return nil, fmt.Errorf("result of task %s is stored at %s but no result store is configured", taskID, task.ResultURI)
+	}
+	r, err := s.store.Open(ctx, task.ResultURI)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskResult{ReadCloser: r, Size: task.ResultSize}, nil
+}
@@ -0,0 +1,76 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/infrastructure/memory"
+	"bamboo/asynctaskmanager/infrastructure/resultstore"
+)
+
+func TestTaskResultService(t *testing.T) {
+	store, err := resultstore.NewLocalResultStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalResultStore() error = %v", err)
+	}
+
+	large := map[string]interface{}{"csv": strings.Repeat("x", 64)}
+	small := map[string]interface{}{"rows": 3}
+
+	tests := []struct {
+		name          string
+		result        map[string]interface{}
+		withStore     bool
+		wantOffloaded bool
+		wantContent   string
+	}{
+		{"small result stays inline", small, true, false, `{"rows":3}`},
+		{"large result offloaded", large, true, true, `{"csv":"` + strings.Repeat("x", 64) + `"}`},
+		{"no store keeps large result inline", large, false, false, `{"csv":"` + strings.Repeat("x", 64) + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := memory.NewTaskRepository()
+			resultService := NewTaskResultService(taskRepo, nil, 32)
+			if tt.withStore {
+				resultService = NewTaskResultService(taskRepo, store, 32)
+			}
+			ctx := context.Background()
+
+			task := &model.Task{TaskID: "task-" + strings.ReplaceAll(tt.name, " ", "-"), Status: model.StatusProcessing}
+			if err := taskRepo.Create(ctx, task); err != nil {
+				t.Fatalf("create task failed: %v", err)
+			}
+			if _, err := resultService.Open(ctx, task.TaskID); !errors.Is(err, ErrTaskResultUnavailable) {
+				t.Errorf("Open() unfinished task error = %v, want %v", err, ErrTaskResultUnavailable)
+			}
+
+			inline, err := resultService.Offload(ctx, task, tt.result)
+			if err != nil {
+				t.Fatalf("Offload() error = %v", err)
+			}
+			task.MarkAsSuccess(inline)
+			if err := taskRepo.Update(ctx, task); err != nil {
+				t.Fatalf("update task failed: %v", err)
+			}
+			if task.HasOffloadedResult() != tt.wantOffloaded || (inline == nil) != tt.wantOffloaded {
+				t.Errorf("offloaded = %v (uri %q), want %v", task.HasOffloadedResult(), task.ResultURI, tt.wantOffloaded)
+			}
+
+			result, err := resultService.Open(ctx, task.TaskID)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer result.Close()
+			data, _ := io.ReadAll(result)
+			if string(data) != tt.wantContent || result.Size != int64(len(tt.wantContent)) {
+				t.Errorf("Open() = %s (size %d), want %s", data, result.Size, tt.wantContent)
+			}
+		})
+	}
+}
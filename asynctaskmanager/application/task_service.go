@@ -2,7 +2,11 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"time"
 
 	"bamboo/asynctaskmanager/domain/model"
 	"bamboo/asynctaskmanager/domain/repository"
@@ -11,11 +15,71 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	// defaultListPageSize 默认分页大小
+	defaultListPageSize = 20
+	// maxListPageSize 最大分页大小
+	maxListPageSize = 100
+
+	// retryBatchSize 批量重试时单次查询的任务数
+	retryBatchSize = 100
+
+	// MaxIdempotencyKeyLength 幂等键的最大长度，与 task.idempotency_key 列宽一致
+	MaxIdempotencyKeyLength = 128
+
+	// MaxBatchTasks 单次批量创建的最大任务数
+	MaxBatchTasks = 10000
+)
+
+// ErrTaskNotRetryable 任务当前状态不允许手动重试
+var ErrTaskNotRetryable = errors.New("task is not retryable")
+
+// ErrBatchTooLarge 批量创建的任务数超过上限
+var ErrBatchTooLarge = errors.New("batch too large")
+
+// CreateTaskParams 创建任务参数
+type CreateTaskParams struct {
+	TaskType       string
+	Priority       model.TaskPriority
+	Payload        map[string]interface{}
+	ScheduledAt    time.Time // 计划执行时间，零值或早于当前时间表示立即执行
+	IdempotencyKey string    // 幂等键，同一任务类型下相同的键只创建一个任务，为空表示不去重
+	Tenant         string    // 所属租户，为空表示默认租户
+}
+
+// BatchTaskResult 批量创建中单个任务的结果，Err 不为 nil 表示该任务未创建
+type BatchTaskResult struct {
+	Task      *model.Task
+	Duplicate bool // 命中已存在的幂等键，Task 为先创建的任务
+	Err       error
+}
+
+// idempotencyRef 任务类型与幂等键，二者共同确定唯一任务
+type idempotencyRef struct {
+	taskType string
+	key      string
+}
+
+// RetryOptions 手动重试选项
+type RetryOptions struct {
+	ResetRetryCount bool                // 为 true 时重试次数清零，否则沿用已用的次数
+	Priority        *model.TaskPriority // 重新入队的优先级，nil 表示保持原优先级
+	TriggeredBy     string              // 触发重试的用户或系统，记录在重试日志中
+}
+
+// TaskPage 任务分页结果
+type TaskPage struct {
+	Tasks      []*model.Task
+	Total      int64
+	NextCursor int64 // 下一页游标，0 表示没有下一页
+}
+
 // TaskService 任务服务
 type TaskService struct {
 	taskRepo       repository.TaskRepository
 	taskLogRepo    repository.TaskLogRepository
 	taskConfigRepo repository.TaskConfigRepository
+	deadLetterRepo repository.DeadLetterRepository
 	queueManager   *redis.QueueManager
 }
 
@@ -24,55 +88,321 @@ func NewTaskService(
 	taskRepo repository.TaskRepository,
 	taskLogRepo repository.TaskLogRepository,
 	taskConfigRepo repository.TaskConfigRepository,
+	deadLetterRepo repository.DeadLetterRepository,
 	queueManager *redis.QueueManager,
 ) *TaskService {
 	return &TaskService{
 		taskRepo:       taskRepo,
 		taskLogRepo:    taskLogRepo,
 		taskConfigRepo: taskConfigRepo,
+		deadLetterRepo: deadLetterRepo,
 		queueManager:   queueManager,
 	}
 }
 
-// CreateTask 创建任务
+// CreateTask 创建任务（立即执行）
 func (s *TaskService) CreateTask(ctx context.Context, taskType string, priority model.TaskPriority, payload map[string]interface{}) (*model.Task, error) {
-	// 获取任务配置
-	config, err := s.taskConfigRepo.GetByType(ctx, taskType)
+	return s.CreateScheduledTask(ctx, taskType, priority, payload, time.Now())
+}
+
+// CreateScheduledTask 创建在指定时间执行的任务
+// scheduledAt 晚于当前时间时任务进入延迟队列，到期后由 Leader 移入就绪队列
+func (s *TaskService) CreateScheduledTask(ctx context.Context, taskType string, priority model.TaskPriority, payload map[string]interface{}, scheduledAt time.Time) (*model.Task, error) {
+	task, _, err := s.SubmitTask(ctx, CreateTaskParams{
+		TaskType:    taskType,
+		Priority:    priority,
+		Payload:     payload,
+		ScheduledAt: scheduledAt,
+	})
+	return task, err
+}
+
+// SubmitTask 按参数创建任务，返回任务以及是否命中已存在的幂等键
+// 任务类型配置了 payload schema 时先校验参数，不满足时返回 *model.PayloadValidationError
+// 命中时返回首次创建的任务，不再入队；先查 Redis 缓存，并发提交由 MySQL 唯一索引兜底
+// 入队失败时删除已写入的任务，使用相同幂等键再次提交会重新创建任务
+func (s *TaskService) SubmitTask(ctx context.Context, params CreateTaskParams) (*model.Task, bool, error) {
+	taskType := params.TaskType
+	tenant, err := model.NormalizeTenant(params.Tenant)
 	if err != nil {
-		return nil, fmt.Errorf("get task config failed: %w", err)
+		return nil, false, err
 	}
+	params.Tenant = tenant
 
-	if !config.IsEnabled() {
-		return nil, fmt.Errorf("task type %s is disabled", taskType)
+	config, err := s.taskConfigFor(ctx, params)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := config.ValidatePayload(params.Payload); err != nil {
+		return nil, false, err
 	}
 
-	// 生成任务ID
-	taskID := uuid.New().String()
+	// 幂等键已缓存时直接返回原任务
+	if params.IdempotencyKey != "" {
+		if task := s.findIdempotentTask(ctx, taskType, params.IdempotencyKey); task != nil {
+			return task, true, nil
+		}
+	}
 
 	// 创建任务
-	task := config.CreateTask(taskID, priority, payload)
+	task := newTaskFromParams(config, params)
+	taskID := task.TaskID
+	delayed := task.IsDelayed(time.Now())
 
 	// 保存任务
 	if err := s.taskRepo.Create(ctx, task); err != nil {
-		return nil, fmt.Errorf("create task failed: %w", err)
+		if !errors.Is(err, repository.ErrDuplicateIdempotencyKey) {
+			return nil, false, fmt.Errorf("create task failed: %w", err)
+		}
+		// 缓存未命中或并发提交，返回先创建的任务
+		existing, err := s.taskRepo.GetByIdempotencyKey(ctx, taskType, params.IdempotencyKey)
+		if err != nil {
+			return nil, false, fmt.Errorf("get task by idempotency key failed: %w", err)
+		}
+		_ = s.queueManager.SetIdempotentTask(ctx, taskType, params.IdempotencyKey, existing.TaskID)
+		return existing, true, nil
+	}
+	creation := creationLog(task, delayed)
+
+	// 推送到队列
+	var pushErr error
+	if delayed {
+		if err := s.queueManager.PushDelayedTask(ctx, task, task.ScheduledAt); err != nil {
+			pushErr = fmt.Errorf("push task to delayed queue failed: %w", err)
+		}
+	} else if err := s.queueManager.PushTask(ctx, task); err != nil {
+		pushErr = fmt.Errorf("push task to queue failed: %w", err)
+	}
+	if pushErr != nil {
+		s.discardTasks(ctx, []*model.Task{task})
+		return nil, false, pushErr
+	}
+
+	if task.IdempotencyKey != "" {
+		_ = s.queueManager.SetIdempotentTask(ctx, taskType, task.IdempotencyKey, taskID)
 	}
 
 	// 记录日志
-	logEntry := model.NewStateChangeLog(
-		taskID,
-		"",
-		model.StatusPending,
-		"",
-		"Task created",
-	)
-	_ = s.taskLogRepo.Create(ctx, logEntry)
+	_ = s.taskLogRepo.Create(ctx, creation)
 
-	// 推送到队列
-	if err := s.queueManager.PushTask(ctx, taskID, priority); err != nil {
-		return nil, fmt.Errorf("push task to queue failed: %w", err)
+	return task, false, nil
+}
+
+// CreateTasks 批量创建任务，结果与 params 按下标一一对应
+// 校验失败的任务单独报错；其余任务在一个事务中写入，创建日志批量写入，入队通过 Redis 流水线完成
+// 幂等键与已有任务或批内靠前的任务相同时标记为重复并返回先创建的任务
+// 返回的 error 仅表示请求整体不可处理，例如超过 MaxBatchTasks
+func (s *TaskService) CreateTasks(ctx context.Context, params []CreateTaskParams) ([]BatchTaskResult, error) {
+	if len(params) > MaxBatchTasks {
+		return nil, fmt.Errorf("%w: %d tasks exceeds limit %d", ErrBatchTooLarge, len(params), MaxBatchTasks)
 	}
 
-	return task, nil
+	results := make([]BatchTaskResult, len(params))
+	configs := make(map[string]*model.TaskConfig)
+	configErrs := make(map[string]error)
+	firstByKey := make(map[idempotencyRef]int)
+	keysByType := make(map[string][]string)
+	var created, duplicates []int
+
+	for i, p := range params {
+		config, ok := configs[p.TaskType]
+		if !ok && configErrs[p.TaskType] == nil {
+			var err error
+			if config, err = s.taskConfigFor(ctx, CreateTaskParams{TaskType: p.TaskType}); err != nil {
+				configErrs[p.TaskType] = err
+			} else {
+				configs[p.TaskType] = config
+			}
+		}
+		if err := configErrs[p.TaskType]; err != nil {
+			results[i].Err = err
+			continue
+		}
+		if len(p.IdempotencyKey) > MaxIdempotencyKeyLength {
+			results[i].Err = fmt.Errorf("idempotency key exceeds %d bytes", MaxIdempotencyKeyLength)
+			continue
+		}
+		if err := validatePriority(p.Priority); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if err := config.ValidatePayload(p.Payload); err != nil {
+			results[i].Err = err
+			continue
+		}
+		tenant, err := model.NormalizeTenant(p.Tenant)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		p.Tenant = tenant
+
+		if p.IdempotencyKey != "" {
+			ref := idempotencyRef{taskType: p.TaskType, key: p.IdempotencyKey}
+			if _, exists := firstByKey[ref]; exists {
+				duplicates = append(duplicates, i)
+				continue
+			}
+			firstByKey[ref] = i
+			keysByType[p.TaskType] = append(keysByType[p.TaskType], p.IdempotencyKey)
+		}
+
+		results[i].Task = newTaskFromParams(config, p)
+		created = append(created, i)
+	}
+
+	// 幂等键已存在的任务直接返回原任务
+	for taskType, keys := range keysByType {
+		existing, err := s.taskRepo.FindByIdempotencyKeys(ctx, taskType, keys)
+		if err != nil {
+			err = fmt.Errorf("find tasks by idempotency keys failed: %w", err)
+			for _, key := range keys {
+				results[firstByKey[idempotencyRef{taskType: taskType, key: key}]] = BatchTaskResult{Err: err}
+			}
+			continue
+		}
+		for _, task := range existing {
+			results[firstByKey[idempotencyRef{taskType: taskType, key: task.IdempotencyKey}]] = BatchTaskResult{Task: task, Duplicate: true}
+		}
+	}
+	created = slices.DeleteFunc(created, func(i int) bool {
+		return results[i].Duplicate || results[i].Err != nil
+	})
+
+	s.insertBatch(ctx, params, results, created)
+
+	// 批内重复的幂等键沿用首个任务的结果
+	for _, i := range duplicates {
+		first := results[firstByKey[idempotencyRef{taskType: params[i].TaskType, key: params[i].IdempotencyKey}]]
+		results[i] = BatchTaskResult{Task: first.Task, Duplicate: first.Task != nil, Err: first.Err}
+	}
+	return results, nil
+}
+
+// insertBatch 写入批量创建的任务及日志并入队
+// 写入时与并发提交的任务发生幂等键冲突则整批回滚，改为逐个提交以区分各任务的结果
+// 入队失败时删除整批已写入的任务，与 SubmitTask 一致
+func (s *TaskService) insertBatch(ctx context.Context, params []CreateTaskParams, results []BatchTaskResult, indexes []int) {
+	if len(indexes) == 0 {
+		return
+	}
+
+	tasks := make([]*model.Task, 0, len(indexes))
+	for _, i := range indexes {
+		tasks = append(tasks, results[i].Task)
+	}
+
+	if err := s.taskRepo.CreateBatch(ctx, tasks); err != nil {
+		if errors.Is(err, repository.ErrDuplicateIdempotencyKey) {
+			for _, i := range indexes {
+				task, duplicate, err := s.SubmitTask(ctx, params[i])
+				results[i] = BatchTaskResult{Task: task, Duplicate: duplicate, Err: err}
+			}
+			return
+		}
+		err = fmt.Errorf("create tasks failed: %w", err)
+		for _, i := range indexes {
+			results[i] = BatchTaskResult{Err: err}
+		}
+		return
+	}
+
+	now := time.Now()
+	logs := make([]*model.TaskLog, 0, len(tasks))
+	for _, task := range tasks {
+		logs = append(logs, creationLog(task, task.IsDelayed(now)))
+	}
+
+	if err := s.queueManager.PushTasks(ctx, tasks, now); err != nil {
+		s.discardTasks(ctx, tasks)
+		err = fmt.Errorf("push tasks to queue failed: %w", err)
+		for _, i := range indexes {
+			results[i] = BatchTaskResult{Err: err}
+		}
+		return
+	}
+
+	for _, task := range tasks {
+		if task.IdempotencyKey != "" {
+			_ = s.queueManager.SetIdempotentTask(ctx, task.TaskType, task.IdempotencyKey, task.TaskID)
+		}
+	}
+	_ = s.taskLogRepo.CreateBatch(ctx, logs)
+}
+
+// discardTasks 删除已写入但未能入队的任务，否则任务会停留在 PENDING 且幂等键被占用，永远不会执行
+// 入队失败可能是因为请求已取消，删除时不随 ctx 取消
+func (s *TaskService) discardTasks(ctx context.Context, tasks []*model.Task) {
+	ctx = context.WithoutCancel(ctx)
+	for _, task := range tasks {
+		if err := s.taskRepo.Delete(ctx, task.TaskID); err != nil {
+			log.Printf("delete unqueued task %s failed: %v", task.TaskID, err)
+		}
+	}
+}
+
+// taskConfigFor 校验创建参数并返回任务类型的配置
+func (s *TaskService) taskConfigFor(ctx context.Context, params CreateTaskParams) (*model.TaskConfig, error) {
+	if len(params.IdempotencyKey) > MaxIdempotencyKeyLength {
+		return nil, fmt.Errorf("idempotency key exceeds %d bytes", MaxIdempotencyKeyLength)
+	}
+	if err := validatePriority(params.Priority); err != nil {
+		return nil, err
+	}
+
+	config, err := s.taskConfigRepo.GetByType(ctx, params.TaskType)
+	if err != nil {
+		return nil, fmt.Errorf("get task config failed: %w", err)
+	}
+
+	if !config.IsEnabled() {
+		return nil, fmt.Errorf("task type %s is disabled", params.TaskType)
+	}
+	return config, nil
+}
+
+// validatePriority 校验优先级是否在取值范围内
+func validatePriority(priority model.TaskPriority) error {
+	if !priority.IsValid() {
+		return fmt.Errorf("priority %d out of range [%d, %d]", priority, model.PriorityMin, model.PriorityMax)
+	}
+	return nil
+}
+
+// newTaskFromParams 按任务配置与创建参数生成新任务
+func newTaskFromParams(config *model.TaskConfig, params CreateTaskParams) *model.Task {
+	task := config.CreateTask(uuid.New().String(), params.Priority, params.Payload)
+	task.IdempotencyKey = params.IdempotencyKey
+	if params.Tenant != "" {
+		task.Tenant = params.Tenant
+	}
+	if params.ScheduledAt.After(task.ScheduledAt) {
+		task.ScheduledAt = params.ScheduledAt
+	}
+	return task
+}
+
+// creationLog 任务创建日志，延迟任务记录计划执行时间
+func creationLog(task *model.Task, delayed bool) *model.TaskLog {
+	message := "Task created"
+	if delayed {
+		message = fmt.Sprintf("Task created, scheduled at %s", task.ScheduledAt.Format(time.RFC3339))
+	}
+	return model.NewStateChangeLog(task.TaskID, "", model.StatusPending, "", message)
+}
+
+// findIdempotentTask 通过 Redis 缓存查找幂等键对应的任务，未命中时返回 nil
+func (s *TaskService) findIdempotentTask(ctx context.Context, taskType, idempotencyKey string) *model.Task {
+	taskID, err := s.queueManager.GetIdempotentTask(ctx, taskType, idempotencyKey)
+	if err != nil || taskID == "" {
+		return nil
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil
+	}
+	return task
 }
 
 // GetTask 获取任务
@@ -80,6 +410,33 @@ func (s *TaskService) GetTask(ctx context.Context, taskID string) (*model.Task,
 	return s.taskRepo.GetByID(ctx, taskID)
 }
 
+// ListTasks 分页查询任务，query.Limit 不大于 0 时使用默认分页大小
+func (s *TaskService) ListTasks(ctx context.Context, query repository.TaskQuery) (*TaskPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultListPageSize
+	}
+	if query.Limit > maxListPageSize {
+		query.Limit = maxListPageSize
+	}
+
+	// 多取一条用于判断是否还有下一页
+	pageSize := query.Limit
+	query.Limit++
+
+	tasks, total, err := s.taskRepo.List(ctx, &query)
+	if err != nil {
+		return nil, fmt.Errorf("list tasks failed: %w", err)
+	}
+
+	page := &TaskPage{Tasks: tasks, Total: total}
+	if len(tasks) > pageSize {
+		page.Tasks = tasks[:pageSize]
+		page.NextCursor = page.Tasks[pageSize-1].ID
+	}
+
+	return page, nil
+}
+
 // CancelTask 取消任务
 func (s *TaskService) CancelTask(ctx context.Context, taskID string) error {
 	// 获取任务
@@ -93,23 +450,30 @@ func (s *TaskService) CancelTask(ctx context.Context, taskID string) error {
 		return fmt.Errorf("task cannot be cancelled, current status: %s", task.Status)
 	}
 
+	fromStatus := task.Status
 	if task.Status == model.StatusPending {
 		// 直接标记为已取消
 		task.MarkAsCancelled()
 		if err := s.taskRepo.Update(ctx, task); err != nil {
 			return fmt.Errorf("update task failed: %w", err)
 		}
+
+		// 延迟任务无需等到期再被丢弃
+		_ = s.queueManager.RemoveDelayedTask(ctx, taskID)
 	} else {
-		// 设置取消标记，Worker 会检测到
+		// 设置取消标记并通知 Worker 中断执行，由 Worker 记录最终的状态变更
 		if err := s.queueManager.SetCancelMark(ctx, taskID); err != nil {
 			return fmt.Errorf("set cancel mark failed: %w", err)
 		}
+
+		_ = s.taskLogRepo.Create(ctx, model.NewInfoLog(taskID, "Cancellation requested by user"))
+		return nil
 	}
 
 	// 记录日志
 	logEntry := model.NewStateChangeLog(
 		taskID,
-		task.Status,
+		fromStatus,
 		model.StatusCancelled,
 		"",
 		"Task cancelled by user",
@@ -119,6 +483,87 @@ func (s *TaskService) CancelTask(ctx context.Context, taskID string) error {
 	return nil
 }
 
+// RetryTask 手动重试已结束的任务（FAILED、TIMEOUT 或 CANCELLED），任务立即进入就绪队列
+// 任务在死信队列中时一并移出
+func (s *TaskService) RetryTask(ctx context.Context, taskID string, opts RetryOptions) (*model.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get task failed: %w", err)
+	}
+
+	if !isManuallyRetryable(task.Status) {
+		return nil, fmt.Errorf("%w: task %s is %s", ErrTaskNotRetryable, taskID, task.Status)
+	}
+
+	fromStatus := task.Status
+	if opts.Priority != nil {
+		if err := validatePriority(*opts.Priority); err != nil {
+			return nil, err
+		}
+		task.Priority = *opts.Priority
+	}
+	if err := requeueTask(ctx, s.taskRepo, s.deadLetterRepo, s.queueManager, task, opts.ResetRetryCount); err != nil {
+		return nil, err
+	}
+
+	triggeredBy := opts.TriggeredBy
+	if triggeredBy == "" {
+		triggeredBy = "unknown"
+	}
+	message := fmt.Sprintf("Task manually retried by %s from %s, priority %s", triggeredBy, fromStatus, task.Priority)
+	if opts.ResetRetryCount {
+		message += ", retry count reset"
+	}
+	_ = s.taskLogRepo.Create(ctx, model.NewRetryLog(taskID, task.RetryCount, message).WithTransition(fromStatus, model.StatusPending))
+
+	return task, nil
+}
+
+// RetryTasks 按条件批量手动重试任务，返回重试成功的数量
+// query.Status 必须是 FAILED、TIMEOUT 或 CANCELLED；单个任务失败时跳过，不影响其余任务
+func (s *TaskService) RetryTasks(ctx context.Context, query repository.TaskQuery, opts RetryOptions) (int, error) {
+	if !isManuallyRetryable(query.Status) {
+		return 0, fmt.Errorf("%w: status filter must be FAILED, TIMEOUT or CANCELLED", ErrTaskNotRetryable)
+	}
+
+	query.Limit = retryBatchSize
+	query.BeforeID = 0
+
+	// 总数只随第一页统计一次，后续分页跳过 COUNT
+	tasks, total, err := s.taskRepo.List(ctx, &query)
+	if err != nil {
+		return 0, fmt.Errorf("list tasks failed: %w", err)
+	}
+	log.Printf("retrying %d %s tasks", total, query.Status)
+	query.SkipCount = true
+
+	retried := 0
+	for {
+		for _, task := range tasks {
+			if _, err := s.RetryTask(ctx, task.TaskID, opts); err != nil {
+				log.Printf("retry task %s failed: %v", task.TaskID, err)
+				continue
+			}
+			retried++
+		}
+
+		if len(tasks) < query.Limit {
+			return retried, nil
+		}
+		query.BeforeID = tasks[len(tasks)-1].ID
+
+		tasks, _, err = s.taskRepo.List(ctx, &query)
+		if err != nil {
+			return retried, fmt.Errorf("list tasks failed: %w", err)
+		}
+	}
+}
+
+// isManuallyRetryable 判断该状态的任务是否可以手动重试
+func isManuallyRetryable(status model.TaskStatus) bool {
+	return status == model.StatusFailed || status == model.StatusTimeout || status == model.StatusCancelled
+}
+
 // GetTaskLogs 获取任务日志
 func (s *TaskService) GetTaskLogs(ctx context.Context, taskID string) ([]*model.TaskLog, error) {
 	return s.taskLogRepo.GetByTaskID(ctx, taskID)
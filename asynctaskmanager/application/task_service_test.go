@@ -0,0 +1,576 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/infrastructure/memory"
+	"bamboo/asynctaskmanager/infrastructure/redis"
+)
+
+// newTestTaskService 创建使用内存仓储与 miniredis 的任务服务，已注册 example_task 与 report_task
+func newTestTaskService(t *testing.T) (*TaskService, *redis.QueueManager, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+
+	configRepo := memory.NewTaskConfigRepository()
+	for _, taskType := range []string{"example_task", "report_task"} {
+		err := configRepo.Create(context.Background(), &model.TaskConfig{
+			TaskType:       taskType,
+			ExecutorType:   model.ExecutorTypeLocal,
+			DefaultTimeout: 30,
+			Enabled:        true,
+		})
+		if err != nil {
+			t.Fatalf("create task config failed: %v", err)
+		}
+	}
+
+	queueManager := redis.NewQueueManager(client)
+	taskService := NewTaskService(memory.NewTaskRepository(), memory.NewTaskLogRepository(), configRepo, memory.NewDeadLetterRepository(), queueManager)
+	return taskService, queueManager, mr
+}
+
+func TestTaskService_SubmitTask_Idempotency(t *testing.T) {
+	tests := []struct {
+		name          string
+		second        CreateTaskParams
+		flushCache    bool
+		wantDuplicate bool
+	}{
+		{
+			name:          "same key hits redis cache",
+			second:        CreateTaskParams{TaskType: "example_task", IdempotencyKey: "order-1"},
+			wantDuplicate: true,
+		},
+		{
+			name:          "same key falls back to unique index",
+			second:        CreateTaskParams{TaskType: "example_task", IdempotencyKey: "order-1"},
+			flushCache:    true,
+			wantDuplicate: true,
+		},
+		{
+			name:          "same key under another task type",
+			second:        CreateTaskParams{TaskType: "report_task", IdempotencyKey: "order-1"},
+			wantDuplicate: false,
+		},
+		{
+			name:          "different key",
+			second:        CreateTaskParams{TaskType: "example_task", IdempotencyKey: "order-2"},
+			wantDuplicate: false,
+		},
+		{
+			name:          "empty key never deduplicates",
+			second:        CreateTaskParams{TaskType: "example_task"},
+			wantDuplicate: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskService, queueManager, mr := newTestTaskService(t)
+			ctx := context.Background()
+
+			first, duplicate, err := taskService.SubmitTask(ctx, CreateTaskParams{
+				TaskType:       "example_task",
+				IdempotencyKey: "order-1",
+			})
+			if err != nil || duplicate {
+				t.Fatalf("first SubmitTask() = %v, %v, want new task", duplicate, err)
+			}
+			if tt.flushCache {
+				mr.FlushAll()
+			}
+
+			second, duplicate, err := taskService.SubmitTask(ctx, tt.second)
+			if err != nil {
+				t.Fatalf("SubmitTask() error = %v", err)
+			}
+			if duplicate != tt.wantDuplicate {
+				t.Errorf("duplicate = %v, want %v", duplicate, tt.wantDuplicate)
+			}
+			if sameTask := second.TaskID == first.TaskID; sameTask != tt.wantDuplicate {
+				t.Errorf("returned task %s, first task %s, want same = %v", second.TaskID, first.TaskID, tt.wantDuplicate)
+			}
+
+			// 重复提交不再入队
+			wantQueued := int64(2)
+			if tt.wantDuplicate {
+				wantQueued = 1
+			}
+			if tt.flushCache {
+				wantQueued--
+			}
+			if n, _ := queueManager.GetQueueLength(ctx, redis.QueueNormal); n != wantQueued {
+				t.Errorf("queue length = %d, want %d", n, wantQueued)
+			}
+		})
+	}
+}
+
+func TestTaskService_SubmitTask_IdempotencyKeyTooLong(t *testing.T) {
+	taskService, _, _ := newTestTaskService(t)
+
+	if _, _, err := taskService.SubmitTask(context.Background(), CreateTaskParams{
+		TaskType:       "example_task",
+		IdempotencyKey: strings.Repeat("k", MaxIdempotencyKeyLength+1),
+	}); err == nil {
+		t.Error("SubmitTask() expected error for oversized idempotency key")
+	}
+}
+
+func TestTaskService_CreateTasks(t *testing.T) {
+	taskService, queueManager, _ := newTestTaskService(t)
+	ctx := context.Background()
+
+	existing, _, err := taskService.SubmitTask(ctx, CreateTaskParams{TaskType: "example_task", IdempotencyKey: "order-1"})
+	if err != nil {
+		t.Fatalf("SubmitTask() error = %v", err)
+	}
+
+	params := []CreateTaskParams{
+		{TaskType: "example_task"},
+		{TaskType: "example_task", IdempotencyKey: "order-1"},
+		{TaskType: "missing_task"},
+		{TaskType: "report_task", IdempotencyKey: "order-2"},
+		{TaskType: "report_task", IdempotencyKey: "order-2"},
+		{TaskType: "example_task", IdempotencyKey: strings.Repeat("k", MaxIdempotencyKeyLength+1)},
+		{TaskType: "example_task", ScheduledAt: time.Now().Add(time.Hour)},
+	}
+	results, err := taskService.CreateTasks(ctx, params)
+	if err != nil {
+		t.Fatalf("CreateTasks() error = %v", err)
+	}
+
+	tests := []struct {
+		index         int
+		wantErr       bool
+		wantDuplicate bool
+		wantTaskID    string
+	}{
+		{index: 0},
+		{index: 1, wantDuplicate: true, wantTaskID: existing.TaskID},
+		{index: 2, wantErr: true},
+		{index: 3},
+		{index: 4, wantDuplicate: true, wantTaskID: results[3].Task.TaskID},
+		{index: 5, wantErr: true},
+		{index: 6},
+	}
+	for _, tt := range tests {
+		got := results[tt.index]
+		if (got.Err != nil) != tt.wantErr {
+			t.Errorf("results[%d].Err = %v, wantErr %v", tt.index, got.Err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got.Duplicate != tt.wantDuplicate {
+			t.Errorf("results[%d].Duplicate = %v, want %v", tt.index, got.Duplicate, tt.wantDuplicate)
+		}
+		if tt.wantTaskID != "" && got.Task.TaskID != tt.wantTaskID {
+			t.Errorf("results[%d].Task = %s, want %s", tt.index, got.Task.TaskID, tt.wantTaskID)
+		}
+	}
+
+	// 批量新建的任务同样写入幂等键缓存，之后的 SubmitTask 直接命中
+	if cached, _ := queueManager.GetIdempotentTask(ctx, "report_task", "order-2"); cached != results[3].Task.TaskID {
+		t.Errorf("cached idempotent task = %q, want %s", cached, results[3].Task.TaskID)
+	}
+
+	// 首次提交的任务加上批量新建的两个立即执行任务
+	if n, _ := queueManager.GetQueueLength(ctx, redis.QueueNormal); n != 3 {
+		t.Errorf("normal queue length = %d, want 3", n)
+	}
+	if n, _ := queueManager.GetDelayedQueueLength(ctx); n != 1 {
+		t.Errorf("delayed queue length = %d, want 1", n)
+	}
+	logs, _ := taskService.taskLogRepo.GetByTaskID(ctx, results[6].Task.TaskID)
+	if len(logs) != 1 || !strings.HasPrefix(logs[0].Message, "Task created, scheduled at") {
+		t.Errorf("logs of delayed task = %v, want one scheduled creation log", logs)
+	}
+
+	if _, err := taskService.CreateTasks(ctx, make([]CreateTaskParams, MaxBatchTasks+1)); !errors.Is(err, ErrBatchTooLarge) {
+		t.Errorf("CreateTasks() error = %v, want ErrBatchTooLarge", err)
+	}
+}
+
+func TestTaskService_SubmitTaskPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority model.TaskPriority
+		wantErr  bool
+	}{
+		{"normal priority", model.PriorityNormal, false},
+		{"multi-level priority", model.TaskPriority(7), false},
+		{"max priority", model.PriorityMax, false},
+		{"priority out of range", model.PriorityMax + 1, true},
+		{"negative priority", model.PriorityMin - 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskService, queueManager, _ := newTestTaskService(t)
+			ctx := context.Background()
+
+			task, _, err := taskService.SubmitTask(ctx, CreateTaskParams{TaskType: "example_task", Priority: tt.priority})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SubmitTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if task.Priority != tt.priority {
+				t.Errorf("priority = %d, want %d", task.Priority, tt.priority)
+			}
+			queueName := redis.QueueNameFor(model.DefaultTenant, tt.priority)
+			if n, _ := queueManager.GetQueueLength(ctx, queueName); n != 1 {
+				t.Errorf("%s length = %d, want 1", queueName, n)
+			}
+		})
+	}
+}
+
+func TestTaskService_SubmitTaskTenant(t *testing.T) {
+	tests := []struct {
+		name       string
+		tenant     string
+		wantTenant string
+		wantErr    bool
+	}{
+		{"default tenant", "", model.DefaultTenant, false},
+		{"named tenant", "acme", "acme", false},
+		{"invalid tenant", "acme:prod", "", true},
+		{"tenant too long", strings.Repeat("a", model.MaxTenantLength+1), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskService, queueManager, _ := newTestTaskService(t)
+			ctx := context.Background()
+
+			task, _, err := taskService.SubmitTask(ctx, CreateTaskParams{TaskType: "example_task", Tenant: tt.tenant})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SubmitTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrInvalidTenant) {
+					t.Errorf("SubmitTask() error = %v, want %v", err, model.ErrInvalidTenant)
+				}
+				return
+			}
+			if task.Tenant != tt.wantTenant {
+				t.Errorf("tenant = %s, want %s", task.Tenant, tt.wantTenant)
+			}
+			queueName := redis.QueueNameFor(tt.wantTenant, task.Priority)
+			if n, _ := queueManager.GetQueueLength(ctx, queueName); n != 1 {
+				t.Errorf("%s length = %d, want 1", queueName, n)
+			}
+		})
+	}
+}
+
+func TestTaskService_PayloadSchema(t *testing.T) {
+	taskService, queueManager, _ := newTestTaskService(t)
+	ctx := context.Background()
+
+	schema, err := model.ParsePayloadSchema([]byte(`{"type": "object", "required": ["url"], "properties": {"url": {"type": "string"}}}`))
+	if err != nil {
+		t.Fatalf("ParsePayloadSchema() error = %v", err)
+	}
+	if err := taskService.taskConfigRepo.Create(ctx, &model.TaskConfig{
+		TaskType:      "http_request",
+		ExecutorType:  model.ExecutorTypeHTTP,
+		PayloadSchema: schema,
+		Enabled:       true,
+	}); err != nil {
+		t.Fatalf("create task config failed: %v", err)
+	}
+
+	// 校验失败的任务不写入也不入队
+	_, _, err = taskService.SubmitTask(ctx, CreateTaskParams{TaskType: "http_request", Payload: map[string]interface{}{"url": 1}})
+	var validationErr *model.PayloadValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 || validationErr.Errors[0].Field != "url" {
+		t.Fatalf("SubmitTask() error = %v, want field error on url", err)
+	}
+	if n, _ := queueManager.GetQueueLength(ctx, redis.QueueNormal); n != 0 {
+		t.Errorf("normal queue length = %d, want 0", n)
+	}
+
+	results, err := taskService.CreateTasks(ctx, []CreateTaskParams{
+		{TaskType: "http_request", Payload: map[string]interface{}{"url": "http://example.com"}},
+		{TaskType: "http_request"},
+		{TaskType: "example_task"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTasks() error = %v", err)
+	}
+	for i, wantErr := range []bool{false, true, false} {
+		if (results[i].Err != nil) != wantErr {
+			t.Errorf("results[%d].Err = %v, wantErr %v", i, results[i].Err, wantErr)
+		}
+	}
+	if !errors.Is(results[1].Err, model.ErrInvalidPayload) {
+		t.Errorf("results[1].Err = %v, want ErrInvalidPayload", results[1].Err)
+	}
+	if n, _ := queueManager.GetQueueLength(ctx, redis.QueueNormal); n != 2 {
+		t.Errorf("normal queue length = %d, want 2", n)
+	}
+}
+
+func TestTaskService_PushFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		submit func(s *TaskService, ctx context.Context, params CreateTaskParams) (*model.Task, bool, error)
+		delay  time.Duration
+	}{
+		{name: "submit ready task", submit: (*TaskService).SubmitTask},
+		{name: "submit delayed task", submit: (*TaskService).SubmitTask, delay: time.Hour},
+		{
+			name: "create tasks in batch",
+			submit: func(s *TaskService, ctx context.Context, params CreateTaskParams) (*model.Task, bool, error) {
+				results, err := s.CreateTasks(ctx, []CreateTaskParams{params})
+				if err != nil {
+					return nil, false, err
+				}
+				return results[0].Task, results[0].Duplicate, results[0].Err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskService, _, mr := newTestTaskService(t)
+			ctx := context.Background()
+			params := CreateTaskParams{TaskType: "example_task", IdempotencyKey: "order-1"}
+			if tt.delay > 0 {
+				params.ScheduledAt = time.Now().Add(tt.delay)
+			}
+
+			// 入队失败时不留下停留在 PENDING 的任务
+			mr.SetError("connection refused")
+			if _, _, err := tt.submit(taskService, ctx, params); err == nil {
+				t.Fatal("submit expected error when redis is unavailable")
+			}
+			mr.SetError("")
+			if _, total, _ := taskService.taskRepo.List(ctx, &repository.TaskQuery{Limit: 10}); total != 0 {
+				t.Fatalf("tasks after push failure = %d, want 0", total)
+			}
+
+			// 使用相同幂等键重试会重新创建并入队
+			task, duplicate, err := tt.submit(taskService, ctx, params)
+			if err != nil || duplicate {
+				t.Fatalf("retry submit = duplicate %v, error %v, want new task", duplicate, err)
+			}
+			queued, _ := mr.ZMembers(redis.QueueDelayed)
+			if tt.delay == 0 {
+				queued, _ = mr.List(redis.QueueNormal)
+			}
+			if len(queued) != 1 || queued[0] != task.TaskID {
+				t.Errorf("queued tasks = %v, want [%s]", queued, task.TaskID)
+			}
+		})
+	}
+}
+
+// createFinishedTask 创建任务并将其置为指定的终态
+func createFinishedTask(t *testing.T, taskService *TaskService, status model.TaskStatus) *model.Task {
+	t.Helper()
+	ctx := context.Background()
+
+	task, err := taskService.CreateTask(ctx, "example_task", model.PriorityNormal, nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	task.RetryCount = 3
+	switch status {
+	case model.StatusFailed:
+		task.MarkAsFailed("boom")
+	case model.StatusTimeout:
+		task.MarkAsTimeout()
+	case model.StatusCancelled:
+		task.MarkAsCancelled()
+	case model.StatusSuccess:
+		task.MarkAsSuccess(nil)
+	}
+	if err := taskService.taskRepo.Update(ctx, task); err != nil {
+		t.Fatalf("update task failed: %v", err)
+	}
+	return task
+}
+
+func TestTaskService_RetryTask(t *testing.T) {
+	high := model.PriorityHigh
+
+	tests := []struct {
+		name           string
+		status         model.TaskStatus
+		opts           RetryOptions
+		wantErr        bool
+		wantRetryCount int
+		wantQueue      string
+	}{
+		{
+			name:           "failed task keeps retry count",
+			status:         model.StatusFailed,
+			opts:           RetryOptions{TriggeredBy: "alice"},
+			wantRetryCount: 3,
+			wantQueue:      redis.QueueNormal,
+		},
+		{
+			name:           "timeout task with reset and high priority",
+			status:         model.StatusTimeout,
+			opts:           RetryOptions{ResetRetryCount: true, Priority: &high, TriggeredBy: "alice"},
+			wantRetryCount: 0,
+			wantQueue:      redis.QueueHigh,
+		},
+		{
+			name:           "cancelled task",
+			status:         model.StatusCancelled,
+			wantRetryCount: 3,
+			wantQueue:      redis.QueueNormal,
+		},
+		{
+			name:    "successful task is not retryable",
+			status:  model.StatusSuccess,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskService, queueManager, mr := newTestTaskService(t)
+			ctx := context.Background()
+
+			created := createFinishedTask(t, taskService, tt.status)
+			mr.Del(redis.QueueNormal)
+
+			task, err := taskService.RetryTask(ctx, created.TaskID, tt.opts)
+			if tt.wantErr {
+				if !errors.Is(err, ErrTaskNotRetryable) {
+					t.Errorf("RetryTask() error = %v, want ErrTaskNotRetryable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RetryTask() error = %v", err)
+			}
+
+			if task.Status != model.StatusPending || task.RetryCount != tt.wantRetryCount || task.CompletedAt != nil {
+				t.Errorf("task = %s/%d, want PENDING/%d", task.Status, task.RetryCount, tt.wantRetryCount)
+			}
+			if n, _ := queueManager.GetQueueLength(ctx, tt.wantQueue); n != 1 {
+				t.Errorf("%s length = %d, want 1", tt.wantQueue, n)
+			}
+
+			logs, _ := taskService.taskLogRepo.GetByTaskIDAndType(ctx, task.TaskID, model.LogTypeRetry)
+			if len(logs) != 1 || !strings.Contains(logs[0].Message, "retried by") {
+				t.Fatalf("retry logs = %+v, want one manual retry log", logs)
+			}
+			if tt.opts.TriggeredBy != "" && !strings.Contains(logs[0].Message, tt.opts.TriggeredBy) {
+				t.Errorf("retry log %q does not name %s", logs[0].Message, tt.opts.TriggeredBy)
+			}
+		})
+	}
+}
+
+func TestTaskService_RetryTask_AfterCancel(t *testing.T) {
+	taskService, queueManager, _ := newTestTaskService(t)
+	ctx := context.Background()
+
+	task, err := taskService.CreateTask(ctx, "example_task", model.PriorityNormal, nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	task.MarkAsProcessing("worker-1")
+	if err := taskService.taskRepo.Update(ctx, task); err != nil {
+		t.Fatalf("update task failed: %v", err)
+	}
+
+	// 取消请求送达 Worker 前任务已失败，取消标记残留
+	if err := taskService.CancelTask(ctx, task.TaskID); err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+	task.MarkAsFailed("boom")
+	if err := taskService.taskRepo.Update(ctx, task); err != nil {
+		t.Fatalf("update task failed: %v", err)
+	}
+
+	if _, err := taskService.RetryTask(ctx, task.TaskID, RetryOptions{}); err != nil {
+		t.Fatalf("RetryTask() error = %v", err)
+	}
+	if marked, _ := queueManager.CheckCancelMark(ctx, task.TaskID); marked {
+		t.Error("cancel mark should be removed so the retried task is not cancelled again")
+	}
+}
+
+func TestTaskService_RetryTasks(t *testing.T) {
+	taskService, queueManager, mr := newTestTaskService(t)
+	ctx := context.Background()
+
+	for _, status := range []model.TaskStatus{model.StatusFailed, model.StatusFailed, model.StatusTimeout, model.StatusSuccess} {
+		createFinishedTask(t, taskService, status)
+	}
+	mr.Del(redis.QueueNormal)
+
+	if _, err := taskService.RetryTasks(ctx, repository.TaskQuery{}, RetryOptions{}); !errors.Is(err, ErrTaskNotRetryable) {
+		t.Errorf("RetryTasks() without status error = %v, want ErrTaskNotRetryable", err)
+	}
+
+	retried, err := taskService.RetryTasks(ctx, repository.TaskQuery{Status: model.StatusFailed}, RetryOptions{TriggeredBy: "ops"})
+	if err != nil {
+		t.Fatalf("RetryTasks() error = %v", err)
+	}
+	if retried != 2 {
+		t.Errorf("retried = %d, want 2", retried)
+	}
+	if n, _ := queueManager.GetQueueLength(ctx, redis.QueueNormal); n != 2 {
+		t.Errorf("queue length = %d, want 2", n)
+	}
+}
+
+// countingTaskRepository 记录需要统计总数的 List 调用次数
+type countingTaskRepository struct {
+	repository.TaskRepository
+	counts int
+}
+
+func (r *countingTaskRepository) List(ctx context.Context, query *repository.TaskQuery) ([]*model.Task, int64, error) {
+	if !query.SkipCount {
+		r.counts++
+	}
+	return r.TaskRepository.List(ctx, query)
+}
+
+func TestTaskService_RetryTasks_CountsOnce(t *testing.T) {
+	taskService, _, _ := newTestTaskService(t)
+	ctx := context.Background()
+
+	// 超过一页，逐页遍历
+	for i := 0; i < retryBatchSize*2+1; i++ {
+		createFinishedTask(t, taskService, model.StatusFailed)
+	}
+	repo := &countingTaskRepository{TaskRepository: taskService.taskRepo}
+	taskService.taskRepo = repo
+
+	retried, err := taskService.RetryTasks(ctx, repository.TaskQuery{Status: model.StatusFailed}, RetryOptions{})
+	if err != nil {
+		t.Fatalf("RetryTasks() error = %v", err)
+	}
+	if retried != retryBatchSize*2+1 {
+		t.Errorf("retried = %d, want %d", retried, retryBatchSize*2+1)
+	}
+	if repo.counts != 1 {
+		t.Errorf("count queries = %d, want 1", repo.counts)
+	}
+}
@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/infrastructure/redis"
+)
+
+const (
+	// taskWatchBuffer 每个订阅缓冲的事件数，缓冲写满时关闭该订阅
+	taskWatchBuffer = 256
+	// taskTypeCacheSize 任务类型缓存的上限，写满后整体清空
+	taskTypeCacheSize = 10000
+)
+
+// TaskWatchFilter 任务事件过滤条件，零值字段表示不过滤
+type TaskWatchFilter struct {
+	TaskID    string
+	TaskTypes []string
+	Statuses  []model.TaskStatus // 变更后的状态
+}
+
+// TaskEvent 任务状态变更事件
+type TaskEvent struct {
+	*model.TaskLog
+	TaskType string
+}
+
+// TaskWatcher 任务事件订阅
+type TaskWatcher struct {
+	filter TaskWatchFilter
+	events chan *TaskEvent
+	lagged atomic.Bool
+}
+
+// Events 事件通道，订阅取消或消费过慢时关闭
+func (w *TaskWatcher) Events() <-chan *TaskEvent {
+	return w.events
+}
+
+// Lagged 订阅是否因消费过慢被关闭，需在事件通道关闭后调用
+func (w *TaskWatcher) Lagged() bool {
+	return w.lagged.Load()
+}
+
+// matches 判断事件是否满足过滤条件
+func (w *TaskWatcher) matches(event *TaskEvent) bool {
+	if w.filter.TaskID != "" && w.filter.TaskID != event.TaskID {
+		return false
+	}
+	if len(w.filter.TaskTypes) > 0 && !slices.Contains(w.filter.TaskTypes, event.TaskType) {
+		return false
+	}
+	if len(w.filter.Statuses) > 0 && !slices.Contains(w.filter.Statuses, event.ToStatus) {
+		return false
+	}
+	return true
+}
+
+// TaskWatchService 任务事件订阅服务
+// 每个实例只订阅一次 Redis 事件频道，再按过滤条件分发给本实例上的订阅
+type TaskWatchService struct {
+	taskRepo repository.TaskRepository
+	eventBus *redis.TaskEventBus
+
+	mu        sync.Mutex
+	watchers  map[*TaskWatcher]struct{}
+	taskTypes map[string]string // 任务ID -> 任务类型，事件本身不带任务类型
+}
+
+// NewTaskWatchService 创建任务事件订阅服务
+func NewTaskWatchService(taskRepo repository.TaskRepository, eventBus *redis.TaskEventBus) *TaskWatchService {
+	return &TaskWatchService{
+		taskRepo:  taskRepo,
+		eventBus:  eventBus,
+		watchers:  make(map[*TaskWatcher]struct{}),
+		taskTypes: make(map[string]string),
+	}
+}
+
+// Start 订阅事件频道并分发事件，直到 ctx 结束
+// 退出时关闭所有订阅，使流式调用及时结束，不阻塞 gRPC 服务的优雅关闭
+func (s *TaskWatchService) Start(ctx context.Context) error {
+	pubsub := s.eventBus.Subscribe(ctx)
+	defer pubsub.Close()
+	defer s.closeAll()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			taskLog, err := redis.DecodeTaskEvent(msg.Payload)
+			if err != nil {
+				log.Printf("decode task event failed: %v", err)
+				continue
+			}
+			s.dispatch(ctx, taskLog)
+		}
+	}
+}
+
+// Watch 创建订阅，调用方结束时需调用 Unwatch
+func (s *TaskWatchService) Watch(filter TaskWatchFilter) *TaskWatcher {
+	watcher := &TaskWatcher{
+		filter: filter,
+		events: make(chan *TaskEvent, taskWatchBuffer),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers[watcher] = struct{}{}
+	return watcher
+}
+
+// Unwatch 取消订阅并关闭事件通道，可重复调用
+func (s *TaskWatchService) Unwatch(watcher *TaskWatcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(watcher)
+}
+
+// dispatch 将事件分发给满足条件的订阅，订阅缓冲已满时关闭该订阅而不是阻塞分发
+func (s *TaskWatchService) dispatch(ctx context.Context, taskLog *model.TaskLog) {
+	event := &TaskEvent{TaskLog: taskLog, TaskType: s.taskType(ctx, taskLog.TaskID)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for watcher := range s.watchers {
+		if !watcher.matches(event) {
+			continue
+		}
+		select {
+		case watcher.events <- event:
+		default:
+			watcher.lagged.Store(true)
+			s.remove(watcher)
+		}
+	}
+}
+
+// taskType 查询任务类型，结果按任务ID缓存，查询失败时返回空字符串
+func (s *TaskWatchService) taskType(ctx context.Context, taskID string) string {
+	s.mu.Lock()
+	if len(s.watchers) == 0 {
+		s.mu.Unlock()
+		return ""
+	}
+	taskType, ok := s.taskTypes[taskID]
+	s.mu.Unlock()
+	if ok {
+		return taskType
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.taskTypes) >= taskTypeCacheSize {
+		clear(s.taskTypes)
+	}
+	s.taskTypes[taskID] = task.TaskType
+	return task.TaskType
+}
+
+// closeAll 关闭所有订阅
+func (s *TaskWatchService) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for watcher := range s.watchers {
+		s.remove(watcher)
+	}
+}
+
+// remove 移除订阅并关闭事件通道，调用方需持有锁
+func (s *TaskWatchService) remove(watcher *TaskWatcher) {
+	if _, ok := s.watchers[watcher]; !ok {
+		return
+	}
+	delete(s.watchers, watcher)
+	close(watcher.events)
+}
+
+// IsFinishedTransition 判断状态变更后任务是否已结束
+// 还能重试的失败与超时直接变更为 PENDING，因此变更为 FAILED、TIMEOUT 即表示重试已耗尽
+func IsFinishedTransition(toStatus model.TaskStatus) bool {
+	switch toStatus {
+	case model.StatusSuccess, model.StatusFailed, model.StatusTimeout, model.StatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+// drainEvents 读取通道中已缓冲的事件
+func drainEvents(watcher *TaskWatcher) []*TaskEvent {
+	var events []*TaskEvent
+	for {
+		select {
+		case event, ok := <-watcher.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestTaskWatchService_Dispatch(t *testing.T) {
+	taskService, _, _ := newTestTaskService(t)
+	ctx := context.Background()
+
+	example, err := taskService.CreateTask(ctx, "example_task", model.PriorityNormal, nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	report, err := taskService.CreateTask(ctx, "report_task", model.PriorityNormal, nil)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter TaskWatchFilter
+		want   int
+	}{
+		{name: "no filter", want: 3},
+		{name: "by task id", filter: TaskWatchFilter{TaskID: example.TaskID}, want: 2},
+		{name: "by task type", filter: TaskWatchFilter{TaskTypes: []string{"report_task"}}, want: 1},
+		{name: "by status", filter: TaskWatchFilter{Statuses: []model.TaskStatus{model.StatusSuccess}}, want: 1},
+		{name: "unknown task type", filter: TaskWatchFilter{TaskTypes: []string{"missing_task"}}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTaskWatchService(taskService.taskRepo, nil)
+			watcher := s.Watch(tt.filter)
+			defer s.Unwatch(watcher)
+
+			s.dispatch(ctx, model.NewStateChangeLog(example.TaskID, model.StatusPending, model.StatusProcessing, "worker-1", ""))
+			s.dispatch(ctx, model.NewStateChangeLog(example.TaskID, model.StatusProcessing, model.StatusSuccess, "worker-1", ""))
+			s.dispatch(ctx, model.NewStateChangeLog(report.TaskID, model.StatusPending, model.StatusProcessing, "worker-1", ""))
+
+			events := drainEvents(watcher)
+			if len(events) != tt.want {
+				t.Fatalf("events = %d, want %d", len(events), tt.want)
+			}
+			for _, event := range events {
+				if event.TaskType == "" {
+					t.Errorf("event of %s has no task type", event.TaskID)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskWatchService_LaggedWatcherClosed(t *testing.T) {
+	taskService, _, _ := newTestTaskService(t)
+	ctx := context.Background()
+	s := NewTaskWatchService(taskService.taskRepo, nil)
+
+	slow := s.Watch(TaskWatchFilter{})
+	for i := 0; i <= taskWatchBuffer; i++ {
+		s.dispatch(ctx, model.NewStateChangeLog("task-1", model.StatusPending, model.StatusProcessing, "", ""))
+	}
+
+	if got := len(drainEvents(slow)); got != taskWatchBuffer {
+		t.Errorf("buffered events = %d, want %d", got, taskWatchBuffer)
+	}
+	if _, ok := <-slow.Events(); ok || !slow.Lagged() {
+		t.Error("lagged watcher should be closed and marked lagged")
+	}
+
+	// 已关闭的订阅可以重复取消
+	s.Unwatch(slow)
+}
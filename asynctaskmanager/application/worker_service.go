@@ -2,8 +2,10 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"bamboo/asynctaskmanager/domain/model"
@@ -12,15 +14,45 @@ import (
 	"bamboo/asynctaskmanager/infrastructure/redis"
 )
 
+const (
+	// cancelPollInterval 轮询执行中任务取消标记的间隔，用于弥补丢失的取消通知
+	cancelPollInterval = 5 * time.Second
+	// queueWaitTimeout 单次阻塞等待 Worker 队列的最长时间，也决定了退出时的最大延迟
+	queueWaitTimeout = time.Second
+)
+
+var (
+	// errTaskCancelled 任务被用户取消，作为执行上下文的取消原因
+	errTaskCancelled = errors.New("task cancelled by user")
+	// errWorkerShutdown Worker 关闭超时，作为执行上下文的取消原因
+	errWorkerShutdown = errors.New("worker shutdown")
+)
+
 // WorkerService Worker 服务
 type WorkerService struct {
 	worker            *model.Worker
 	taskRepo          repository.TaskRepository
 	taskLogRepo       repository.TaskLogRepository
+	taskConfigRepo    repository.TaskConfigRepository
+	deadLetterRepo    repository.DeadLetterRepository
 	workerRepo        repository.WorkerRepository
 	queueManager      *redis.QueueManager
+	limiter           *redis.ConcurrencyLimiter
 	executorRegistry  service.ExecutorRegistry
+	resultService     *TaskResultService
 	heartbeatInterval time.Duration
+	shutdownTimeout   time.Duration
+
+	// slots 执行槽位，容量为 Worker.Capacity
+	slots    chan struct{}
+	inflight sync.WaitGroup
+	// execBase 所有任务执行上下文的根，关闭超时后取消
+	execBase context.Context
+	stopExec context.CancelCauseFunc
+
+	// running 执行中任务的取消函数（task_id -> cancel）
+	running   map[string]context.CancelCauseFunc
+	runningMu sync.Mutex
 }
 
 // NewWorkerService 创建 Worker 服务
@@ -28,19 +60,39 @@ func NewWorkerService(
 	worker *model.Worker,
 	taskRepo repository.TaskRepository,
 	taskLogRepo repository.TaskLogRepository,
+	taskConfigRepo repository.TaskConfigRepository,
+	deadLetterRepo repository.DeadLetterRepository,
 	workerRepo repository.WorkerRepository,
 	queueManager *redis.QueueManager,
+	limiter *redis.ConcurrencyLimiter,
 	executorRegistry service.ExecutorRegistry,
+	resultService *TaskResultService,
 	heartbeatInterval time.Duration,
+	shutdownTimeout time.Duration,
 ) *WorkerService {
+	capacity := worker.Capacity
+	if capacity < 1 {
+		capacity = 1
+	}
+	execBase, stopExec := context.WithCancelCause(context.Background())
+
 	return &WorkerService{
 		worker:            worker,
 		taskRepo:          taskRepo,
 		taskLogRepo:       taskLogRepo,
+		taskConfigRepo:    taskConfigRepo,
+		deadLetterRepo:    deadLetterRepo,
 		workerRepo:        workerRepo,
 		queueManager:      queueManager,
+		limiter:           limiter,
 		executorRegistry:  executorRegistry,
+		resultService:     resultService,
 		heartbeatInterval: heartbeatInterval,
+		shutdownTimeout:   shutdownTimeout,
+		slots:             make(chan struct{}, capacity),
+		execBase:          execBase,
+		stopExec:          stopExec,
+		running:           make(map[string]context.CancelCauseFunc),
 	}
 }
 
@@ -56,11 +108,61 @@ func (s *WorkerService) Start(ctx context.Context) error {
 
 	log.Printf("worker %s registered", s.worker.WorkerID)
 
+	if err := s.queueManager.KeepAlive(ctx, s.worker.WorkerID); err != nil {
+		return fmt.Errorf("keep consumer alive failed: %w", err)
+	}
+
+	// 心跳与取消监听持续到在途任务结束，避免排空期间被判定为失效
+	bgCtx, stopBackground := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopBackground()
+
 	// 启动心跳
-	go s.heartbeatLoop(ctx)
+	go s.heartbeatLoop(bgCtx)
+
+	// 监听取消通知
+	go s.cancelLoop(bgCtx)
+
+	// 启动任务处理循环，退出后不再接收新任务并等待在途任务结束
+	err := s.taskLoop(ctx)
+	s.markOffline(bgCtx)
+	s.drain()
 
-	// 启动任务处理循环
-	return s.taskLoop(ctx)
+	return err
+}
+
+// markOffline 标记为离线，调度器不再向本 Worker 分配任务
+func (s *WorkerService) markOffline(ctx context.Context) {
+	worker, err := s.workerRepo.GetByID(ctx, s.worker.WorkerID)
+	if err != nil {
+		log.Printf("get worker failed: %v", err)
+		return
+	}
+
+	worker.MarkOffline()
+	if err := s.workerRepo.Update(ctx, worker); err != nil {
+		log.Printf("mark worker offline failed: %v", err)
+	}
+}
+
+// drain 等待在途任务结束，超过 shutdownTimeout 后中断剩余任务
+// 被中断的任务不确认，由 Leader 按 Worker 失效进行故障转移
+func (s *WorkerService) drain() {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(s.shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		log.Printf("worker %s shutdown timeout, interrupting %d tasks", s.worker.WorkerID, len(s.runningTaskIDs()))
+		s.stopExec(errWorkerShutdown)
+		<-done
+	}
 }
 
 // heartbeatLoop 心跳循环
@@ -71,86 +173,206 @@ func (s *WorkerService) heartbeatLoop(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			// 标记为离线
-			s.worker.MarkOffline()
-			_ = s.workerRepo.Update(ctx, s.worker)
 			return
 		case <-ticker.C:
 			if err := s.workerRepo.UpdateHeartbeat(ctx, s.worker.WorkerID); err != nil {
 				log.Printf("update heartbeat failed: %v", err)
 			}
+			if err := s.queueManager.KeepAlive(ctx, s.worker.WorkerID); err != nil {
+				log.Printf("keep consumer alive failed: %v", err)
+			}
 		}
 	}
 }
 
-// taskLoop 任务处理循环
-func (s *WorkerService) taskLoop(ctx context.Context) error {
-	ticker := time.NewTicker(100 * time.Millisecond)
+// cancelLoop 取消监听循环
+// 收到取消通知时中断对应任务的执行上下文，并定期轮询取消标记，防止断线期间丢失通知
+func (s *WorkerService) cancelLoop(ctx context.Context) {
+	pubsub := s.queueManager.SubscribeCancel(ctx)
+	defer pubsub.Close()
+
+	ticker := time.NewTicker(cancelPollInterval)
 	defer ticker.Stop()
 
+	messages := pubsub.Channel()
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			s.interruptTask(msg.Payload)
 		case <-ticker.C:
-			if err := s.processTask(ctx); err != nil {
-				log.Printf("process task failed: %v", err)
+			for _, taskID := range s.runningTaskIDs() {
+				cancelled, err := s.queueManager.CheckCancelMark(ctx, taskID)
+				if err != nil {
+					log.Printf("check cancel mark failed: %v", err)
+					continue
+				}
+				if cancelled {
+					s.interruptTask(taskID)
+				}
 			}
 		}
 	}
 }
 
-// processTask 处理任务
-func (s *WorkerService) processTask(ctx context.Context) error {
-	// 从队列获取任务
-	taskID, err := s.queueManager.PopFromWorkerQueue(ctx, s.worker.WorkerID)
-	if err != nil {
-		return nil // 队列为空
+// interruptTask 中断执行中的任务，任务不在本 Worker 上时忽略
+func (s *WorkerService) interruptTask(taskID string) {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	if cancel, ok := s.running[taskID]; ok {
+		log.Printf("worker %s interrupting task %s", s.worker.WorkerID, taskID)
+		cancel(errTaskCancelled)
+	}
+}
+
+// trackTask 登记执行中任务的取消函数，返回的函数用于注销
+func (s *WorkerService) trackTask(taskID string, cancel context.CancelCauseFunc) func() {
+	s.runningMu.Lock()
+	s.running[taskID] = cancel
+	s.runningMu.Unlock()
+
+	return func() {
+		s.runningMu.Lock()
+		delete(s.running, taskID)
+		s.runningMu.Unlock()
 	}
+}
+
+// runningTaskIDs 执行中的任务ID
+func (s *WorkerService) runningTaskIDs() []string {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	ids := make([]string, 0, len(s.running))
+	for taskID := range s.running {
+		ids = append(ids, taskID)
+	}
+	return ids
+}
+
+// taskLoop 任务处理循环，最多同时执行 Capacity 个任务
+func (s *WorkerService) taskLoop(ctx context.Context) error {
+	for {
+		// 等待空闲槽位
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case s.slots <- struct{}{}:
+		}
+
+		// 阻塞等待任务，处理结束后确认；进程崩溃时未确认的任务由 Leader 回收
+		taskID, err := s.queueManager.WaitWorkerTask(ctx, s.worker.WorkerID, queueWaitTimeout)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("wait worker task failed: %v", err)
+		}
+		if taskID == "" {
+			<-s.slots
+			if err != nil {
+				// Redis 异常时退避，避免空转
+				select {
+				case <-ctx.Done():
+				case <-time.After(queueWaitTimeout):
+				}
+			}
+			continue
+		}
+
+		s.inflight.Add(1)
+		go func() {
+			defer s.inflight.Done()
+			defer func() { <-s.slots }()
+
+			// 服务退出后仍需写回在途任务的结果
+			if err := s.processTask(context.WithoutCancel(ctx), taskID); err != nil {
+				log.Printf("process task failed: %v", err)
+			}
+		}()
+	}
+}
+
+// processTask 处理已从队列取出的任务
+// 处理结束后确认、扣减负载并释放任务类型与租户的并发槽位；因 Worker 关闭被中断的任务保持未确认
+// 写回任务时 fencing token 已过期（任务已被故障转移）则放弃本次结果，不再重新入队或移入死信
+func (s *WorkerService) processTask(ctx context.Context, taskID string) error {
+	interrupted := false
+	var held *model.Task
+	defer func() {
+		if interrupted {
+			return
+		}
+		if err := s.queueManager.AckTask(ctx, s.worker.WorkerID, taskID); err != nil {
+			log.Printf("ack task failed: %v", err)
+		}
+		if err := s.workerRepo.IncrLoad(ctx, s.worker.WorkerID, -1); err != nil {
+			log.Printf("update worker load failed: %v", err)
+		}
+		if held != nil {
+			if err := s.limiter.ReleaseTask(ctx, held); err != nil {
+				log.Printf("release concurrency slot failed: %v", err)
+			}
+		}
+	}()
 
 	// 获取任务详情
 	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
 		return fmt.Errorf("get task failed: %w", err)
 	}
+	held = task
 
 	log.Printf("worker %s processing task %s", s.worker.WorkerID, task.TaskID)
 
+	// 设置超时，并登记取消函数以便收到取消通知时中断执行
+	timeoutCtx, cancelTimeout := context.WithTimeout(s.execBase, time.Duration(task.Timeout)*time.Second)
+	defer cancelTimeout()
+	execCtx, cancel := context.WithCancelCause(timeoutCtx)
+	defer cancel(nil)
+	defer s.trackTask(taskID, cancel)()
+
 	// 检查取消标记
 	cancelled, err := s.queueManager.CheckCancelMark(ctx, taskID)
 	if err == nil && cancelled {
-		task.MarkAsCancelled()
-		_ = s.taskRepo.Update(ctx, task)
-		_ = s.queueManager.RemoveCancelMark(ctx, taskID)
-
-		// 更新负载
-		s.worker.CompleteTask()
-		_ = s.workerRepo.UpdateLoad(ctx, s.worker.WorkerID, s.worker.CurrentLoad)
-
-		log.Printf("task %s cancelled", taskID)
-		return nil
+		return s.finishCancelled(ctx, task)
 	}
 
 	// 获取执行器
 	executor, err := s.executorRegistry.Get(task.TaskType)
 	if err != nil {
 		task.MarkAsFailed(fmt.Sprintf("executor not found: %s", task.TaskType))
-		_ = s.taskRepo.Update(ctx, task)
-
-		// 更新负载
-		s.worker.CompleteTask()
-		_ = s.workerRepo.UpdateLoad(ctx, s.worker.WorkerID, s.worker.CurrentLoad)
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			return s.updateFailed(task, err)
+		}
+		_ = s.taskLogRepo.Create(ctx, model.NewErrorLog(taskID, s.worker.WorkerID, "Executor not found", task.ErrorMsg).
+			WithTransition(model.StatusProcessing, model.StatusFailed))
 
 		return fmt.Errorf("executor not found: %s", task.TaskType)
 	}
 
-	// 设置超时
-	execCtx, cancel := context.WithTimeout(ctx, time.Duration(task.Timeout)*time.Second)
-	defer cancel()
-
 	// 执行任务
 	result, err := executor.Execute(execCtx, task)
 
+	// 执行期间被取消，无论执行器是否返回错误都以取消结束
+	if errors.Is(context.Cause(execCtx), errTaskCancelled) {
+		return s.finishCancelled(ctx, task)
+	}
+
+	// 关闭超时被中断，不写回结果
+	if errors.Is(context.Cause(execCtx), errWorkerShutdown) {
+		interrupted = true
+		log.Printf("task %s interrupted by worker shutdown", taskID)
+		return nil
+	}
+
+	// 结果超过阈值时转存到结果存储，转存失败按执行失败处理
+	if err == nil {
+		result, err = s.resultService.Offload(ctx, task, result)
+	}
+
 	// 处理结果
 	if err != nil {
 		if execCtx.Err() == context.DeadlineExceeded {
@@ -165,22 +387,28 @@ func (s *WorkerService) processTask(ctx context.Context) error {
 
 		// 判断是否需要重试
 		if task.CanRetry() {
-			task.MarkAsRetrying()
-			_ = s.taskRepo.Update(ctx, task)
+			// 按重试策略计算退避时间，放入延迟队列等待到期
+			delay := retryDelay(ctx, s.taskConfigRepo, task)
+			task.ScheduleRetry(time.Now().Add(delay))
+			if err := s.taskRepo.Update(ctx, task); err != nil {
+				return s.updateFailed(task, err)
+			}
 
 			// 重新推送到队列
-			_ = s.queueManager.PushTask(ctx, taskID, task.Priority)
+			_ = s.queueManager.PushDelayedTask(ctx, task, task.ScheduledAt)
 
 			// 记录重试日志
 			logEntry := model.NewRetryLog(
 				taskID,
 				task.RetryCount,
-				fmt.Sprintf("Task failed, retry %d/%d", task.RetryCount, task.MaxRetry),
-			)
+				fmt.Sprintf("Task failed, retry %d/%d in %s", task.RetryCount, task.MaxRetry, delay),
+			).WithTransition(model.StatusProcessing, model.StatusPending)
 			_ = s.taskLogRepo.Create(ctx, logEntry)
 		} else {
 			// 达到最大重试次数
-			_ = s.taskRepo.Update(ctx, task)
+			if err := s.taskRepo.Update(ctx, task); err != nil {
+				return s.updateFailed(task, err)
+			}
 
 			// 记录错误日志
 			logEntry := model.NewErrorLog(
@@ -188,13 +416,19 @@ func (s *WorkerService) processTask(ctx context.Context) error {
 				s.worker.WorkerID,
 				"Task failed and max retry reached",
 				err.Error(),
-			)
+			).WithTransition(model.StatusProcessing, task.Status)
 			_ = s.taskLogRepo.Create(ctx, logEntry)
+
+			if err := moveToDeadLetter(ctx, s.deadLetterRepo, s.queueManager, task); err != nil {
+				log.Printf("move task %s to dead letter queue failed: %v", taskID, err)
+			}
 		}
 	} else {
 		// 成功
 		task.MarkAsSuccess(result)
-		_ = s.taskRepo.Update(ctx, task)
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			return s.updateFailed(task, err)
+		}
 
 		// 记录日志
 		logEntry := model.NewStateChangeLog(
@@ -209,12 +443,38 @@ func (s *WorkerService) processTask(ctx context.Context) error {
 		log.Printf("task %s succeeded", taskID)
 	}
 
-	// 更新 Worker 负载
-	s.worker.CompleteTask()
-	if err := s.workerRepo.UpdateLoad(ctx, s.worker.WorkerID, s.worker.CurrentLoad); err != nil {
-		log.Printf("update worker load failed: %v", err)
+	return nil
+}
+
+// updateFailed 处理写回任务失败
+// fencing token 已过期说明任务已被故障转移给其他 Worker，本 Worker 只记录日志，不再操作任何队列；其他错误返回给调用方
+func (s *WorkerService) updateFailed(task *model.Task, err error) error {
+	if errors.Is(err, repository.ErrStaleFencingToken) {
+		log.Printf("task %s has been reassigned, worker %s drops its outcome: %v", task.TaskID, s.worker.WorkerID, err)
+		return nil
 	}
+	return fmt.Errorf("update task %s failed: %w", task.TaskID, err)
+}
 
+// finishCancelled 以取消状态结束任务，并清除取消标记
+func (s *WorkerService) finishCancelled(ctx context.Context, task *model.Task) error {
+	fromStatus := task.Status
+	task.MarkAsCancelled()
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return s.updateFailed(task, err)
+	}
+	_ = s.queueManager.RemoveCancelMark(ctx, task.TaskID)
+
+	logEntry := model.NewStateChangeLog(
+		task.TaskID,
+		fromStatus,
+		model.StatusCancelled,
+		s.worker.WorkerID,
+		"Task cancelled by user",
+	)
+	_ = s.taskLogRepo.Create(ctx, logEntry)
+
+	log.Printf("task %s cancelled", task.TaskID)
 	return nil
 }
 
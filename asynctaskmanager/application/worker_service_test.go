@@ -0,0 +1,430 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/infrastructure/executor"
+	"bamboo/asynctaskmanager/infrastructure/memory"
+	"bamboo/asynctaskmanager/infrastructure/redis"
+	"bamboo/asynctaskmanager/infrastructure/resultstore"
+)
+
+// workerFixture Worker 测试环境
+type workerFixture struct {
+	mr           *miniredis.Miniredis
+	taskRepo     repository.TaskRepository
+	taskLogRepo  repository.TaskLogRepository
+	deadLetters  repository.DeadLetterRepository
+	queueManager *redis.QueueManager
+	workerRepo   repository.WorkerRepository
+	executor     *executor.LocalExecutor
+	resultStore  *resultstore.LocalResultStore
+	worker       *WorkerService
+
+	// release 放行 block_task；running/peak 为 block_task 当前与最大并发数
+	release chan struct{}
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func newWorkerFixture(t *testing.T, capacity int, shutdownTimeout time.Duration) *workerFixture {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+
+	resultStore, err := resultstore.NewLocalResultStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("create result store failed: %v", err)
+	}
+
+	f := &workerFixture{
+		mr:           mr,
+		taskRepo:     memory.NewTaskRepository(),
+		taskLogRepo:  memory.NewTaskLogRepository(),
+		deadLetters:  memory.NewDeadLetterRepository(),
+		queueManager: redis.NewQueueManager(client),
+		workerRepo:   redis.NewWorkerRepository(client),
+		executor:     executor.NewLocalExecutor(),
+		resultStore:  resultStore,
+		release:      make(chan struct{}),
+	}
+
+	registry := executor.NewExecutorRegistry()
+	f.executor.RegisterHandler("long_task", func(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	f.executor.RegisterHandler("block_task", func(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+		n := f.running.Add(1)
+		defer f.running.Add(-1)
+		for {
+			peak := f.peak.Load()
+			if n <= peak || f.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		<-f.release
+		return map[string]interface{}{"ok": true}, nil
+	})
+	f.executor.RegisterHandler("fail_task", func(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errors.New("downstream unavailable")
+	})
+	f.executor.RegisterHandler("report_task", func(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"csv": strings.Repeat("x", DefaultResultOffloadThreshold)}, nil
+	})
+	if err := registry.Register(f.executor); err != nil {
+		t.Fatalf("register executor failed: %v", err)
+	}
+
+	worker := &model.Worker{
+		WorkerID:       "worker-1",
+		Capacity:       capacity,
+		Status:         model.WorkerOnline,
+		SupportedTypes: []string{"long_task", "block_task", "fail_task", "report_task"},
+	}
+	if err := f.workerRepo.Register(context.Background(), worker); err != nil {
+		t.Fatalf("register worker failed: %v", err)
+	}
+	f.worker = NewWorkerService(
+		worker,
+		f.taskRepo,
+		f.taskLogRepo,
+		memory.NewTaskConfigRepository(),
+		f.deadLetters,
+		f.workerRepo,
+		f.queueManager,
+		redis.NewConcurrencyLimiter(client),
+		registry,
+		NewTaskResultService(f.taskRepo, f.resultStore, 0),
+		time.Second,
+		shutdownTimeout,
+	)
+	return f
+}
+
+// assignTask 模拟调度器将任务分配给 Worker 并增加负载
+func (f *workerFixture) assignTask(t *testing.T, taskID, taskType string) *model.Task {
+	t.Helper()
+
+	ctx := context.Background()
+	task := &model.Task{
+		TaskID:   taskID,
+		TaskType: taskType,
+		Priority: model.PriorityNormal,
+		Status:   model.StatusPending,
+		Timeout:  60,
+	}
+	task.MarkAsProcessing(f.worker.worker.WorkerID)
+	if err := f.taskRepo.Create(ctx, task); err != nil {
+		t.Fatalf("create task failed: %v", err)
+	}
+	if err := f.queueManager.PushToWorkerQueue(ctx, f.worker.worker.WorkerID, taskID); err != nil {
+		t.Fatalf("push to worker queue failed: %v", err)
+	}
+	if err := f.workerRepo.IncrLoad(ctx, f.worker.worker.WorkerID, 1); err != nil {
+		t.Fatalf("incr load failed: %v", err)
+	}
+	return task
+}
+
+// load Worker 注册表中的负载
+func (f *workerFixture) load(t *testing.T) int {
+	t.Helper()
+
+	worker, err := f.workerRepo.GetByID(context.Background(), f.worker.worker.WorkerID)
+	if err != nil {
+		t.Fatalf("get worker failed: %v", err)
+	}
+	return worker.CurrentLoad
+}
+
+func TestWorkerService_CancelRunningTask(t *testing.T) {
+	f := newWorkerFixture(t, 1, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go f.worker.cancelLoop(ctx)
+	waitFor(t, func() bool { return f.mr.PubSubNumSub("task:cancel")["task:cancel"] > 0 })
+
+	f.assignTask(t, "task-1", "long_task")
+
+	done := make(chan error, 1)
+	go func() { done <- f.worker.processTask(ctx, "task-1") }()
+	waitFor(t, func() bool { return len(f.worker.runningTaskIDs()) == 1 })
+
+	taskService := NewTaskService(f.taskRepo, f.taskLogRepo, memory.NewTaskConfigRepository(), f.deadLetters, f.queueManager)
+	if err := taskService.CancelTask(ctx, "task-1"); err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("processTask() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("running task was not interrupted")
+	}
+
+	task, _ := f.taskRepo.GetByID(ctx, "task-1")
+	if task.Status != model.StatusCancelled {
+		t.Errorf("task status = %s, want %s", task.Status, model.StatusCancelled)
+	}
+	if cancelled, _ := f.queueManager.CheckCancelMark(ctx, "task-1"); cancelled {
+		t.Error("cancel mark should be removed")
+	}
+
+	logs, _ := f.taskLogRepo.GetByTaskID(ctx, "task-1")
+	var found bool
+	for _, l := range logs {
+		if l.LogType == model.LogTypeStateChange && l.ToStatus == model.StatusCancelled && l.WorkerID == "worker-1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("missing CANCELLED state change log from worker, got %d logs", len(logs))
+	}
+}
+
+func TestWorkerService_CancelBeforeExecution(t *testing.T) {
+	f := newWorkerFixture(t, 1, time.Second)
+	ctx := context.Background()
+
+	f.assignTask(t, "task-1", "long_task")
+	if err := f.queueManager.SetCancelMark(ctx, "task-1"); err != nil {
+		t.Fatalf("SetCancelMark() error = %v", err)
+	}
+
+	if err := f.worker.processTask(ctx, "task-1"); err != nil {
+		t.Fatalf("processTask() error = %v", err)
+	}
+
+	task, _ := f.taskRepo.GetByID(ctx, "task-1")
+	if task.Status != model.StatusCancelled {
+		t.Errorf("task status = %s, want %s", task.Status, model.StatusCancelled)
+	}
+	if load := f.load(t); load != 0 {
+		t.Errorf("worker load = %d, want 0", load)
+	}
+}
+
+func TestWorkerService_DeadLetterAfterRetriesExhausted(t *testing.T) {
+	f := newWorkerFixture(t, 1, time.Second)
+	ctx := context.Background()
+
+	// assignTask 创建的任务 MaxRetry 为 0，首次失败即耗尽重试
+	f.assignTask(t, "task-1", "fail_task")
+	if err := f.worker.processTask(ctx, "task-1"); err != nil {
+		t.Fatalf("processTask() error = %v", err)
+	}
+
+	task, _ := f.taskRepo.GetByID(ctx, "task-1")
+	if task.Status != model.StatusFailed {
+		t.Errorf("task status = %s, want %s", task.Status, model.StatusFailed)
+	}
+	deadLetter, err := f.deadLetters.GetByTaskID(ctx, "task-1")
+	if err != nil {
+		t.Fatalf("GetByTaskID() error = %v", err)
+	}
+	if deadLetter.TaskType != "fail_task" || deadLetter.ErrorMsg != "downstream unavailable" {
+		t.Errorf("dead letter = %+v, want fail_task with executor error", deadLetter)
+	}
+	if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueDead); n != 1 {
+		t.Errorf("dead letter queue length = %d, want 1", n)
+	}
+}
+
+// staleTaskRepository 模拟任务已被故障转移给其他 Worker，写回时总是返回 ErrStaleFencingToken
+type staleTaskRepository struct {
+	repository.TaskRepository
+}
+
+func (r staleTaskRepository) Update(ctx context.Context, task *model.Task) error {
+	return fmt.Errorf("update task %s rejected: %w", task.TaskID, repository.ErrStaleFencingToken)
+}
+
+func TestWorkerService_StaleFencingToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		taskType string
+		maxRetry int
+	}{
+		{"失败后重试", "fail_task", 3},
+		{"重试耗尽", "fail_task", 0},
+		{"成功", "report_task", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newWorkerFixture(t, 1, time.Second)
+			ctx := context.Background()
+
+			task := f.assignTask(t, "task-1", tt.taskType)
+			task.MaxRetry = tt.maxRetry
+			f.worker.taskRepo = staleTaskRepository{f.taskRepo}
+
+			if err := f.worker.processTask(ctx, "task-1"); err != nil {
+				t.Fatalf("processTask() error = %v", err)
+			}
+
+			// 旧 Worker 不得重新入队或移入死信
+			if n, _ := f.queueManager.GetDelayedQueueLength(ctx); n != 0 {
+				t.Errorf("delayed queue length = %d, want 0", n)
+			}
+			if n, _ := f.queueManager.GetQueueLength(ctx, redis.QueueDead); n != 0 {
+				t.Errorf("dead letter queue length = %d, want 0", n)
+			}
+			if _, err := f.deadLetters.GetByTaskID(ctx, "task-1"); err == nil {
+				t.Error("task should not be dead-lettered by a stale worker")
+			}
+			if load := f.load(t); load != 0 {
+				t.Errorf("worker load = %d, want 0", load)
+			}
+		})
+	}
+}
+
+func TestWorkerService_OffloadLargeResult(t *testing.T) {
+	f := newWorkerFixture(t, 1, time.Second)
+	ctx := context.Background()
+
+	f.assignTask(t, "task-1", "report_task")
+	if err := f.worker.processTask(ctx, "task-1"); err != nil {
+		t.Fatalf("processTask() error = %v", err)
+	}
+
+	task, _ := f.taskRepo.GetByID(ctx, "task-1")
+	if task.Status != model.StatusSuccess {
+		t.Fatalf("task status = %s, want %s", task.Status, model.StatusSuccess)
+	}
+	if task.Result != nil || !task.HasOffloadedResult() {
+		t.Fatalf("result should be offloaded, got uri %q and %d inline fields", task.ResultURI, len(task.Result))
+	}
+	r, err := f.resultStore.Open(ctx, task.ResultURI)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	r.Close()
+}
+
+func TestWorkerService_ConcurrentExecution(t *testing.T) {
+	f := newWorkerFixture(t, 3, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	taskIDs := []string{"task-1", "task-2", "task-3", "task-4", "task-5"}
+	for _, taskID := range taskIDs {
+		f.assignTask(t, taskID, "block_task")
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- f.worker.taskLoop(ctx) }()
+
+	// 槽位占满后不再取任务
+	waitFor(t, func() bool { return f.running.Load() == 3 })
+	time.Sleep(300 * time.Millisecond)
+	if n, _ := f.queueManager.GetQueueLength(ctx, "worker:worker-1:queue"); n != 2 {
+		t.Errorf("worker queue length = %d, want 2", n)
+	}
+
+	close(f.release)
+	waitFor(t, func() bool { return f.load(t) == 0 })
+
+	cancel()
+	<-done
+	f.worker.drain()
+
+	for _, taskID := range taskIDs {
+		if task, _ := f.taskRepo.GetByID(ctx, taskID); task.Status != model.StatusSuccess {
+			t.Errorf("task %s status = %s, want %s", taskID, task.Status, model.StatusSuccess)
+		}
+	}
+	if peak := f.peak.Load(); peak != 3 {
+		t.Errorf("peak concurrency = %d, want 3", peak)
+	}
+}
+
+func TestWorkerService_GracefulShutdown(t *testing.T) {
+	tests := []struct {
+		name       string
+		taskType   string
+		wantStatus model.TaskStatus
+		wantAcked  bool
+	}{
+		{
+			name:       "in-flight task finishes before deadline",
+			taskType:   "block_task",
+			wantStatus: model.StatusSuccess,
+			wantAcked:  true,
+		},
+		{
+			name:       "in-flight task interrupted at deadline",
+			taskType:   "long_task",
+			wantStatus: model.StatusProcessing,
+			wantAcked:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newWorkerFixture(t, 2, 300*time.Millisecond)
+			ctx, cancel := context.WithCancel(context.Background())
+
+			f.assignTask(t, "task-1", tt.taskType)
+
+			done := make(chan error, 1)
+			go func() { done <- f.worker.Start(ctx) }()
+			waitFor(t, func() bool { return len(f.worker.runningTaskIDs()) == 1 })
+
+			cancel()
+			if tt.taskType == "block_task" {
+				close(f.release)
+			}
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Start() did not return after shutdown")
+			}
+
+			task, _ := f.taskRepo.GetByID(context.Background(), "task-1")
+			if task.Status != tt.wantStatus {
+				t.Errorf("task status = %s, want %s", task.Status, tt.wantStatus)
+			}
+			n, _ := f.queueManager.GetProcessingLength(context.Background(), "worker-1")
+			if acked := n == 0; acked != tt.wantAcked {
+				t.Errorf("acked = %v, want %v", acked, tt.wantAcked)
+			}
+
+			worker, _ := f.workerRepo.GetByID(context.Background(), "worker-1")
+			if worker.Status != model.WorkerOffline {
+				t.Errorf("worker status = %s, want %s", worker.Status, model.WorkerOffline)
+			}
+		})
+	}
+}
+
+// waitFor 等待条件成立
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
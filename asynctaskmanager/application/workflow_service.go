@@ -0,0 +1,204 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/infrastructure/redis"
+
+	"github.com/google/uuid"
+)
+
+// workflowAdvanceBatch 单次推进的工作流上限
+const workflowAdvanceBatch = 100
+
+// WorkflowService 工作流服务
+// 节点就绪后才提交任务，由 Leader 定期根据任务终态推进执行中的工作流
+type WorkflowService struct {
+	workflowRepo    repository.WorkflowRepository
+	taskRepo        repository.TaskRepository
+	taskConfigRepo  repository.TaskConfigRepository
+	taskService     *TaskService
+	leaderElection  *redis.LeaderElection
+	advanceInterval time.Duration
+}
+
+// NewWorkflowService 创建工作流服务
+// advanceInterval 为 Leader 检查节点任务状态的间隔
+func NewWorkflowService(
+	workflowRepo repository.WorkflowRepository,
+	taskRepo repository.TaskRepository,
+	taskConfigRepo repository.TaskConfigRepository,
+	taskService *TaskService,
+	leaderElection *redis.LeaderElection,
+	advanceInterval time.Duration,
+) *WorkflowService {
+	return &WorkflowService{
+		workflowRepo:    workflowRepo,
+		taskRepo:        taskRepo,
+		taskConfigRepo:  taskConfigRepo,
+		taskService:     taskService,
+		leaderElection:  leaderElection,
+		advanceInterval: advanceInterval,
+	}
+}
+
+// CreateWorkflow 创建工作流并立即提交没有上游依赖的节点
+func (s *WorkflowService) CreateWorkflow(ctx context.Context, name string, failurePolicy model.FailurePolicy, nodes []*model.WorkflowNode) (*model.Workflow, error) {
+	workflow, err := model.NewWorkflow(uuid.New().String(), name, failurePolicy, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 提前校验任务类型与节点参数，避免工作流执行到一半才发现节点无法提交
+	for _, node := range workflow.Nodes {
+		config, err := s.taskConfigRepo.GetByType(ctx, node.TaskType)
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %s: %v", model.ErrInvalidWorkflow, node.NodeID, err)
+		}
+		if !config.IsEnabled() {
+			return nil, fmt.Errorf("%w: node %s: task type %s is disabled", model.ErrInvalidWorkflow, node.NodeID, node.TaskType)
+		}
+		if err := config.ValidatePayload(node.Payload); err != nil {
+			return nil, fmt.Errorf("%w: node %s: %w", model.ErrInvalidWorkflow, node.NodeID, err)
+		}
+	}
+
+	if err := s.workflowRepo.Create(ctx, workflow); err != nil {
+		return nil, fmt.Errorf("create workflow failed: %w", err)
+	}
+
+	if err := s.advance(ctx, workflow); err != nil {
+		return nil, fmt.Errorf("start workflow failed: %w", err)
+	}
+	return workflow, nil
+}
+
+// GetWorkflow 获取工作流及其节点
+func (s *WorkflowService) GetWorkflow(ctx context.Context, workflowID string) (*model.Workflow, error) {
+	return s.workflowRepo.GetByID(ctx, workflowID)
+}
+
+// Start 启动工作流推进循环，仅在当前实例为 Leader 时推进
+func (s *WorkflowService) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.advanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			isLeader, err := s.leaderElection.IsLeader(ctx)
+			if err != nil || !isLeader {
+				continue
+			}
+			if err := s.advanceRunning(ctx); err != nil {
+				log.Printf("advance workflows failed: %v", err)
+			}
+		}
+	}
+}
+
+// advanceRunning 推进所有执行中的工作流
+func (s *WorkflowService) advanceRunning(ctx context.Context) error {
+	workflows, err := s.workflowRepo.FindRunning(ctx, workflowAdvanceBatch)
+	if err != nil {
+		return fmt.Errorf("find running workflows failed: %w", err)
+	}
+
+	for _, workflow := range workflows {
+		if err := s.advance(ctx, workflow); err != nil {
+			log.Printf("advance workflow %s failed: %v", workflow.WorkflowID, err)
+		}
+	}
+	return nil
+}
+
+// advance 根据节点任务的终态推进工作流：结束节点、应用失败策略、提交就绪节点
+// 节点任务使用由工作流ID和节点ID组成的幂等键，重复推进不会重复创建任务
+// 节点参数不满足 payload schema（例如创建工作流后修改了 schema）时节点直接失败，重试提交也不会成功
+func (s *WorkflowService) advance(ctx context.Context, workflow *model.Workflow) error {
+	before := make(map[string]model.NodeStatus, len(workflow.Nodes))
+	for _, node := range workflow.Nodes {
+		before[node.NodeID] = node.Status
+	}
+
+	for _, node := range workflow.Nodes {
+		if node.Status != model.NodeRunning {
+			continue
+		}
+		task, err := s.taskRepo.GetByID(ctx, node.TaskID)
+		if err != nil {
+			return fmt.Errorf("get task of node %s failed: %w", node.NodeID, err)
+		}
+		if task.IsFinalState() {
+			workflow.FinishNode(node.NodeID, task.Status)
+		}
+	}
+
+	// 有节点被拒绝时重新应用失败策略，其下游可能因此被跳过或（CONTINUE 策略下）变为就绪
+	for rejected := true; rejected; {
+		rejected = false
+
+		for _, node := range workflow.ApplyFailurePolicy() {
+			if err := s.taskService.CancelTask(ctx, node.TaskID); err != nil {
+				log.Printf("cancel task %s of workflow %s failed: %v", node.TaskID, workflow.WorkflowID, err)
+			}
+		}
+
+		for _, node := range workflow.ReadyNodes() {
+			task, _, err := s.taskService.SubmitTask(ctx, CreateTaskParams{
+				TaskType:       node.TaskType,
+				Priority:       node.Priority,
+				Payload:        node.Payload,
+				IdempotencyKey: workflowNodeKey(workflow.WorkflowID, node.NodeID),
+			})
+			if errors.Is(err, model.ErrInvalidPayload) {
+				log.Printf("node %s of workflow %s rejected: %v", node.NodeID, workflow.WorkflowID, err)
+				workflow.RejectNode(node.NodeID)
+				rejected = true
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("submit task of node %s failed: %w", node.NodeID, err)
+			}
+			workflow.StartNode(node.NodeID, task.TaskID)
+		}
+	}
+
+	for _, node := range workflow.Nodes {
+		if node.Status == before[node.NodeID] {
+			continue
+		}
+		if err := s.workflowRepo.UpdateNode(ctx, node); err != nil {
+			return fmt.Errorf("update node %s failed: %w", node.NodeID, err)
+		}
+	}
+
+	if workflow.Status == model.WorkflowRunning && workflow.Refresh() {
+		if err := s.workflowRepo.UpdateStatus(ctx, workflow); err != nil {
+			return fmt.Errorf("update workflow status failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// workflowNodeKeyPrefix 节点任务幂等键的前缀，用于识别属于工作流的任务
+const workflowNodeKeyPrefix = "wf:"
+
+// workflowNodeKey 节点任务的幂等键
+func workflowNodeKey(workflowID, nodeID string) string {
+	return fmt.Sprintf("%s%s:%s", workflowNodeKeyPrefix, workflowID, nodeID)
+}
+
+// isWorkflowNodeTask 判断任务是否由工作流节点提交
+func isWorkflowNodeTask(task *model.Task) bool {
+	return strings.HasPrefix(task.IdempotencyKey, workflowNodeKeyPrefix)
+}
@@ -0,0 +1,303 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/infrastructure/memory"
+)
+
+// newTestWorkflowService 创建使用内存仓储的工作流服务
+func newTestWorkflowService(t *testing.T) *WorkflowService {
+	t.Helper()
+
+	taskService, _, _ := newTestTaskService(t)
+	return NewWorkflowService(memory.NewWorkflowRepository(), taskService.taskRepo, taskService.taskConfigRepo, taskService, nil, 0)
+}
+
+// finishNodeTask 将节点对应的任务置为终态
+func finishNodeTask(t *testing.T, s *WorkflowService, workflowID, nodeID string, status model.TaskStatus) {
+	t.Helper()
+	ctx := context.Background()
+
+	workflow, err := s.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		t.Fatalf("GetWorkflow() error = %v", err)
+	}
+	task, err := s.taskRepo.GetByID(ctx, workflow.Node(nodeID).TaskID)
+	if err != nil {
+		t.Fatalf("get task of node %s failed: %v", nodeID, err)
+	}
+
+	if status == model.StatusSuccess {
+		task.MarkAsSuccess(nil)
+	} else {
+		task.MarkAsFailed("boom")
+	}
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		t.Fatalf("update task failed: %v", err)
+	}
+}
+
+// nodeStatuses 返回工作流中各节点的状态
+func nodeStatuses(workflow *model.Workflow) map[string]model.NodeStatus {
+	statuses := make(map[string]model.NodeStatus, len(workflow.Nodes))
+	for _, node := range workflow.Nodes {
+		statuses[node.NodeID] = node.Status
+	}
+	return statuses
+}
+
+func diamondWorkflowNodes() []*model.WorkflowNode {
+	return []*model.WorkflowNode{
+		{NodeID: "a", TaskType: "example_task"},
+		{NodeID: "b", TaskType: "example_task", DependsOn: []string{"a"}},
+		{NodeID: "c", TaskType: "report_task", DependsOn: []string{"a"}},
+		{NodeID: "d", TaskType: "example_task", DependsOn: []string{"b", "c"}},
+	}
+}
+
+func TestWorkflowService_Advance(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     model.FailurePolicy
+		bStatus    model.TaskStatus
+		wantNodes  map[string]model.NodeStatus
+		wantStatus model.WorkflowStatus
+	}{
+		{
+			name:    "all nodes succeed",
+			bStatus: model.StatusSuccess,
+			wantNodes: map[string]model.NodeStatus{
+				"a": model.NodeSuccess, "b": model.NodeSuccess, "c": model.NodeSuccess, "d": model.NodeSuccess,
+			},
+			wantStatus: model.WorkflowSuccess,
+		},
+		{
+			// b 失败后取消执行中的 c，d 不再提交
+			name:    "fail fast cancels running siblings",
+			policy:  model.FailurePolicyFailFast,
+			bStatus: model.StatusFailed,
+			wantNodes: map[string]model.NodeStatus{
+				"a": model.NodeSuccess, "b": model.NodeFailed, "c": model.NodeFailed, "d": model.NodeSkipped,
+			},
+			wantStatus: model.WorkflowFailed,
+		},
+		{
+			name:    "continue runs downstream nodes",
+			policy:  model.FailurePolicyContinue,
+			bStatus: model.StatusFailed,
+			wantNodes: map[string]model.NodeStatus{
+				"a": model.NodeSuccess, "b": model.NodeFailed, "c": model.NodeSuccess, "d": model.NodeSuccess,
+			},
+			wantStatus: model.WorkflowFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestWorkflowService(t)
+			ctx := context.Background()
+
+			workflow, err := s.CreateWorkflow(ctx, "diamond", tt.policy, diamondWorkflowNodes())
+			if err != nil {
+				t.Fatalf("CreateWorkflow() error = %v", err)
+			}
+			id := workflow.WorkflowID
+
+			// 只有根节点在创建时提交
+			if got := nodeStatuses(workflow); got["a"] != model.NodeRunning || got["b"] != model.NodeWaiting {
+				t.Fatalf("nodes after create = %v, want a RUNNING and others WAITING", got)
+			}
+
+			finishNodeTask(t, s, id, "a", model.StatusSuccess)
+			if err := s.advanceRunning(ctx); err != nil {
+				t.Fatalf("advanceRunning() error = %v", err)
+			}
+			workflow, _ = s.GetWorkflow(ctx, id)
+			if got := nodeStatuses(workflow); got["b"] != model.NodeRunning || got["c"] != model.NodeRunning || got["d"] != model.NodeWaiting {
+				t.Fatalf("nodes after a finished = %v, want b and c RUNNING", got)
+			}
+
+			finishNodeTask(t, s, id, "b", tt.bStatus)
+			if err := s.advanceRunning(ctx); err != nil {
+				t.Fatalf("advanceRunning() error = %v", err)
+			}
+
+			// 重复推进不会重复提交节点任务
+			if err := s.advanceRunning(ctx); err != nil {
+				t.Fatalf("advanceRunning() error = %v", err)
+			}
+
+			workflow, _ = s.GetWorkflow(ctx, id)
+			if workflow.Node("c").Status == model.NodeRunning {
+				finishNodeTask(t, s, id, "c", model.StatusSuccess)
+				if err := s.advanceRunning(ctx); err != nil {
+					t.Fatalf("advanceRunning() error = %v", err)
+				}
+				workflow, _ = s.GetWorkflow(ctx, id)
+			}
+			if workflow.Node("d").Status == model.NodeRunning {
+				finishNodeTask(t, s, id, "d", model.StatusSuccess)
+				if err := s.advanceRunning(ctx); err != nil {
+					t.Fatalf("advanceRunning() error = %v", err)
+				}
+				workflow, _ = s.GetWorkflow(ctx, id)
+			}
+
+			got := nodeStatuses(workflow)
+			for nodeID, want := range tt.wantNodes {
+				if got[nodeID] != want {
+					t.Errorf("node %s = %s, want %s", nodeID, got[nodeID], want)
+				}
+			}
+			if workflow.Status != tt.wantStatus || workflow.CompletedAt == nil {
+				t.Errorf("workflow status = %s, want %s", workflow.Status, tt.wantStatus)
+			}
+
+			_, total, err := s.taskRepo.List(ctx, &repository.TaskQuery{Limit: 10})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			submitted := 0
+			for _, status := range got {
+				if status != model.NodeSkipped {
+					submitted++
+				}
+			}
+			if int(total) != submitted {
+				t.Errorf("tasks created = %d, want %d", total, submitted)
+			}
+		})
+	}
+}
+
+func TestWorkflowService_CreateWorkflow_UnknownTaskType(t *testing.T) {
+	s := newTestWorkflowService(t)
+
+	nodes := []*model.WorkflowNode{
+		{NodeID: "a", TaskType: "example_task"},
+		{NodeID: "b", TaskType: "missing_task", DependsOn: []string{"a"}},
+	}
+	if _, err := s.CreateWorkflow(context.Background(), "broken", "", nodes); !errors.Is(err, model.ErrInvalidWorkflow) {
+		t.Errorf("CreateWorkflow() error = %v, want ErrInvalidWorkflow", err)
+	}
+}
+
+func TestWorkflowService_RetryNodeTask(t *testing.T) {
+	s := newTestWorkflowService(t)
+	ctx := context.Background()
+
+	workflow, err := s.CreateWorkflow(ctx, "diamond", model.FailurePolicyFailFast, diamondWorkflowNodes())
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v", err)
+	}
+	finishNodeTask(t, s, workflow.WorkflowID, "a", model.StatusFailed)
+	if err := s.advance(ctx, workflow); err != nil {
+		t.Fatalf("advance() error = %v", err)
+	}
+
+	// 节点已按失败推进，单独重试节点任务会让工作流与任务状态不一致
+	taskID := workflow.Node("a").TaskID
+	if _, err := s.taskService.RetryTask(ctx, taskID, RetryOptions{}); !errors.Is(err, ErrTaskNotRetryable) {
+		t.Fatalf("RetryTask() error = %v, want ErrTaskNotRetryable", err)
+	}
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if task.Status != model.StatusFailed {
+		t.Errorf("task status = %s, want %s", task.Status, model.StatusFailed)
+	}
+}
+
+// setPayloadSchema 为任务类型配置 payload schema
+func setPayloadSchema(t *testing.T, s *WorkflowService, taskType, schema string) {
+	t.Helper()
+
+	parsed, err := model.ParsePayloadSchema([]byte(schema))
+	if err != nil {
+		t.Fatalf("ParsePayloadSchema() error = %v", err)
+	}
+	config, err := s.taskConfigRepo.GetByType(context.Background(), taskType)
+	if err != nil {
+		t.Fatalf("GetByType() error = %v", err)
+	}
+	config.PayloadSchema = parsed
+}
+
+func TestWorkflowService_CreateWorkflow_InvalidPayload(t *testing.T) {
+	s := newTestWorkflowService(t)
+	ctx := context.Background()
+	setPayloadSchema(t, s, "report_task", `{"required": ["report_id"]}`)
+
+	// 下游节点参数不合法时整个工作流被拒绝，不提交任何节点
+	_, err := s.CreateWorkflow(ctx, "broken", "", diamondWorkflowNodes())
+	if !errors.Is(err, model.ErrInvalidWorkflow) || !errors.Is(err, model.ErrInvalidPayload) {
+		t.Fatalf("CreateWorkflow() error = %v, want ErrInvalidWorkflow and ErrInvalidPayload", err)
+	}
+	if _, total, _ := s.taskRepo.List(ctx, &repository.TaskQuery{Limit: 10}); total != 0 {
+		t.Errorf("tasks created = %d, want 0", total)
+	}
+}
+
+func TestWorkflowService_Advance_InvalidPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    model.FailurePolicy
+		wantNodes map[string]model.NodeStatus
+	}{
+		{
+			name:   "fail fast",
+			policy: model.FailurePolicyFailFast,
+			wantNodes: map[string]model.NodeStatus{
+				"a": model.NodeSuccess, "b": model.NodeFailed, "c": model.NodeFailed, "d": model.NodeSkipped,
+			},
+		},
+		{
+			name:   "skip descendants",
+			policy: model.FailurePolicySkipDescendants,
+			wantNodes: map[string]model.NodeStatus{
+				"a": model.NodeSuccess, "b": model.NodeRunning, "c": model.NodeFailed, "d": model.NodeSkipped,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestWorkflowService(t)
+			ctx := context.Background()
+
+			workflow, err := s.CreateWorkflow(ctx, "diamond", tt.policy, diamondWorkflowNodes())
+			if err != nil {
+				t.Fatalf("CreateWorkflow() error = %v", err)
+			}
+			id := workflow.WorkflowID
+
+			// 创建工作流后 schema 收紧，c 的参数不再合法
+			setPayloadSchema(t, s, "report_task", `{"required": ["report_id"]}`)
+			finishNodeTask(t, s, id, "a", model.StatusSuccess)
+
+			// 被拒绝的节点直接失败，重复推进不会报错
+			for i := 0; i < 2; i++ {
+				if err := s.advance(ctx, workflow); err != nil {
+					t.Fatalf("advance() error = %v", err)
+				}
+			}
+
+			workflow, _ = s.GetWorkflow(ctx, id)
+			got := nodeStatuses(workflow)
+			for nodeID, want := range tt.wantNodes {
+				if got[nodeID] != want {
+					t.Errorf("node %s = %s, want %s", nodeID, got[nodeID], want)
+				}
+			}
+			if workflow.Node("c").TaskID != "" {
+				t.Errorf("rejected node has task %s, want none", workflow.Node("c").TaskID)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// DeadLetter 死信记录，任务重试次数耗尽后进入死信队列，等待人工排查后重新入队或清除
+type DeadLetter struct {
+	ID         int64
+	TaskID     string
+	TaskType   string
+	ErrorMsg   string
+	RetryCount int
+	WorkerID   string
+	CreatedAt  time.Time
+}
+
+// NewDeadLetter 根据重试耗尽的任务创建死信记录
+func NewDeadLetter(task *Task) *DeadLetter {
+	return &DeadLetter{
+		TaskID:     task.TaskID,
+		TaskType:   task.TaskType,
+		ErrorMsg:   task.ErrorMsg,
+		RetryCount: task.RetryCount,
+		WorkerID:   task.WorkerID,
+		CreatedAt:  time.Now(),
+	}
+}
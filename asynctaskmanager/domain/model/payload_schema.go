@@ -0,0 +1,420 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidPayload 任务参数不满足任务类型的 payload schema
+var ErrInvalidPayload = errors.New("invalid payload")
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string // 字段路径，例如 body.items[0].id，为空表示整个 payload
+	Message string
+}
+
+// PayloadValidationError payload 校验失败，包含全部字段错误
+type PayloadValidationError struct {
+	Errors []FieldError
+}
+
+// Error 实现 error 接口
+func (e *PayloadValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		if fieldErr.Field == "" {
+			messages = append(messages, fieldErr.Message)
+		} else {
+			messages = append(messages, fieldErr.Field+" "+fieldErr.Message)
+		}
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidPayload, strings.Join(messages, "; "))
+}
+
+// Unwrap 使 errors.Is(err, ErrInvalidPayload) 成立
+func (e *PayloadValidationError) Unwrap() error {
+	return ErrInvalidPayload
+}
+
+// schemaAnnotations 只作说明、不参与校验的关键字
+var schemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true,
+	"title": true, "description": true, "default": true, "examples": true,
+}
+
+// PayloadSchema 任务参数的 JSON Schema
+// 支持 type、enum、const、properties、required、additionalProperties、items、
+// minLength、maxLength、pattern、minimum、maximum、exclusiveMinimum、exclusiveMaximum、minItems、maxItems，
+// 写入配置时用 ParsePayloadSchema 拒绝其他校验关键字，避免配置了却不生效；
+// 从存储加载时用 LoadPayloadSchema 忽略无法识别的部分，读取配置不会因 schema 失败
+type PayloadSchema struct {
+	raw  json.RawMessage
+	root *schemaNode
+}
+
+// schemaNode 解析后的 schema 节点
+type schemaNode struct {
+	reject bool // false schema，任何值都不满足
+
+	types      []string
+	enum       []interface{}
+	properties map[string]*schemaNode
+	required   []string
+	additional *schemaNode // additionalProperties，nil 表示不限制
+	items      *schemaNode
+
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+	minimum, maximum     *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	minItems, maxItems   *int
+}
+
+// ParsePayloadSchema 严格解析 JSON Schema，含不支持的关键字或关键字取值不合法时报错
+func ParsePayloadSchema(data []byte) (*PayloadSchema, error) {
+	root, err := parseSchemaNode(data, "#", true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload schema: %w", err)
+	}
+	return &PayloadSchema{raw: append(json.RawMessage(nil), data...), root: root}, nil
+}
+
+// LoadPayloadSchema 宽松解析已存储的 JSON Schema，忽略不支持的关键字与取值不合法的关键字
+// 原始 JSON 原样保留，再次写入时不会丢失
+func LoadPayloadSchema(data []byte) *PayloadSchema {
+	root, _ := parseSchemaNode(data, "#", false)
+	return &PayloadSchema{raw: append(json.RawMessage(nil), data...), root: root}
+}
+
+// Raw 返回 schema 的原始 JSON
+func (s *PayloadSchema) Raw() json.RawMessage {
+	return s.raw
+}
+
+// Validate 校验 payload，不满足时返回 *PayloadValidationError
+func (s *PayloadSchema) Validate(payload map[string]interface{}) error {
+	var value interface{} = payload
+	if payload == nil {
+		value = map[string]interface{}{}
+	}
+
+	var errs []FieldError
+	s.root.validate(value, "", &errs)
+	if len(errs) > 0 {
+		return &PayloadValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// parseSchemaNode 解析 schema 节点，at 为节点在 schema 中的位置，用于错误信息
+// strict 为 false 时跳过无法解析的关键字（不是对象的节点视为不限制），不会返回错误
+func parseSchemaNode(data []byte, at string, strict bool) (*schemaNode, error) {
+	data = bytes.TrimSpace(data)
+	switch string(data) {
+	case "true":
+		return &schemaNode{}, nil
+	case "false":
+		return &schemaNode{reject: true}, nil
+	}
+
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		if !strict {
+			return &schemaNode{}, nil
+		}
+		return nil, fmt.Errorf("%s: schema must be an object or boolean", at)
+	}
+
+	node := &schemaNode{}
+	for keyword, value := range keywords {
+		parsed := *node // 宽松模式下关键字解析失败时回退到解析前的状态
+		var err error
+		switch keyword {
+		case "type":
+			node.types, err = parseSchemaTypes(value)
+		case "enum":
+			err = json.Unmarshal(value, &node.enum)
+		case "const":
+			var constant interface{}
+			err = json.Unmarshal(value, &constant)
+			node.enum = []interface{}{constant}
+		case "properties":
+			var properties map[string]json.RawMessage
+			if err = json.Unmarshal(value, &properties); err == nil {
+				node.properties = make(map[string]*schemaNode, len(properties))
+				for name, property := range properties {
+					if node.properties[name], err = parseSchemaNode(property, at+"/properties/"+name, strict); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case "required":
+			err = json.Unmarshal(value, &node.required)
+		case "additionalProperties":
+			node.additional, err = parseSchemaNode(value, at+"/additionalProperties", strict)
+			if err != nil {
+				return nil, err
+			}
+		case "items":
+			node.items, err = parseSchemaNode(value, at+"/items", strict)
+			if err != nil {
+				return nil, err
+			}
+		case "minLength":
+			node.minLength, err = parseSchemaCount(value)
+		case "maxLength":
+			node.maxLength, err = parseSchemaCount(value)
+		case "minItems":
+			node.minItems, err = parseSchemaCount(value)
+		case "maxItems":
+			node.maxItems, err = parseSchemaCount(value)
+		case "pattern":
+			var pattern string
+			if err = json.Unmarshal(value, &pattern); err == nil {
+				node.pattern, err = regexp.Compile(pattern)
+			}
+		case "minimum":
+			err = json.Unmarshal(value, &node.minimum)
+		case "maximum":
+			err = json.Unmarshal(value, &node.maximum)
+		case "exclusiveMinimum":
+			err = json.Unmarshal(value, &node.exclusiveMinimum)
+		case "exclusiveMaximum":
+			err = json.Unmarshal(value, &node.exclusiveMaximum)
+		default:
+			if strict && !schemaAnnotations[keyword] {
+				return nil, fmt.Errorf("%s: unsupported keyword %q", at, keyword)
+			}
+		}
+		if err != nil {
+			if !strict {
+				*node = parsed
+				continue
+			}
+			return nil, fmt.Errorf("%s/%s: %w", at, keyword, err)
+		}
+	}
+	return node, nil
+}
+
+// parseSchemaTypes 解析 type 关键字，可以是单个类型或类型数组
+func parseSchemaTypes(value json.RawMessage) ([]string, error) {
+	var types []string
+	var single string
+	if err := json.Unmarshal(value, &single); err == nil {
+		types = []string{single}
+	} else if err := json.Unmarshal(value, &types); err != nil {
+		return nil, errors.New("type must be a string or an array of strings")
+	}
+
+	for _, t := range types {
+		switch t {
+		case "null", "boolean", "object", "array", "number", "integer", "string":
+		default:
+			return nil, fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return types, nil
+}
+
+// parseSchemaCount 解析非负整数关键字
+func parseSchemaCount(value json.RawMessage) (*int, error) {
+	var count int
+	if err := json.Unmarshal(value, &count); err != nil || count < 0 {
+		return nil, errors.New("must be a non-negative integer")
+	}
+	return &count, nil
+}
+
+// validate 校验值并将错误追加到 errs，path 为值在 payload 中的路径
+func (n *schemaNode) validate(value interface{}, path string, errs *[]FieldError) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if n.reject {
+		fail("is not allowed")
+		return
+	}
+
+	actual := jsonTypeOf(value)
+	if len(n.types) > 0 && !n.matchesType(value, actual) {
+		fail("must be %s, got %s", strings.Join(n.types, " or "), actual)
+		return
+	}
+
+	if len(n.enum) > 0 && !containsJSONValue(n.enum, value) {
+		allowed, _ := json.Marshal(n.enum)
+		fail("must be one of %s", allowed)
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if n.minLength != nil && length < *n.minLength {
+			fail("must be at least %d characters", *n.minLength)
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			fail("must be at most %d characters", *n.maxLength)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(v) {
+			fail("must match pattern %s", n.pattern)
+		}
+	case map[string]interface{}:
+		n.validateObject(v, path, errs)
+	case []interface{}:
+		if n.minItems != nil && len(v) < *n.minItems {
+			fail("must have at least %d items", *n.minItems)
+		}
+		if n.maxItems != nil && len(v) > *n.maxItems {
+			fail("must have at most %d items", *n.maxItems)
+		}
+		if n.items != nil {
+			for i, item := range v {
+				n.items.validate(item, path+"["+strconv.Itoa(i)+"]", errs)
+			}
+		}
+	default:
+		if number, ok := toJSONNumber(value); ok {
+			n.validateNumber(number, fail)
+		}
+	}
+}
+
+// validateObject 校验对象的必填字段与各属性，按字段名排序以保证错误顺序稳定
+func (n *schemaNode) validateObject(object map[string]interface{}, path string, errs *[]FieldError) {
+	for _, name := range n.required {
+		if _, ok := object[name]; !ok {
+			*errs = append(*errs, FieldError{Field: joinFieldPath(path, name), Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := n.properties[name]
+		if !ok {
+			property = n.additional
+		}
+		if property != nil {
+			property.validate(object[name], joinFieldPath(path, name), errs)
+		}
+	}
+}
+
+// validateNumber 校验数值范围
+func (n *schemaNode) validateNumber(number float64, fail func(format string, args ...interface{})) {
+	if n.minimum != nil && number < *n.minimum {
+		fail("must be >= %v", *n.minimum)
+	}
+	if n.maximum != nil && number > *n.maximum {
+		fail("must be <= %v", *n.maximum)
+	}
+	if n.exclusiveMinimum != nil && number <= *n.exclusiveMinimum {
+		fail("must be > %v", *n.exclusiveMinimum)
+	}
+	if n.exclusiveMaximum != nil && number >= *n.exclusiveMaximum {
+		fail("must be < %v", *n.exclusiveMaximum)
+	}
+}
+
+// matchesType 判断值是否满足 type，integer 匹配没有小数部分的数值
+func (n *schemaNode) matchesType(value interface{}, actual string) bool {
+	for _, t := range n.types {
+		if t == actual {
+			return true
+		}
+		if t == "integer" && actual == "number" {
+			if number, _ := toJSONNumber(value); number == math.Trunc(number) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonTypeOf 返回值对应的 JSON 类型
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if _, ok := toJSONNumber(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// toJSONNumber 将 Go 数值类型转换为 float64
+func toJSONNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		number, err := v.Float64()
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// containsJSONValue 判断 values 中是否有与 value 相等的 JSON 值，数值按大小比较
+func containsJSONValue(values []interface{}, value interface{}) bool {
+	number, isNumber := toJSONNumber(value)
+	for _, candidate := range values {
+		if isNumber {
+			if other, ok := toJSONNumber(candidate); ok && other == number {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinFieldPath 拼接字段路径
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const httpPayloadSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["url"],
+	"properties": {
+		"url": {"type": "string", "pattern": "^https?://", "description": "请求地址"},
+		"method": {"enum": ["GET", "POST", "PUT", "DELETE"]},
+		"retries": {"type": "integer", "minimum": 0, "maximum": 5},
+		"body": {
+			"type": "object",
+			"properties": {
+				"ids": {"type": "array", "items": {"type": "integer"}, "minItems": 1}
+			}
+		}
+	},
+	"additionalProperties": false
+}`
+
+func TestParsePayloadSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{"常用关键字", httpPayloadSchema, false},
+		{"布尔 schema", `true`, false},
+		{"不是 JSON", `{`, true},
+		{"不是对象", `"object"`, true},
+		{"未知类型", `{"type": "date"}`, true},
+		{"不支持的关键字", `{"oneOf": [{"type": "string"}]}`, true},
+		{"嵌套的不支持关键字", `{"properties": {"a": {"$ref": "#/defs/a"}}}`, true},
+		{"正则不合法", `{"pattern": "("}`, true},
+		{"长度为负", `{"minLength": -1}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePayloadSchema([]byte(tt.schema))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePayloadSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadPayloadSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		payload map[string]interface{}
+		want    []FieldError
+	}{
+		{
+			name:    "忽略不支持的关键字，其余关键字照常校验",
+			schema:  `{"required": ["url"], "oneOf": [{"type": "string"}], "properties": {"url": {"$ref": "#/defs/url", "type": "string"}}}`,
+			payload: map[string]interface{}{"url": 1},
+			want:    []FieldError{{Field: "url", Message: "must be string, got number"}},
+		},
+		{
+			name:    "忽略取值不合法的关键字",
+			schema:  `{"required": ["url"], "minLength": -1, "pattern": "(", "type": "date"}`,
+			payload: map[string]interface{}{},
+			want:    []FieldError{{Field: "url", Message: "is required"}},
+		},
+		{
+			name:    "不是对象时不限制",
+			schema:  `"object"`,
+			payload: map[string]interface{}{"url": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := LoadPayloadSchema([]byte(tt.schema))
+			if string(schema.Raw()) != tt.schema {
+				t.Errorf("Raw() = %s, want %s", schema.Raw(), tt.schema)
+			}
+
+			err := schema.Validate(tt.payload)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *PayloadValidationError
+			if !errors.As(err, &validationErr) || !reflect.DeepEqual(validationErr.Errors, tt.want) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadSchema_Validate(t *testing.T) {
+	schema, err := ParsePayloadSchema([]byte(httpPayloadSchema))
+	if err != nil {
+		t.Fatalf("ParsePayloadSchema() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		payload map[string]interface{}
+		want    []FieldError
+	}{
+		{
+			name: "合法",
+			payload: map[string]interface{}{
+				"url":     "https://example.com",
+				"method":  "POST",
+				"retries": float64(3),
+				"body":    map[string]interface{}{"ids": []interface{}{float64(1), 2}},
+			},
+		},
+		{
+			name:    "缺少必填字段",
+			payload: map[string]interface{}{"method": "GET"},
+			want:    []FieldError{{Field: "url", Message: "is required"}},
+		},
+		{
+			name:    "nil 按空对象校验",
+			payload: nil,
+			want:    []FieldError{{Field: "url", Message: "is required"}},
+		},
+		{
+			name: "多个字段错误按字段名排序",
+			payload: map[string]interface{}{
+				"url":     "ftp://example.com",
+				"method":  "PATCH",
+				"retries": 1.5,
+				"extra":   true,
+			},
+			want: []FieldError{
+				{Field: "extra", Message: "is not allowed"},
+				{Field: "method", Message: `must be one of ["GET","POST","PUT","DELETE"]`},
+				{Field: "retries", Message: "must be integer, got number"},
+				{Field: "url", Message: "must match pattern ^https?://"},
+			},
+		},
+		{
+			name:    "旧接口传入的字符串不满足数值类型",
+			payload: map[string]interface{}{"url": "http://example.com", "retries": "3"},
+			want:    []FieldError{{Field: "retries", Message: "must be integer, got string"}},
+		},
+		{
+			name:    "超出范围",
+			payload: map[string]interface{}{"url": "http://example.com", "retries": 9},
+			want:    []FieldError{{Field: "retries", Message: "must be <= 5"}},
+		},
+		{
+			name: "嵌套字段与数组元素",
+			payload: map[string]interface{}{
+				"url":  "http://example.com",
+				"body": map[string]interface{}{"ids": []interface{}{float64(1), "two"}},
+			},
+			want: []FieldError{{Field: "body.ids[1]", Message: "must be integer, got string"}},
+		},
+		{
+			name: "数组元素过少",
+			payload: map[string]interface{}{
+				"url":  "http://example.com",
+				"body": map[string]interface{}{"ids": []interface{}{}},
+			},
+			want: []FieldError{{Field: "body.ids", Message: "must have at least 1 items"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate(tt.payload)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *PayloadValidationError
+			if !errors.As(err, &validationErr) || !errors.Is(err, ErrInvalidPayload) {
+				t.Fatalf("Validate() error = %v, want PayloadValidationError", err)
+			}
+			if !reflect.DeepEqual(validationErr.Errors, tt.want) {
+				t.Errorf("Validate() errors = %v, want %v", validationErr.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskConfig_ValidatePayload(t *testing.T) {
+	config := &TaskConfig{TaskType: "http_request"}
+	if err := config.ValidatePayload(nil); err != nil {
+		t.Errorf("ValidatePayload() without schema error = %v", err)
+	}
+
+	schema, err := ParsePayloadSchema([]byte(`{"required": ["url"]}`))
+	if err != nil {
+		t.Fatalf("ParsePayloadSchema() error = %v", err)
+	}
+	config.PayloadSchema = schema
+	if err := config.ValidatePayload(map[string]interface{}{}); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("ValidatePayload() error = %v, want ErrInvalidPayload", err)
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -19,11 +20,24 @@ const (
 // TaskPriority 任务优先级（值对象）
 type TaskPriority int
 
+// 优先级取值范围为 PriorityMin 到 PriorityMax，数值越大越优先
+// PriorityNormal 与 PriorityHigh 保留只有两级优先级时的取值
 const (
 	PriorityNormal TaskPriority = 0 // 普通优先级
 	PriorityHigh   TaskPriority = 1 // 高优先级
+
+	PriorityMin TaskPriority = 0 // 最低优先级
+	PriorityMax TaskPriority = 9 // 最高优先级
 )
 
+// PriorityLevels 优先级级数
+const PriorityLevels = int(PriorityMax-PriorityMin) + 1
+
+// IsValid 判断优先级是否在取值范围内
+func (p TaskPriority) IsValid() bool {
+	return p >= PriorityMin && p <= PriorityMax
+}
+
 // IsHigh 判断是否是高优先级
 func (p TaskPriority) IsHigh() bool {
 	return p == PriorityHigh
@@ -41,9 +55,11 @@ func (p TaskPriority) String() string {
 		return "HIGH"
 	case PriorityNormal:
 		return "NORMAL"
-	default:
-		return "UNKNOWN"
 	}
+	if p.IsValid() {
+		return fmt.Sprintf("P%d", p)
+	}
+	return "UNKNOWN"
 }
 
 // Value 返回优先级数值
@@ -70,6 +86,17 @@ type Task struct {
 	CompletedAt *time.Time
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	// FencingToken 最近一次由 Leader 写入时的任期 token，用于拒绝过期 Leader 的写入
+	FencingToken int64
+	// IdempotencyKey 客户端提供的幂等键，同一任务类型下唯一，为空表示不去重
+	IdempotencyKey string
+	// Tenant 任务所属租户（队列），各租户的就绪队列独立并按配额轮流调度
+	Tenant string
+	// ResultURI 结果转存到结果存储时的地址，此时 Result 为空；为空表示结果保存在 Result 中
+	ResultURI string
+	// ResultSize 转存结果的字节数（JSON 编码）
+	ResultSize int64
 }
 
 // CanRetry 判断任务是否可以重试
@@ -85,6 +112,13 @@ func (t *Task) MarkAsProcessing(workerID string) {
 	t.StartedAt = &now
 }
 
+// Unassign 撤销尚未送达 Worker 的分配，任务回到待处理
+func (t *Task) Unassign() {
+	t.Status = StatusPending
+	t.WorkerID = ""
+	t.StartedAt = nil
+}
+
 // MarkAsSuccess 标记任务为成功
 func (t *Task) MarkAsSuccess(result map[string]interface{}) {
 	t.Status = StatusSuccess
@@ -93,6 +127,18 @@ func (t *Task) MarkAsSuccess(result map[string]interface{}) {
 	t.CompletedAt = &now
 }
 
+// OffloadResult 记录已转存到结果存储的结果，任务中不再保留结果内容
+func (t *Task) OffloadResult(uri string, size int64) {
+	t.Result = nil
+	t.ResultURI = uri
+	t.ResultSize = size
+}
+
+// HasOffloadedResult 判断结果是否已转存到结果存储
+func (t *Task) HasOffloadedResult() bool {
+	return t.ResultURI != ""
+}
+
 // MarkAsFailed 标记任务为失败
 func (t *Task) MarkAsFailed(errorMsg string) {
 	t.Status = StatusFailed
@@ -125,6 +171,28 @@ func (t *Task) MarkAsRetrying() {
 	t.CompletedAt = nil
 }
 
+// ScheduleRetry 标记任务为重试中，并在 nextRetryAt 之前不再执行
+func (t *Task) ScheduleRetry(nextRetryAt time.Time) {
+	t.MarkAsRetrying()
+	t.ScheduledAt = nextRetryAt
+}
+
+// Requeue 将已结束的任务重置为待处理，resetRetries 为 true 时重新计算重试次数
+func (t *Task) Requeue(resetRetries bool) {
+	t.Status = StatusPending
+	t.Result = nil
+	t.ResultURI = ""
+	t.ResultSize = 0
+	t.ErrorMsg = ""
+	t.WorkerID = ""
+	t.StartedAt = nil
+	t.CompletedAt = nil
+	t.ScheduledAt = time.Now()
+	if resetRetries {
+		t.RetryCount = 0
+	}
+}
+
 // IsTimeout 判断任务是否超时
 func (t *Task) IsTimeout() bool {
 	if t.Status != StatusProcessing || t.StartedAt == nil {
@@ -133,6 +201,11 @@ func (t *Task) IsTimeout() bool {
 	return time.Since(*t.StartedAt) > time.Duration(t.Timeout)*time.Second
 }
 
+// IsDelayed 判断任务是否尚未到达计划执行时间
+func (t *Task) IsDelayed(now time.Time) bool {
+	return t.ScheduledAt.After(now)
+}
+
 // IsFinalState 判断是否是终态
 func (t *Task) IsFinalState() bool {
 	return t.Status == StatusSuccess || t.Status == StatusCancelled ||
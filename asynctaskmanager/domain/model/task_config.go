@@ -2,6 +2,7 @@ package model
 
 import (
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -33,28 +34,51 @@ type TaskConfig struct {
 	DefaultTimeout  int
 	DefaultMaxRetry int
 	RetryStrategy   RetryStrategy
-	RetryDelay      int
-	BackoffRate     float64
-	MaxConcurrent   int
+	RetryDelay      int            // 重试基础延迟（秒）
+	BackoffRate     float64        // 指数退避倍率
+	MaxRetryDelay   int            // 重试延迟上限（秒），0 表示不限制
+	RetryJitter     float64        // 随机抖动比例（0~1），实际延迟在 [delay, delay*(1+jitter)] 之间
+	MaxConcurrent   int            // 同类型任务在集群内的最大并发数，0 表示不限制
+	RateLimit       float64        // 每秒分发的任务数上限（令牌桶速率），0 表示不限制
+	RateBurst       int            // 令牌桶容量，允许的突发分发数，小于 1 时按 1 处理
+	PayloadSchema   *PayloadSchema // payload 的 JSON Schema，nil 表示不校验
 	Enabled         bool
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
 
-// CalculateNextRetryTime 计算下次重试时间
-func (tc *TaskConfig) CalculateNextRetryTime(retryCount int) time.Time {
-	var delay time.Duration
+// CalculateRetryDelay 计算重试延迟
+// retryCount 为本次重试前已重试的次数，首次重试使用基础延迟
+func (tc *TaskConfig) CalculateRetryDelay(retryCount int) time.Duration {
+	delay := float64(tc.RetryDelay) * float64(time.Second)
 
-	if tc.RetryStrategy == RetryStrategyExponential {
+	if tc.RetryStrategy == RetryStrategyExponential && tc.BackoffRate > 0 {
 		// 指数退避
-		delay = time.Duration(tc.RetryDelay) * time.Second *
-			time.Duration(math.Pow(tc.BackoffRate, float64(retryCount)))
-	} else {
-		// 固定间隔
-		delay = time.Duration(tc.RetryDelay) * time.Second
+		delay *= math.Pow(tc.BackoffRate, float64(retryCount))
+	}
+
+	// 随机抖动，避免大量任务同时重试
+	if tc.RetryJitter > 0 {
+		delay += delay * tc.RetryJitter * rand.Float64()
+	}
+
+	// 延迟上限
+	if maxDelay := float64(tc.MaxRetryDelay) * float64(time.Second); maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+
+	switch {
+	case delay <= 0:
+		return 0
+	case delay >= float64(math.MaxInt64):
+		return time.Duration(math.MaxInt64)
 	}
+	return time.Duration(delay)
+}
 
-	return time.Now().Add(delay)
+// CalculateNextRetryTime 计算下次重试时间
+func (tc *TaskConfig) CalculateNextRetryTime(retryCount int) time.Time {
+	return time.Now().Add(tc.CalculateRetryDelay(retryCount))
 }
 
 // IsEnabled 判断任务配置是否启用
@@ -62,6 +86,14 @@ func (tc *TaskConfig) IsEnabled() bool {
 	return tc.Enabled
 }
 
+// ValidatePayload 按 PayloadSchema 校验任务参数，未配置 schema 时不校验
+func (tc *TaskConfig) ValidatePayload(payload map[string]interface{}) error {
+	if tc.PayloadSchema == nil {
+		return nil
+	}
+	return tc.PayloadSchema.Validate(payload)
+}
+
 // CreateTask 创建任务实例
 func (tc *TaskConfig) CreateTask(taskID string, priority TaskPriority, payload map[string]interface{}) *Task {
 	return &Task{
@@ -75,5 +107,6 @@ func (tc *TaskConfig) CreateTask(taskID string, priority TaskPriority, payload m
 		ScheduledAt: time.Now(),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+		Tenant:      DefaultTenant,
 	}
 }
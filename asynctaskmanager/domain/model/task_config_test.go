@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskConfig_CalculateRetryDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     *TaskConfig
+		retryCount int
+		want       time.Duration
+	}{
+		{
+			name:       "fixed strategy ignores retry count",
+			config:     &TaskConfig{RetryStrategy: RetryStrategyFixed, RetryDelay: 5, BackoffRate: 2},
+			retryCount: 3,
+			want:       5 * time.Second,
+		},
+		{
+			name:       "exponential first retry uses base delay",
+			config:     &TaskConfig{RetryStrategy: RetryStrategyExponential, RetryDelay: 5, BackoffRate: 2},
+			retryCount: 0,
+			want:       5 * time.Second,
+		},
+		{
+			name:       "exponential grows by backoff rate",
+			config:     &TaskConfig{RetryStrategy: RetryStrategyExponential, RetryDelay: 5, BackoffRate: 2},
+			retryCount: 3,
+			want:       40 * time.Second,
+		},
+		{
+			name:       "fractional backoff rate",
+			config:     &TaskConfig{RetryStrategy: RetryStrategyExponential, RetryDelay: 10, BackoffRate: 1.5},
+			retryCount: 1,
+			want:       15 * time.Second,
+		},
+		{
+			name:       "capped by max retry delay",
+			config:     &TaskConfig{RetryStrategy: RetryStrategyExponential, RetryDelay: 5, BackoffRate: 2, MaxRetryDelay: 30},
+			retryCount: 10,
+			want:       30 * time.Second,
+		},
+		{
+			name:       "huge exponent stays capped",
+			config:     &TaskConfig{RetryStrategy: RetryStrategyExponential, RetryDelay: 5, BackoffRate: 10, MaxRetryDelay: 60},
+			retryCount: 400,
+			want:       60 * time.Second,
+		},
+		{
+			name:       "zero delay",
+			config:     &TaskConfig{RetryStrategy: RetryStrategyFixed},
+			retryCount: 1,
+			want:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.CalculateRetryDelay(tt.retryCount); got != tt.want {
+				t.Errorf("TaskConfig.CalculateRetryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskConfig_CalculateRetryDelay_Jitter(t *testing.T) {
+	config := &TaskConfig{
+		RetryStrategy: RetryStrategyFixed,
+		RetryDelay:    10,
+		RetryJitter:   0.5,
+		MaxRetryDelay: 14,
+	}
+
+	for i := 0; i < 100; i++ {
+		got := config.CalculateRetryDelay(0)
+		if got < 10*time.Second || got > 14*time.Second {
+			t.Fatalf("TaskConfig.CalculateRetryDelay() = %v, want within [10s, 14s]", got)
+		}
+	}
+}
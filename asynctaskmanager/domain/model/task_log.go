@@ -41,6 +41,18 @@ func NewStateChangeLog(taskID string, fromStatus, toStatus TaskStatus, workerID,
 	}
 }
 
+// WithTransition 记录日志伴随的状态变更，用于重试、失败等非 STATE_CHANGE 类型的日志
+func (l *TaskLog) WithTransition(fromStatus, toStatus TaskStatus) *TaskLog {
+	l.FromStatus = fromStatus
+	l.ToStatus = toStatus
+	return l
+}
+
+// IsStateChange 判断日志是否记录了一次状态变更
+func (l *TaskLog) IsStateChange() bool {
+	return l.ToStatus != ""
+}
+
 // NewRetryLog 创建重试日志
 func NewRetryLog(taskID string, retryCount int, message string) *TaskLog {
 	return &TaskLog{
@@ -51,7 +51,10 @@ func TestTaskPriority_String(t *testing.T) {
 	}{
 		{"high priority", PriorityHigh, "HIGH"},
 		{"normal priority", PriorityNormal, "NORMAL"},
+		{"numbered priority", TaskPriority(5), "P5"},
+		{"max priority", PriorityMax, "P9"},
 		{"unknown priority", TaskPriority(99), "UNKNOWN"},
+		{"negative priority", TaskPriority(-1), "UNKNOWN"},
 	}
 
 	for _, tt := range tests {
@@ -63,6 +66,28 @@ func TestTaskPriority_String(t *testing.T) {
 	}
 }
 
+func TestTaskPriority_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority TaskPriority
+		want     bool
+	}{
+		{"min priority", PriorityMin, true},
+		{"max priority", PriorityMax, true},
+		{"middle priority", TaskPriority(4), true},
+		{"below min", PriorityMin - 1, false},
+		{"above max", PriorityMax + 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.priority.IsValid(); got != tt.want {
+				t.Errorf("TaskPriority.IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTaskPriority_Value(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -282,6 +307,30 @@ func TestTask_MarkAsRetrying(t *testing.T) {
 	}
 }
 
+func TestTask_ScheduleRetry(t *testing.T) {
+	next := time.Now().Add(time.Minute)
+	task := &Task{
+		Status:     StatusFailed,
+		RetryCount: 1,
+		WorkerID:   "worker-1",
+	}
+
+	task.ScheduleRetry(next)
+
+	if task.Status != StatusPending {
+		t.Errorf("Task.Status = %v, want %v", task.Status, StatusPending)
+	}
+	if task.RetryCount != 2 {
+		t.Errorf("Task.RetryCount = %v, want 2", task.RetryCount)
+	}
+	if !task.ScheduledAt.Equal(next) {
+		t.Errorf("Task.ScheduledAt = %v, want %v", task.ScheduledAt, next)
+	}
+	if !task.IsDelayed(time.Now()) {
+		t.Error("Task.IsDelayed() = false, want true")
+	}
+}
+
 func TestTask_IsTimeout(t *testing.T) {
 	now := time.Now()
 
@@ -337,6 +386,40 @@ func TestTask_IsTimeout(t *testing.T) {
 	}
 }
 
+func TestTask_IsDelayed(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		task *Task
+		want bool
+	}{
+		{
+			name: "scheduled in the future is delayed",
+			task: &Task{ScheduledAt: now.Add(time.Minute)},
+			want: true,
+		},
+		{
+			name: "scheduled now is not delayed",
+			task: &Task{ScheduledAt: now},
+			want: false,
+		},
+		{
+			name: "scheduled in the past is not delayed",
+			task: &Task{ScheduledAt: now.Add(-time.Minute)},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.IsDelayed(now); got != tt.want {
+				t.Errorf("Task.IsDelayed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTask_IsFinalState(t *testing.T) {
 	tests := []struct {
 		name string
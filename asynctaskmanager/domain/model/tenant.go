@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// DefaultTenant 未指定租户的任务所属的默认租户
+const DefaultTenant = "default"
+
+// MaxTenantLength 租户名的最大长度，与 task.tenant 列宽一致
+const MaxTenantLength = 64
+
+// ErrInvalidTenant 租户名不合法
+var ErrInvalidTenant = errors.New("invalid tenant")
+
+// tenantPattern 租户名只允许字母、数字、下划线、中划线和点，租户名会拼入 Redis 键
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// NormalizeTenant 校验租户名，空字符串返回默认租户
+func NormalizeTenant(tenant string) (string, error) {
+	if tenant == "" {
+		return DefaultTenant, nil
+	}
+	if len(tenant) > MaxTenantLength {
+		return "", fmt.Errorf("%w: exceeds %d bytes", ErrInvalidTenant, MaxTenantLength)
+	}
+	if !tenantPattern.MatchString(tenant) {
+		return "", fmt.Errorf("%w: %q may only contain letters, digits, '_', '-' and '.'", ErrInvalidTenant, tenant)
+	}
+	return tenant, nil
+}
+
+// TenantConfig 租户调度配置（聚合根），未配置的租户按默认配额调度且不限制并发
+type TenantConfig struct {
+	ID          int64
+	Tenant      string
+	Quantum     int     // 调度配额：轮到该租户时本轮最多出队的任务数（DRR quantum），小于 1 时按 1 处理
+	MaxInFlight int     // 租户在集群内同时执行的最大任务数，0 表示不限制
+	RateLimit   float64 // 租户每秒分发的任务数上限（令牌桶速率），0 表示不限制
+	RateBurst   int     // 租户令牌桶容量，允许的突发分发数，小于 1 时按 1 处理
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// EffectiveQuantum 返回生效的调度配额
+func (tc *TenantConfig) EffectiveQuantum() int {
+	return max(tc.Quantum, 1)
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTenant(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenant  string
+		want    string
+		wantErr bool
+	}{
+		{"empty tenant uses default", "", DefaultTenant, false},
+		{"plain tenant", "team-a", "team-a", false},
+		{"dots and underscores", "data.backfill_v2", "data.backfill_v2", false},
+		{"colon is rejected", "team:a", "", true},
+		{"space is rejected", "team a", "", true},
+		{"too long", strings.Repeat("t", MaxTenantLength+1), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTenant(tt.tenant)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeTenant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTenant) {
+				t.Errorf("NormalizeTenant() error = %v, want ErrInvalidTenant", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeTenant() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantConfig_EffectiveQuantum(t *testing.T) {
+	tests := []struct {
+		name    string
+		quantum int
+		want    int
+	}{
+		{"configured quantum", 5, 5},
+		{"zero quantum", 0, 1},
+		{"negative quantum", -3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &TenantConfig{Tenant: "team-a", Quantum: tt.quantum}
+			if got := config.EffectiveQuantum(); got != tt.want {
+				t.Errorf("EffectiveQuantum() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidWorkflow 工作流定义不合法（节点重复、依赖不存在或存在环等）
+var ErrInvalidWorkflow = errors.New("invalid workflow")
+
+// MaxNodeIDLength 节点ID的最大长度
+const MaxNodeIDLength = 64
+
+// WorkflowStatus 工作流状态
+type WorkflowStatus string
+
+const (
+	WorkflowRunning WorkflowStatus = "RUNNING" // 执行中
+	WorkflowSuccess WorkflowStatus = "SUCCESS" // 所有节点成功
+	WorkflowFailed  WorkflowStatus = "FAILED"  // 存在失败或被跳过的节点
+)
+
+// FailurePolicy 节点失败后的处理策略
+type FailurePolicy string
+
+const (
+	FailurePolicyFailFast        FailurePolicy = "FAIL_FAST"        // 取消执行中的节点并跳过所有未开始的节点
+	FailurePolicyContinue        FailurePolicy = "CONTINUE"         // 失败节点视为已结束，下游节点照常执行
+	FailurePolicySkipDescendants FailurePolicy = "SKIP_DESCENDANTS" // 跳过失败节点的所有下游节点，其他分支继续执行
+)
+
+// IsValid 判断策略是否合法
+func (p FailurePolicy) IsValid() bool {
+	switch p {
+	case FailurePolicyFailFast, FailurePolicyContinue, FailurePolicySkipDescendants:
+		return true
+	default:
+		return false
+	}
+}
+
+// NodeStatus 工作流节点状态
+type NodeStatus string
+
+const (
+	NodeWaiting NodeStatus = "WAITING" // 等待上游节点完成
+	NodeRunning NodeStatus = "RUNNING" // 已提交任务
+	NodeSuccess NodeStatus = "SUCCESS" // 任务成功
+	NodeFailed  NodeStatus = "FAILED"  // 任务失败、超时、被取消或无法提交
+	NodeSkipped NodeStatus = "SKIPPED" // 因失败策略被跳过，未提交任务
+)
+
+// IsFinished 判断节点是否已结束
+func (s NodeStatus) IsFinished() bool {
+	return s == NodeSuccess || s == NodeFailed || s == NodeSkipped
+}
+
+// WorkflowNode 工作流节点（实体），节点就绪后才创建对应的任务
+type WorkflowNode struct {
+	ID         int64
+	WorkflowID string
+	NodeID     string
+	TaskType   string
+	Priority   TaskPriority
+	Payload    map[string]interface{}
+	DependsOn  []string
+	TaskID     string
+	Status     NodeStatus
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Workflow 工作流（聚合根），节点之间的依赖构成有向无环图
+type Workflow struct {
+	ID            int64
+	WorkflowID    string
+	Name          string
+	Status        WorkflowStatus
+	FailurePolicy FailurePolicy
+	Nodes         []*WorkflowNode
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   *time.Time
+}
+
+// NewWorkflow 创建工作流并校验依赖关系，failurePolicy 为空时使用 FAIL_FAST
+func NewWorkflow(workflowID, name string, failurePolicy FailurePolicy, nodes []*WorkflowNode) (*Workflow, error) {
+	if failurePolicy == "" {
+		failurePolicy = FailurePolicyFailFast
+	}
+
+	now := time.Now()
+	wf := &Workflow{
+		WorkflowID:    workflowID,
+		Name:          name,
+		Status:        WorkflowRunning,
+		FailurePolicy: failurePolicy,
+		Nodes:         nodes,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := wf.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		node.WorkflowID = workflowID
+		node.Status = NodeWaiting
+		node.CreatedAt = now
+		node.UpdatedAt = now
+	}
+	return wf, nil
+}
+
+// Validate 校验失败策略、节点ID唯一、依赖存在且无环
+func (wf *Workflow) Validate() error {
+	if !wf.FailurePolicy.IsValid() {
+		return fmt.Errorf("%w: unknown failure policy %q", ErrInvalidWorkflow, wf.FailurePolicy)
+	}
+	if len(wf.Nodes) == 0 {
+		return fmt.Errorf("%w: no nodes", ErrInvalidWorkflow)
+	}
+
+	nodes := make(map[string]*WorkflowNode, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		if node.NodeID == "" || len(node.NodeID) > MaxNodeIDLength {
+			return fmt.Errorf("%w: node id must be 1-%d bytes", ErrInvalidWorkflow, MaxNodeIDLength)
+		}
+		if node.TaskType == "" {
+			return fmt.Errorf("%w: node %s has no task type", ErrInvalidWorkflow, node.NodeID)
+		}
+		if !node.Priority.IsValid() {
+			return fmt.Errorf("%w: node %s has invalid priority %d", ErrInvalidWorkflow, node.NodeID, node.Priority)
+		}
+		if _, exists := nodes[node.NodeID]; exists {
+			return fmt.Errorf("%w: duplicate node %s", ErrInvalidWorkflow, node.NodeID)
+		}
+		nodes[node.NodeID] = node
+	}
+
+	for _, node := range wf.Nodes {
+		for _, parent := range node.DependsOn {
+			if _, exists := nodes[parent]; !exists {
+				return fmt.Errorf("%w: node %s depends on unknown node %s", ErrInvalidWorkflow, node.NodeID, parent)
+			}
+		}
+	}
+
+	if len(wf.topologicalOrder()) != len(wf.Nodes) {
+		return fmt.Errorf("%w: dependency cycle detected", ErrInvalidWorkflow)
+	}
+	return nil
+}
+
+// Node 根据节点ID查找节点
+func (wf *Workflow) Node(nodeID string) *WorkflowNode {
+	for _, node := range wf.Nodes {
+		if node.NodeID == nodeID {
+			return node
+		}
+	}
+	return nil
+}
+
+// StartNode 记录节点提交的任务
+func (wf *Workflow) StartNode(nodeID, taskID string) {
+	if node := wf.Node(nodeID); node != nil {
+		node.TaskID = taskID
+		node.Status = NodeRunning
+		node.UpdatedAt = time.Now()
+	}
+}
+
+// FinishNode 根据任务终态结束节点，仅 SUCCESS 视为成功
+func (wf *Workflow) FinishNode(nodeID string, taskStatus TaskStatus) {
+	node := wf.Node(nodeID)
+	if node == nil || node.Status != NodeRunning {
+		return
+	}
+
+	node.Status = NodeFailed
+	if taskStatus == StatusSuccess {
+		node.Status = NodeSuccess
+	}
+	node.UpdatedAt = time.Now()
+}
+
+// RejectNode 将无法提交任务的等待节点标记为失败，例如节点参数不满足任务类型的 payload schema
+func (wf *Workflow) RejectNode(nodeID string) {
+	node := wf.Node(nodeID)
+	if node == nil || node.Status != NodeWaiting {
+		return
+	}
+
+	node.Status = NodeFailed
+	node.UpdatedAt = time.Now()
+}
+
+// ApplyFailurePolicy 按失败策略跳过不再执行的等待节点，返回需要取消的执行中节点
+func (wf *Workflow) ApplyFailurePolicy() []*WorkflowNode {
+	switch wf.FailurePolicy {
+	case FailurePolicyFailFast:
+		if !wf.hasNodeIn(NodeFailed) {
+			return nil
+		}
+		var running []*WorkflowNode
+		for _, node := range wf.Nodes {
+			switch node.Status {
+			case NodeWaiting:
+				wf.skip(node)
+			case NodeRunning:
+				running = append(running, node)
+			}
+		}
+		return running
+
+	case FailurePolicySkipDescendants:
+		// 按拓扑序处理，跳过的节点会继续传递给其下游
+		for _, node := range wf.topologicalOrder() {
+			if node.Status != NodeWaiting {
+				continue
+			}
+			for _, parent := range node.DependsOn {
+				if status := wf.Node(parent).Status; status == NodeFailed || status == NodeSkipped {
+					wf.skip(node)
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ReadyNodes 依赖已满足、可以提交任务的等待节点
+// 上游全部成功时就绪；CONTINUE 策略下上游全部结束即就绪
+func (wf *Workflow) ReadyNodes() []*WorkflowNode {
+	var ready []*WorkflowNode
+	for _, node := range wf.Nodes {
+		if node.Status != NodeWaiting {
+			continue
+		}
+		if wf.dependenciesMet(node) {
+			ready = append(ready, node)
+		}
+	}
+	return ready
+}
+
+// Refresh 所有节点结束后更新工作流终态，返回工作流是否已结束
+func (wf *Workflow) Refresh() bool {
+	if wf.Status != WorkflowRunning {
+		return true
+	}
+
+	status := WorkflowSuccess
+	for _, node := range wf.Nodes {
+		if !node.Status.IsFinished() {
+			return false
+		}
+		if node.Status != NodeSuccess {
+			status = WorkflowFailed
+		}
+	}
+
+	now := time.Now()
+	wf.Status = status
+	wf.UpdatedAt = now
+	wf.CompletedAt = &now
+	return true
+}
+
+// dependenciesMet 判断节点的上游是否满足执行条件
+func (wf *Workflow) dependenciesMet(node *WorkflowNode) bool {
+	for _, parent := range node.DependsOn {
+		status := wf.Node(parent).Status
+		if status == NodeSuccess {
+			continue
+		}
+		if wf.FailurePolicy == FailurePolicyContinue && status.IsFinished() {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// hasNodeIn 判断是否存在指定状态的节点
+func (wf *Workflow) hasNodeIn(status NodeStatus) bool {
+	for _, node := range wf.Nodes {
+		if node.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// skip 跳过节点
+func (wf *Workflow) skip(node *WorkflowNode) {
+	node.Status = NodeSkipped
+	node.UpdatedAt = time.Now()
+}
+
+// topologicalOrder 按依赖关系排序节点（Kahn 算法），存在环时返回的节点数少于总数
+func (wf *Workflow) topologicalOrder() []*WorkflowNode {
+	inDegree := make(map[string]int, len(wf.Nodes))
+	children := make(map[string][]*WorkflowNode, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		for _, parent := range node.DependsOn {
+			inDegree[node.NodeID]++
+			children[parent] = append(children[parent], node)
+		}
+	}
+
+	queue := make([]*WorkflowNode, 0, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		if inDegree[node.NodeID] == 0 {
+			queue = append(queue, node)
+		}
+	}
+
+	order := make([]*WorkflowNode, 0, len(wf.Nodes))
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		order = append(order, node)
+		for _, child := range children[node.NodeID] {
+			inDegree[child.NodeID]--
+			if inDegree[child.NodeID] == 0 {
+				queue = append(queue, child)
+			}
+		}
+	}
+	return order
+}
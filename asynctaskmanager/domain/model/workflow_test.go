@@ -0,0 +1,247 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+// diamondNodes a -> (b, c) -> d
+func diamondNodes() []*WorkflowNode {
+	return []*WorkflowNode{
+		{NodeID: "a", TaskType: "example_task"},
+		{NodeID: "b", TaskType: "example_task", DependsOn: []string{"a"}},
+		{NodeID: "c", TaskType: "example_task", DependsOn: []string{"a"}},
+		{NodeID: "d", TaskType: "example_task", DependsOn: []string{"b", "c"}},
+	}
+}
+
+func nodeIDs(nodes []*WorkflowNode) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.NodeID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestNewWorkflow_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  FailurePolicy
+		nodes   []*WorkflowNode
+		wantErr bool
+	}{
+		{
+			name:  "valid diamond",
+			nodes: diamondNodes(),
+		},
+		{
+			name:    "unknown policy",
+			policy:  "RETRY_ALL",
+			nodes:   diamondNodes(),
+			wantErr: true,
+		},
+		{
+			name:    "no nodes",
+			wantErr: true,
+		},
+		{
+			name: "duplicate node",
+			nodes: []*WorkflowNode{
+				{NodeID: "a", TaskType: "example_task"},
+				{NodeID: "a", TaskType: "example_task"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown dependency",
+			nodes: []*WorkflowNode{
+				{NodeID: "a", TaskType: "example_task", DependsOn: []string{"missing"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing task type",
+			nodes: []*WorkflowNode{
+				{NodeID: "a"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "priority out of range",
+			nodes: []*WorkflowNode{
+				{NodeID: "a", TaskType: "example_task", Priority: PriorityMax + 1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cycle",
+			nodes: []*WorkflowNode{
+				{NodeID: "a", TaskType: "example_task", DependsOn: []string{"c"}},
+				{NodeID: "b", TaskType: "example_task", DependsOn: []string{"a"}},
+				{NodeID: "c", TaskType: "example_task", DependsOn: []string{"b"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "self dependency",
+			nodes: []*WorkflowNode{
+				{NodeID: "a", TaskType: "example_task", DependsOn: []string{"a"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf, err := NewWorkflow("wf-1", "test", tt.policy, tt.nodes)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidWorkflow) {
+					t.Errorf("NewWorkflow() error = %v, want ErrInvalidWorkflow", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewWorkflow() error = %v", err)
+			}
+			if wf.FailurePolicy != FailurePolicyFailFast {
+				t.Errorf("FailurePolicy = %s, want %s", wf.FailurePolicy, FailurePolicyFailFast)
+			}
+			for _, node := range wf.Nodes {
+				if node.Status != NodeWaiting || node.WorkflowID != "wf-1" {
+					t.Errorf("node %s = %s/%s, want WAITING/wf-1", node.NodeID, node.Status, node.WorkflowID)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkflow_FailurePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      FailurePolicy
+		wantCancel  []string
+		wantReady   []string
+		wantSkipped []string
+		wantStatus  WorkflowStatus
+	}{
+		{
+			// 执行中的 c 需要取消，未开始的 d、e 被跳过
+			name:        "fail fast",
+			policy:      FailurePolicyFailFast,
+			wantCancel:  []string{"c"},
+			wantReady:   []string{},
+			wantSkipped: []string{"d", "e"},
+			wantStatus:  WorkflowFailed,
+		},
+		{
+			name:        "skip descendants",
+			policy:      FailurePolicySkipDescendants,
+			wantCancel:  []string{},
+			wantReady:   []string{},
+			wantSkipped: []string{"d", "e"},
+			wantStatus:  WorkflowFailed,
+		},
+		{
+			name:        "continue",
+			policy:      FailurePolicyContinue,
+			wantCancel:  []string{},
+			wantReady:   []string{},
+			wantSkipped: []string{},
+			wantStatus:  WorkflowFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := append(diamondNodes(), &WorkflowNode{NodeID: "e", TaskType: "example_task", DependsOn: []string{"d"}})
+			wf, err := NewWorkflow("wf-1", "test", tt.policy, nodes)
+			if err != nil {
+				t.Fatalf("NewWorkflow() error = %v", err)
+			}
+
+			if got := nodeIDs(wf.ReadyNodes()); len(got) != 1 || got[0] != "a" {
+				t.Fatalf("ReadyNodes() = %v, want [a]", got)
+			}
+			wf.StartNode("a", "task-a")
+			wf.FinishNode("a", StatusSuccess)
+			wf.StartNode("b", "task-b")
+			wf.StartNode("c", "task-c")
+			wf.FinishNode("b", StatusFailed)
+
+			if got := nodeIDs(wf.ApplyFailurePolicy()); !equalIDs(got, tt.wantCancel) {
+				t.Errorf("ApplyFailurePolicy() = %v, want %v", got, tt.wantCancel)
+			}
+			if got := nodeIDs(wf.ReadyNodes()); !equalIDs(got, tt.wantReady) {
+				t.Errorf("ReadyNodes() = %v, want %v", got, tt.wantReady)
+			}
+
+			// c 结束后，CONTINUE 策略下 d 照常就绪
+			wf.FinishNode("c", StatusCancelled)
+			wf.ApplyFailurePolicy()
+			if tt.policy == FailurePolicyContinue {
+				if got := nodeIDs(wf.ReadyNodes()); !equalIDs(got, []string{"d"}) {
+					t.Errorf("ReadyNodes() after c finished = %v, want [d]", got)
+				}
+				for _, id := range []string{"d", "e"} {
+					wf.StartNode(id, "task-"+id)
+					wf.FinishNode(id, StatusSuccess)
+				}
+			}
+
+			var skipped []*WorkflowNode
+			for _, node := range wf.Nodes {
+				if node.Status == NodeSkipped {
+					skipped = append(skipped, node)
+				}
+			}
+			if got := nodeIDs(skipped); !equalIDs(got, tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", got, tt.wantSkipped)
+			}
+
+			if !wf.Refresh() {
+				t.Fatal("Refresh() = false, want finished")
+			}
+			if wf.Status != tt.wantStatus || wf.CompletedAt == nil {
+				t.Errorf("Status = %s, want %s", wf.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestWorkflow_Refresh(t *testing.T) {
+	wf, err := NewWorkflow("wf-1", "test", FailurePolicyFailFast, diamondNodes())
+	if err != nil {
+		t.Fatalf("NewWorkflow() error = %v", err)
+	}
+
+	for _, ids := range [][]string{{"a"}, {"b", "c"}, {"d"}} {
+		if wf.Refresh() {
+			t.Fatal("Refresh() = true before all nodes finished")
+		}
+		if got := nodeIDs(wf.ReadyNodes()); !equalIDs(got, ids) {
+			t.Fatalf("ReadyNodes() = %v, want %v", got, ids)
+		}
+		for _, id := range ids {
+			wf.StartNode(id, "task-"+id)
+			wf.FinishNode(id, StatusSuccess)
+		}
+	}
+
+	if !wf.Refresh() || wf.Status != WorkflowSuccess {
+		t.Errorf("Refresh() status = %s, want %s", wf.Status, WorkflowSuccess)
+	}
+}
+
+func equalIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+// ErrDeadLetterNotFound 任务不在死信队列中
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetterQuery 死信查询条件，零值字段表示不过滤
+// 结果按 ID 倒序排列，使用 keyset 分页：下一页以上一页最后一条记录的 ID 作为 BeforeID
+type DeadLetterQuery struct {
+	TaskType      string
+	ErrorContains string   // 错误信息包含的子串
+	TaskIDs       []string // 只匹配指定任务
+	BeforeID      int64    // 只返回 ID 小于该值的记录，0 表示从最新开始
+	Limit         int
+}
+
+// DeadLetterRepository 死信仓储接口
+type DeadLetterRepository interface {
+	// Create 创建死信记录，同一任务再次进入死信队列时覆盖原记录
+	Create(ctx context.Context, deadLetter *model.DeadLetter) error
+
+	// GetByTaskID 根据任务ID查找死信记录，不存在时返回 ErrDeadLetterNotFound
+	GetByTaskID(ctx context.Context, taskID string) (*model.DeadLetter, error)
+
+	// List 按条件分页查询死信，返回当前页记录以及满足过滤条件（不含 BeforeID）的总数
+	List(ctx context.Context, query *DeadLetterQuery) ([]*model.DeadLetter, int64, error)
+
+	// Delete 删除指定任务的死信记录，返回删除的数量
+	Delete(ctx context.Context, taskIDs []string) (int64, error)
+}
@@ -11,6 +11,9 @@ type TaskLogRepository interface {
 	// Create 创建任务日志
 	Create(ctx context.Context, log *model.TaskLog) error
 
+	// CreateBatch 批量创建任务日志
+	CreateBatch(ctx context.Context, logs []*model.TaskLog) error
+
 	// GetByTaskID 根据任务ID查找日志
 	GetByTaskID(ctx context.Context, taskID string) ([]*model.TaskLog, error)
 
@@ -2,19 +2,54 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"bamboo/asynctaskmanager/domain/model"
 )
 
+// ErrStaleFencingToken 写入携带的 fencing token 小于已记录的 token，说明写入方已不是当前 Leader
+var ErrStaleFencingToken = errors.New("stale fencing token")
+
+// ErrDuplicateIdempotencyKey 同一任务类型下已存在相同幂等键的任务
+var ErrDuplicateIdempotencyKey = errors.New("duplicate idempotency key")
+
+// TaskQuery 任务列表查询条件，零值字段表示不过滤
+// 结果按 ID 倒序排列，使用 keyset 分页：下一页以上一页最后一条任务的 ID 作为 BeforeID
+type TaskQuery struct {
+	Status        model.TaskStatus
+	Priority      *model.TaskPriority
+	TaskType      string
+	Tenant        string
+	WorkerID      string
+	CreatedAfter  time.Time // 含
+	CreatedBefore time.Time // 不含
+	BeforeID      int64     // 只返回 ID 小于该值的任务，0 表示从最新开始
+	Limit         int
+	SkipCount     bool // 不统计总数，List 返回的总数为 0；逐页遍历时除第一页外无需重复统计
+}
+
 // TaskRepository 任务仓储接口
 type TaskRepository interface {
 	// Create 创建任务
+	// 同一任务类型下幂等键已存在时返回 ErrDuplicateIdempotencyKey
 	Create(ctx context.Context, task *model.Task) error
 
+	// CreateBatch 在同一事务中批量创建任务，任一任务写入失败时全部回滚
+	// 幂等键与已有任务或批内其他任务冲突时返回 ErrDuplicateIdempotencyKey
+	CreateBatch(ctx context.Context, tasks []*model.Task) error
+
 	// GetByID 根据ID查找任务
 	GetByID(ctx context.Context, taskID string) (*model.Task, error)
 
+	// GetByIdempotencyKey 根据任务类型与幂等键查找任务
+	GetByIdempotencyKey(ctx context.Context, taskType, idempotencyKey string) (*model.Task, error)
+
+	// FindByIdempotencyKeys 查找任务类型下幂等键对应的已有任务，不存在的键不返回
+	FindByIdempotencyKeys(ctx context.Context, taskType string, idempotencyKeys []string) ([]*model.Task, error)
+
 	// Update 更新任务
+	// 已记录的 fencing token 大于 task.FencingToken 时拒绝写入并返回 ErrStaleFencingToken
 	Update(ctx context.Context, task *model.Task) error
 
 	// Delete 删除任务
@@ -31,4 +66,8 @@ type TaskRepository interface {
 
 	// FindByStatus 根据状态查找任务
 	FindByStatus(ctx context.Context, status model.TaskStatus, limit int) ([]*model.Task, error)
+
+	// List 按条件分页查询任务，返回当前页任务以及满足过滤条件（不含 BeforeID）的总数
+	// SkipCount 为 true 时不统计总数
+	List(ctx context.Context, query *TaskQuery) ([]*model.Task, int64, error)
 }
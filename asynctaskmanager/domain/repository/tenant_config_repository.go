@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+// ErrTenantConfigNotFound 租户没有单独的调度配置
+var ErrTenantConfigNotFound = errors.New("tenant config not found")
+
+// TenantConfigRepository 租户调度配置仓储接口
+type TenantConfigRepository interface {
+	// Save 创建或更新租户配置
+	Save(ctx context.Context, config *model.TenantConfig) error
+
+	// GetByTenant 根据租户查找配置，不存在时返回 ErrTenantConfigNotFound
+	GetByTenant(ctx context.Context, tenant string) (*model.TenantConfig, error)
+
+	// FindAll 查找所有租户配置
+	FindAll(ctx context.Context) ([]*model.TenantConfig, error)
+
+	// Delete 删除租户配置，租户恢复默认配额
+	Delete(ctx context.Context, tenant string) error
+}
@@ -21,6 +21,12 @@ type WorkerRepository interface {
 	// Remove 移除 Worker
 	Remove(ctx context.Context, workerID string) error
 
+	// FindExpired 查找心跳已过期的 Worker ID（包括注册信息已失效的 Worker）
+	FindExpired(ctx context.Context, timeout time.Duration) ([]string, error)
+
+	// Purge 彻底清除已过期 Worker 的注册信息，在其任务完成故障转移后调用
+	Purge(ctx context.Context, workerID string) error
+
 	// FindAll 查找所有 Worker
 	FindAll(ctx context.Context) ([]*model.Worker, error)
 
@@ -35,4 +41,7 @@ type WorkerRepository interface {
 
 	// UpdateLoad 更新负载
 	UpdateLoad(ctx context.Context, workerID string, load int) error
+
+	// IncrLoad 原子地增减负载，结果不小于 0；调度器分配任务时加一，Worker 处理结束时减一
+	IncrLoad(ctx context.Context, workerID string, delta int) error
 }
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+// WorkflowRepository 工作流仓储接口，查询结果包含全部节点
+type WorkflowRepository interface {
+	// Create 创建工作流及其节点
+	Create(ctx context.Context, workflow *model.Workflow) error
+
+	// GetByID 根据ID查找工作流
+	GetByID(ctx context.Context, workflowID string) (*model.Workflow, error)
+
+	// UpdateStatus 更新工作流状态
+	UpdateStatus(ctx context.Context, workflow *model.Workflow) error
+
+	// UpdateNode 更新节点状态与关联的任务ID
+	UpdateNode(ctx context.Context, node *model.WorkflowNode) error
+
+	// FindRunning 查找执行中的工作流
+	FindRunning(ctx context.Context, limit int) ([]*model.Workflow, error)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrResultNotFound 结果存储中不存在该结果
+var ErrResultNotFound = errors.New("result not found")
+
+// ResultStore 任务结果存储接口
+// 超过大小阈值的任务结果写入结果存储，任务中只保留返回的 URI
+type ResultStore interface {
+	// Put 写入结果并返回其 URI，同一 key 重复写入时覆盖
+	Put(ctx context.Context, key string, data []byte) (string, error)
+
+	// Open 按 URI 打开结果，不存在时返回 ErrResultNotFound，调用方负责关闭
+	Open(ctx context.Context, uri string) (io.ReadCloser, error)
+
+	// Delete 按 URI 删除结果，不存在时视为成功
+	Delete(ctx context.Context, uri string) error
+}
@@ -0,0 +1,178 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/domain/service"
+	"bamboo/asynctaskmanager/proto/taskhandler"
+)
+
+const (
+	// defaultHandlerService 默认调用的服务名
+	defaultHandlerService = "taskhandler.TaskHandler"
+	// defaultHandlerMethod 默认调用的方法名
+	defaultHandlerMethod = "Handle"
+)
+
+// GRPCExecutor gRPC 执行器
+// 按任务配置 ExecutorConfig 中的 address/service/method/timeout 调用远端 TaskHandler 服务
+type GRPCExecutor struct {
+	taskConfigRepo repository.TaskConfigRepository
+	taskTypes      []string
+	dialOptions    []grpc.DialOption
+	conns          map[string]*grpc.ClientConn
+	mu             sync.Mutex
+}
+
+// grpcTarget 单个任务类型的调用目标
+type grpcTarget struct {
+	address string
+	method  string
+	timeout time.Duration
+}
+
+// NewGRPCExecutor 创建 gRPC 执行器
+// 未指定 dialOptions 时使用不加密的连接
+func NewGRPCExecutor(taskConfigRepo repository.TaskConfigRepository, taskTypes []string, dialOptions ...grpc.DialOption) *GRPCExecutor {
+	if len(dialOptions) == 0 {
+		dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	return &GRPCExecutor{
+		taskConfigRepo: taskConfigRepo,
+		taskTypes:      taskTypes,
+		dialOptions:    dialOptions,
+		conns:          make(map[string]*grpc.ClientConn),
+	}
+}
+
+func (e *GRPCExecutor) Execute(ctx context.Context, task *model.Task) (map[string]interface{}, error) {
+	config, err := e.taskConfigRepo.GetByType(ctx, task.TaskType)
+	if err != nil {
+		return nil, fmt.Errorf("get task config failed: %w", err)
+	}
+
+	target, err := parseGRPCTarget(config.ExecutorConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid executor config for %s: %w", task.TaskType, err)
+	}
+
+	conn, err := e.getConn(target.address)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := structpb.NewStruct(task.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("convert payload failed: %w", err)
+	}
+
+	req := &taskhandler.HandleTaskRequest{
+		TaskId:     task.TaskID,
+		TaskType:   task.TaskType,
+		Payload:    payload,
+		RetryCount: int32(task.RetryCount),
+	}
+	resp := &taskhandler.HandleTaskResponse{}
+
+	if target.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, target.timeout)
+		defer cancel()
+	}
+
+	if err := conn.Invoke(ctx, target.method, req, resp); err != nil {
+		return nil, fmt.Errorf("invoke %s failed: %w", target.method, err)
+	}
+
+	return resp.GetResult().AsMap(), nil
+}
+
+func (e *GRPCExecutor) Type() model.ExecutorType {
+	return model.ExecutorTypeRPC
+}
+
+func (e *GRPCExecutor) SupportedTaskTypes() []string {
+	return e.taskTypes
+}
+
+// Close 关闭所有连接
+func (e *GRPCExecutor) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var firstErr error
+	for address, conn := range e.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("close connection %s failed: %w", address, err)
+		}
+		delete(e.conns, address)
+	}
+	return firstErr
+}
+
+// getConn 获取到目标地址的连接，同一地址复用连接
+func (e *GRPCExecutor) getConn(address string) (*grpc.ClientConn, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if conn, ok := e.conns[address]; ok {
+		return conn, nil
+	}
+
+	conn, err := grpc.NewClient(address, e.dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("create grpc client for %s failed: %w", address, err)
+	}
+	e.conns[address] = conn
+	return conn, nil
+}
+
+// parseGRPCTarget 从执行器配置解析调用目标
+// address 必填；service、method 默认为 TaskHandler.Handle；timeout 单位为秒
+func parseGRPCTarget(config map[string]interface{}) (*grpcTarget, error) {
+	address, _ := config["address"].(string)
+	if address == "" {
+		return nil, fmt.Errorf("address is required")
+	}
+
+	serviceName := defaultHandlerService
+	if s, ok := config["service"].(string); ok && s != "" {
+		serviceName = s
+	}
+	methodName := defaultHandlerMethod
+	if m, ok := config["method"].(string); ok && m != "" {
+		methodName = m
+	}
+
+	target := &grpcTarget{
+		address: address,
+		method:  "/" + strings.TrimPrefix(serviceName, "/") + "/" + methodName,
+	}
+
+	switch v := config["timeout"].(type) {
+	case nil:
+	case float64:
+		target.timeout = time.Duration(v * float64(time.Second))
+	case int:
+		target.timeout = time.Duration(v) * time.Second
+	default:
+		return nil, fmt.Errorf("timeout must be a number of seconds, got %T", v)
+	}
+	if target.timeout < 0 {
+		return nil, fmt.Errorf("timeout must not be negative")
+	}
+
+	return target, nil
+}
+
+var _ service.Executor = (*GRPCExecutor)(nil)
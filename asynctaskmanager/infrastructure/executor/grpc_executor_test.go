@@ -0,0 +1,202 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/infrastructure/memory"
+	"bamboo/asynctaskmanager/proto/taskhandler"
+)
+
+// echoHandler 测试用 TaskHandler，回显 payload，payload 中 fail=true 时返回错误
+type echoHandler struct {
+	taskhandler.UnimplementedTaskHandlerServer
+}
+
+func (h *echoHandler) Handle(ctx context.Context, req *taskhandler.HandleTaskRequest) (*taskhandler.HandleTaskResponse, error) {
+	if req.GetPayload().GetFields()["fail"].GetBoolValue() {
+		return nil, status.Error(codes.Internal, "handler failed")
+	}
+	result, err := structpb.NewStruct(map[string]interface{}{
+		"task_id": req.GetTaskId(),
+		"echo":    req.GetPayload().AsMap(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &taskhandler.HandleTaskResponse{Result: result}, nil
+}
+
+func newBufconnExecutor(t *testing.T, configs ...*model.TaskConfig) *GRPCExecutor {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	taskhandler.RegisterTaskHandlerServer(server, &echoHandler{})
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	repo := memory.NewTaskConfigRepository()
+	taskTypes := make([]string, 0, len(configs))
+	for _, config := range configs {
+		if err := repo.Create(context.Background(), config); err != nil {
+			t.Fatalf("create task config failed: %v", err)
+		}
+		taskTypes = append(taskTypes, config.TaskType)
+	}
+
+	e := NewGRPCExecutor(repo, taskTypes,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	t.Cleanup(func() { _ = e.Close() })
+	return e
+}
+
+func TestGRPCExecutor_Execute(t *testing.T) {
+	e := newBufconnExecutor(t,
+		&model.TaskConfig{
+			TaskType:       "remote_task",
+			ExecutorType:   model.ExecutorTypeRPC,
+			ExecutorConfig: map[string]interface{}{"address": "passthrough:///bufnet", "timeout": float64(5)},
+		},
+		&model.TaskConfig{
+			TaskType:     "explicit_method",
+			ExecutorType: model.ExecutorTypeRPC,
+			ExecutorConfig: map[string]interface{}{
+				"address": "passthrough:///bufnet",
+				"service": "taskhandler.TaskHandler",
+				"method":  "Handle",
+			},
+		},
+		&model.TaskConfig{
+			TaskType:       "unknown_method",
+			ExecutorType:   model.ExecutorTypeRPC,
+			ExecutorConfig: map[string]interface{}{"address": "passthrough:///bufnet", "method": "Missing"},
+		},
+		&model.TaskConfig{
+			TaskType:       "no_address",
+			ExecutorType:   model.ExecutorTypeRPC,
+			ExecutorConfig: map[string]interface{}{},
+		},
+	)
+
+	tests := []struct {
+		name     string
+		task     *model.Task
+		wantCode codes.Code
+		wantErr  bool
+	}{
+		{
+			name: "default service and method",
+			task: &model.Task{TaskID: "t1", TaskType: "remote_task", Payload: map[string]interface{}{"n": float64(1)}},
+		},
+		{
+			name: "explicit service and method",
+			task: &model.Task{TaskID: "t2", TaskType: "explicit_method", Payload: map[string]interface{}{"n": float64(2)}},
+		},
+		{
+			name:     "handler returns error",
+			task:     &model.Task{TaskID: "t3", TaskType: "remote_task", Payload: map[string]interface{}{"fail": true}},
+			wantCode: codes.Internal,
+			wantErr:  true,
+		},
+		{
+			name:     "unknown method",
+			task:     &model.Task{TaskID: "t4", TaskType: "unknown_method"},
+			wantCode: codes.Unimplemented,
+			wantErr:  true,
+		},
+		{
+			name:    "missing address",
+			task:    &model.Task{TaskID: "t5", TaskType: "no_address"},
+			wantErr: true,
+		},
+		{
+			name:    "missing config",
+			task:    &model.Task{TaskID: "t6", TaskType: "not_configured"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := e.Execute(context.Background(), tt.task)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if tt.wantCode != codes.OK && status.Code(err) != tt.wantCode {
+					t.Errorf("Execute() code = %v, want %v", status.Code(err), tt.wantCode)
+				}
+				return
+			}
+			if result["task_id"] != tt.task.TaskID {
+				t.Errorf("result task_id = %v, want %s", result["task_id"], tt.task.TaskID)
+			}
+			echo, _ := result["echo"].(map[string]interface{})
+			if echo["n"] != tt.task.Payload["n"] {
+				t.Errorf("result echo = %v, want payload %v", echo, tt.task.Payload)
+			}
+		})
+	}
+}
+
+func TestParseGRPCTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		want    grpcTarget
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			config: map[string]interface{}{"address": "localhost:9000"},
+			want:   grpcTarget{address: "localhost:9000", method: "/taskhandler.TaskHandler/Handle"},
+		},
+		{
+			name: "custom service method and timeout",
+			config: map[string]interface{}{
+				"address": "handler:9000",
+				"service": "billing.Handler",
+				"method":  "Charge",
+				"timeout": float64(1.5),
+			},
+			want: grpcTarget{address: "handler:9000", method: "/billing.Handler/Charge", timeout: 1500 * time.Millisecond},
+		},
+		{
+			name:    "missing address",
+			config:  map[string]interface{}{"method": "Handle"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			config:  map[string]interface{}{"address": "localhost:9000", "timeout": "5s"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGRPCTarget(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGRPCTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && *got != tt.want {
+				t.Errorf("parseGRPCTarget() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
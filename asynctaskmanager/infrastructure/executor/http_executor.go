@@ -42,9 +42,9 @@ func (e *HTTPExecutor) Execute(ctx context.Context, task *model.Task) (map[strin
 	// 构建请求
 	var reqBody io.Reader
 	if body, ok := task.Payload["body"]; ok {
-		bodyBytes, err := json.Marshal(body)
+		bodyBytes, err := encodeBody(body)
 		if err != nil {
-			return nil, fmt.Errorf("marshal body failed: %w", err)
+			return nil, err
 		}
 		reqBody = bytes.NewReader(bodyBytes)
 	}
@@ -81,6 +81,19 @@ func (e *HTTPExecutor) Execute(ctx context.Context, task *model.Task) (map[strin
 	return result, nil
 }
 
+// encodeBody 编码请求体：对象、数组等类型化的值编码为 JSON
+// 字符串原样发送，兼容通过旧的 map<string,string> payload 传入已编码好的 JSON 文本
+func encodeBody(body interface{}) ([]byte, error) {
+	if str, ok := body.(string); ok {
+		return []byte(str), nil
+	}
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal body failed: %w", err)
+	}
+	return bodyBytes, nil
+}
+
 func (e *HTTPExecutor) Type() model.ExecutorType {
 	return model.ExecutorTypeHTTP
 }
@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+func TestHTTPExecutor_Body(t *testing.T) {
+	var gotBody, gotContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotContentType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		body interface{}
+		want string
+	}{
+		{
+			name: "类型化的对象编码为 JSON",
+			body: map[string]interface{}{"count": float64(3), "dry": true, "tags": []interface{}{"a"}},
+			want: `{"count":3,"dry":true,"tags":["a"]}`,
+		},
+		{
+			name: "字符串原样发送",
+			body: `{"count":3}`,
+			want: `{"count":3}`,
+		},
+	}
+
+	e := NewHTTPExecutor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &model.Task{
+				TaskID:   "task-1",
+				TaskType: "http_request",
+				Payload:  map[string]interface{}{"url": server.URL, "body": tt.body},
+			}
+
+			result, err := e.Execute(context.Background(), task)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result["status_code"] != http.StatusOK {
+				t.Errorf("status_code = %v, want %d", result["status_code"], http.StatusOK)
+			}
+			if gotBody != tt.want {
+				t.Errorf("body = %s, want %s", gotBody, tt.want)
+			}
+			if gotContentType != "application/json" {
+				t.Errorf("Content-Type = %s, want application/json", gotContentType)
+			}
+		})
+	}
+}
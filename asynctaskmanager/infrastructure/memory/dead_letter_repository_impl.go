@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+)
+
+type deadLetterRepositoryImpl struct {
+	deadLetters map[string]*model.DeadLetter
+	nextID      int64
+	mu          sync.RWMutex
+}
+
+func NewDeadLetterRepository() repository.DeadLetterRepository {
+	return &deadLetterRepositoryImpl{
+		deadLetters: make(map[string]*model.DeadLetter),
+	}
+}
+
+func (r *deadLetterRepositoryImpl) Create(ctx context.Context, deadLetter *model.DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 模拟自增主键，覆盖时记录排到最新
+	r.nextID++
+	deadLetter.ID = r.nextID
+	copied := *deadLetter
+	r.deadLetters[deadLetter.TaskID] = &copied
+	return nil
+}
+
+func (r *deadLetterRepositoryImpl) GetByTaskID(ctx context.Context, taskID string) (*model.DeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deadLetter, exists := r.deadLetters[taskID]
+	if !exists {
+		return nil, repository.ErrDeadLetterNotFound
+	}
+
+	copied := *deadLetter
+	return &copied, nil
+}
+
+func (r *deadLetterRepositoryImpl) List(ctx context.Context, query *repository.DeadLetterQuery) ([]*model.DeadLetter, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*model.DeadLetter, 0)
+	for _, deadLetter := range r.deadLetters {
+		if matchDeadLetterQuery(deadLetter, query) {
+			matched = append(matched, deadLetter)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].ID > matched[j].ID
+	})
+
+	total := int64(len(matched))
+	deadLetters := make([]*model.DeadLetter, 0, query.Limit)
+	for _, deadLetter := range matched {
+		if query.BeforeID > 0 && deadLetter.ID >= query.BeforeID {
+			continue
+		}
+		if len(deadLetters) >= query.Limit {
+			break
+		}
+		copied := *deadLetter
+		deadLetters = append(deadLetters, &copied)
+	}
+
+	return deadLetters, total, nil
+}
+
+func (r *deadLetterRepositoryImpl) Delete(ctx context.Context, taskIDs []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for _, taskID := range taskIDs {
+		if _, exists := r.deadLetters[taskID]; exists {
+			delete(r.deadLetters, taskID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// matchDeadLetterQuery 判断死信是否满足过滤条件（不含分页游标）
+func matchDeadLetterQuery(deadLetter *model.DeadLetter, query *repository.DeadLetterQuery) bool {
+	if query.TaskType != "" && deadLetter.TaskType != query.TaskType {
+		return false
+	}
+	if query.ErrorContains != "" && !strings.Contains(deadLetter.ErrorMsg, query.ErrorContains) {
+		return false
+	}
+	if len(query.TaskIDs) > 0 {
+		for _, taskID := range query.TaskIDs {
+			if deadLetter.TaskID == taskID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -27,6 +27,16 @@ func (r *taskLogRepositoryImpl) Create(ctx context.Context, log *model.TaskLog)
 	return nil
 }
 
+func (r *taskLogRepositoryImpl) CreateBatch(ctx context.Context, logs []*model.TaskLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, log := range logs {
+		r.logs[log.TaskID] = append(r.logs[log.TaskID], log)
+	}
+	return nil
+}
+
 func (r *taskLogRepositoryImpl) GetByTaskID(ctx context.Context, taskID string) ([]*model.TaskLog, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,8 +12,9 @@ import (
 )
 
 type taskRepositoryImpl struct {
-	tasks map[string]*model.Task
-	mu    sync.RWMutex
+	tasks  map[string]*model.Task
+	nextID int64
+	mu     sync.RWMutex
 }
 
 func NewTaskRepository() repository.TaskRepository {
@@ -28,11 +30,45 @@ func (r *taskRepositoryImpl) Create(ctx context.Context, task *model.Task) error
 	if _, exists := r.tasks[task.TaskID]; exists {
 		return fmt.Errorf("task already exists: %s", task.TaskID)
 	}
+	if task.IdempotencyKey != "" && r.findByIdempotencyKey(task.TaskType, task.IdempotencyKey) != nil {
+		return fmt.Errorf("create task %s failed: %w", task.TaskID, repository.ErrDuplicateIdempotencyKey)
+	}
 
+	// 模拟自增主键
+	r.nextID++
+	task.ID = r.nextID
 	r.tasks[task.TaskID] = task
 	return nil
 }
 
+func (r *taskRepositoryImpl) CreateBatch(ctx context.Context, tasks []*model.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 先整体校验，保证全部写入或全部不写入
+	keys := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		if _, exists := r.tasks[task.TaskID]; exists {
+			return fmt.Errorf("task already exists: %s", task.TaskID)
+		}
+		if task.IdempotencyKey == "" {
+			continue
+		}
+		key := task.TaskType + "/" + task.IdempotencyKey
+		if keys[key] || r.findByIdempotencyKey(task.TaskType, task.IdempotencyKey) != nil {
+			return fmt.Errorf("create task %s failed: %w", task.TaskID, repository.ErrDuplicateIdempotencyKey)
+		}
+		keys[key] = true
+	}
+
+	for _, task := range tasks {
+		r.nextID++
+		task.ID = r.nextID
+		r.tasks[task.TaskID] = task
+	}
+	return nil
+}
+
 func (r *taskRepositoryImpl) GetByID(ctx context.Context, taskID string) (*model.Task, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -45,13 +81,52 @@ func (r *taskRepositoryImpl) GetByID(ctx context.Context, taskID string) (*model
 	return task, nil
 }
 
+func (r *taskRepositoryImpl) GetByIdempotencyKey(ctx context.Context, taskType, idempotencyKey string) (*model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	task := r.findByIdempotencyKey(taskType, idempotencyKey)
+	if task == nil {
+		return nil, fmt.Errorf("task not found: %s/%s", taskType, idempotencyKey)
+	}
+
+	return task, nil
+}
+
+func (r *taskRepositoryImpl) FindByIdempotencyKeys(ctx context.Context, taskType string, idempotencyKeys []string) ([]*model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tasks []*model.Task
+	for _, key := range idempotencyKeys {
+		if task := r.findByIdempotencyKey(taskType, key); task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// findByIdempotencyKey 调用方需持有锁
+func (r *taskRepositoryImpl) findByIdempotencyKey(taskType, idempotencyKey string) *model.Task {
+	for _, task := range r.tasks {
+		if task.TaskType == taskType && task.IdempotencyKey == idempotencyKey {
+			return task
+		}
+	}
+	return nil
+}
+
 func (r *taskRepositoryImpl) Update(ctx context.Context, task *model.Task) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.tasks[task.TaskID]; !exists {
+	existing, exists := r.tasks[task.TaskID]
+	if !exists {
 		return fmt.Errorf("task not found: %s", task.TaskID)
 	}
+	if existing.FencingToken > task.FencingToken {
+		return fmt.Errorf("update task %s rejected: %w", task.TaskID, repository.ErrStaleFencingToken)
+	}
 
 	task.UpdatedAt = time.Now()
 	r.tasks[task.TaskID] = task
@@ -127,3 +202,62 @@ func (r *taskRepositoryImpl) FindByStatus(ctx context.Context, status model.Task
 
 	return tasks, nil
 }
+
+func (r *taskRepositoryImpl) List(ctx context.Context, query *repository.TaskQuery) ([]*model.Task, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*model.Task, 0)
+	for _, task := range r.tasks {
+		if matchTaskQuery(task, query) {
+			matched = append(matched, task)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].ID > matched[j].ID
+	})
+
+	var total int64
+	if !query.SkipCount {
+		total = int64(len(matched))
+	}
+	tasks := make([]*model.Task, 0, query.Limit)
+	for _, task := range matched {
+		if query.BeforeID > 0 && task.ID >= query.BeforeID {
+			continue
+		}
+		if len(tasks) >= query.Limit {
+			break
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, total, nil
+}
+
+// matchTaskQuery 判断任务是否满足过滤条件（不含分页游标）
+func matchTaskQuery(task *model.Task, query *repository.TaskQuery) bool {
+	if query.Status != "" && task.Status != query.Status {
+		return false
+	}
+	if query.Priority != nil && task.Priority != *query.Priority {
+		return false
+	}
+	if query.TaskType != "" && task.TaskType != query.TaskType {
+		return false
+	}
+	if query.Tenant != "" && task.Tenant != query.Tenant {
+		return false
+	}
+	if query.WorkerID != "" && task.WorkerID != query.WorkerID {
+		return false
+	}
+	if !query.CreatedAfter.IsZero() && task.CreatedAt.Before(query.CreatedAfter) {
+		return false
+	}
+	if !query.CreatedBefore.IsZero() && !task.CreatedAt.Before(query.CreatedBefore) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+)
+
+func TestTaskRepository_List(t *testing.T) {
+	repo := NewTaskRepository()
+	ctx := context.Background()
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 10; i++ {
+		task := &model.Task{
+			TaskID:    fmt.Sprintf("task-%d", i),
+			TaskType:  "email",
+			Priority:  model.PriorityNormal,
+			Status:    model.StatusPending,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}
+		if i%2 == 0 {
+			task.TaskType = "report"
+			task.Priority = model.PriorityHigh
+			task.Status = model.StatusProcessing
+			task.WorkerID = "worker-1"
+		}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	high := model.PriorityHigh
+	tests := []struct {
+		name      string
+		query     repository.TaskQuery
+		wantIDs   []string
+		wantTotal int64
+	}{
+		{
+			name:      "newest first",
+			query:     repository.TaskQuery{Limit: 3},
+			wantIDs:   []string{"task-9", "task-8", "task-7"},
+			wantTotal: 10,
+		},
+		{
+			name:      "keyset cursor",
+			query:     repository.TaskQuery{BeforeID: 8, Limit: 3},
+			wantIDs:   []string{"task-6", "task-5", "task-4"},
+			wantTotal: 10,
+		},
+		{
+			name:      "skip count",
+			query:     repository.TaskQuery{BeforeID: 8, Limit: 3, SkipCount: true},
+			wantIDs:   []string{"task-6", "task-5", "task-4"},
+			wantTotal: 0,
+		},
+		{
+			name: "combined filters",
+			query: repository.TaskQuery{
+				Status:   model.StatusProcessing,
+				Priority: &high,
+				TaskType: "report",
+				WorkerID: "worker-1",
+				Limit:    10,
+			},
+			wantIDs:   []string{"task-8", "task-6", "task-4", "task-2", "task-0"},
+			wantTotal: 5,
+		},
+		{
+			name: "created range",
+			query: repository.TaskQuery{
+				CreatedAfter:  base.Add(2 * time.Minute),
+				CreatedBefore: base.Add(5 * time.Minute),
+				Limit:         10,
+			},
+			wantIDs:   []string{"task-4", "task-3", "task-2"},
+			wantTotal: 3,
+		},
+		{
+			name:      "no match",
+			query:     repository.TaskQuery{TaskType: "unknown", Limit: 10},
+			wantIDs:   []string{},
+			wantTotal: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, total, err := repo.List(ctx, &tt.query)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("List() total = %d, want %d", total, tt.wantTotal)
+			}
+			if len(tasks) != len(tt.wantIDs) {
+				t.Fatalf("List() returned %d tasks, want %d", len(tasks), len(tt.wantIDs))
+			}
+			for i, task := range tasks {
+				if task.TaskID != tt.wantIDs[i] {
+					t.Errorf("List()[%d] = %s, want %s", i, task.TaskID, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+}
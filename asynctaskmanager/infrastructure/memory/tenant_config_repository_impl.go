@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+)
+
+type tenantConfigRepositoryImpl struct {
+	configs map[string]*model.TenantConfig
+	nextID  int64
+	mu      sync.RWMutex
+}
+
+func NewTenantConfigRepository() repository.TenantConfigRepository {
+	return &tenantConfigRepositoryImpl{
+		configs: make(map[string]*model.TenantConfig),
+	}
+}
+
+func (r *tenantConfigRepositoryImpl) Save(ctx context.Context, config *model.TenantConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	copied := *config
+	if existing, exists := r.configs[config.Tenant]; exists {
+		copied.ID = existing.ID
+		copied.CreatedAt = existing.CreatedAt
+	} else {
+		r.nextID++
+		copied.ID = r.nextID
+		copied.CreatedAt = now
+	}
+	copied.UpdatedAt = now
+	r.configs[config.Tenant] = &copied
+	return nil
+}
+
+func (r *tenantConfigRepositoryImpl) GetByTenant(ctx context.Context, tenant string) (*model.TenantConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	config, exists := r.configs[tenant]
+	if !exists {
+		return nil, repository.ErrTenantConfigNotFound
+	}
+
+	copied := *config
+	return &copied, nil
+}
+
+func (r *tenantConfigRepositoryImpl) FindAll(ctx context.Context) ([]*model.TenantConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	configs := make([]*model.TenantConfig, 0, len(r.configs))
+	for _, config := range r.configs {
+		copied := *config
+		configs = append(configs, &copied)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Tenant < configs[j].Tenant
+	})
+	return configs, nil
+}
+
+func (r *tenantConfigRepositoryImpl) Delete(ctx context.Context, tenant string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.configs, tenant)
+	return nil
+}
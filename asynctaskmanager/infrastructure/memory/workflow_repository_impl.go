@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+)
+
+// workflowRepositoryImpl 读写时复制工作流，调用方修改返回值不会影响已存储的数据
+type workflowRepositoryImpl struct {
+	workflows map[string]*model.Workflow
+	nextID    int64
+	mu        sync.RWMutex
+}
+
+func NewWorkflowRepository() repository.WorkflowRepository {
+	return &workflowRepositoryImpl{
+		workflows: make(map[string]*model.Workflow),
+	}
+}
+
+func (r *workflowRepositoryImpl) Create(ctx context.Context, workflow *model.Workflow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.workflows[workflow.WorkflowID]; exists {
+		return fmt.Errorf("workflow already exists: %s", workflow.WorkflowID)
+	}
+
+	// 模拟自增主键
+	r.nextID++
+	workflow.ID = r.nextID
+	for i, node := range workflow.Nodes {
+		node.ID = int64(i + 1)
+	}
+	r.workflows[workflow.WorkflowID] = cloneWorkflow(workflow)
+	return nil
+}
+
+func (r *workflowRepositoryImpl) GetByID(ctx context.Context, workflowID string) (*model.Workflow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	workflow, exists := r.workflows[workflowID]
+	if !exists {
+		return nil, fmt.Errorf("workflow not found: %s", workflowID)
+	}
+
+	return cloneWorkflow(workflow), nil
+}
+
+func (r *workflowRepositoryImpl) UpdateStatus(ctx context.Context, workflow *model.Workflow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.workflows[workflow.WorkflowID]
+	if !exists {
+		return fmt.Errorf("workflow not found: %s", workflow.WorkflowID)
+	}
+
+	existing.Status = workflow.Status
+	existing.CompletedAt = workflow.CompletedAt
+	existing.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *workflowRepositoryImpl) UpdateNode(ctx context.Context, node *model.WorkflowNode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workflow, exists := r.workflows[node.WorkflowID]
+	if !exists {
+		return fmt.Errorf("workflow not found: %s", node.WorkflowID)
+	}
+	existing := workflow.Node(node.NodeID)
+	if existing == nil {
+		return fmt.Errorf("workflow node not found: %s/%s", node.WorkflowID, node.NodeID)
+	}
+
+	existing.TaskID = node.TaskID
+	existing.Status = node.Status
+	existing.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *workflowRepositoryImpl) FindRunning(ctx context.Context, limit int) ([]*model.Workflow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*model.Workflow
+	for _, workflow := range r.workflows {
+		if workflow.Status == model.WorkflowRunning {
+			result = append(result, cloneWorkflow(workflow))
+		}
+	}
+
+	// 与 MySQL 实现一致，按创建顺序返回
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// cloneWorkflow 复制工作流及其节点
+func cloneWorkflow(workflow *model.Workflow) *model.Workflow {
+	clone := *workflow
+	clone.Nodes = make([]*model.WorkflowNode, len(workflow.Nodes))
+	for i, node := range workflow.Nodes {
+		n := *node
+		n.DependsOn = append([]string(nil), node.DependsOn...)
+		clone.Nodes[i] = &n
+	}
+	return &clone
+}
@@ -0,0 +1,188 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+)
+
+// deadLetterColumns 死信查询的列，顺序与 scanDeadLetter 一致
+const deadLetterColumns = `id, task_id, task_type, error_message, retry_count, worker_id, created_at`
+
+// likeEscaper 转义 LIKE 模式中的通配符
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// DeadLetterRepositoryImpl DeadLetter 仓储 MySQL 实现
+type DeadLetterRepositoryImpl struct {
+	client *Client
+}
+
+// NewDeadLetterRepository 创建 DeadLetter 仓储
+func NewDeadLetterRepository(client *Client) repository.DeadLetterRepository {
+	return &DeadLetterRepositoryImpl{client: client}
+}
+
+// Create 创建死信记录，REPLACE 使再次进入死信队列的任务获得新的 ID，排在列表最前
+func (r *DeadLetterRepositoryImpl) Create(ctx context.Context, deadLetter *model.DeadLetter) error {
+	query := `REPLACE INTO dead_letter (task_id, task_type, error_message, retry_count, worker_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	result, err := r.client.db.ExecContext(ctx, query,
+		deadLetter.TaskID,
+		deadLetter.TaskType,
+		deadLetter.ErrorMsg,
+		deadLetter.RetryCount,
+		deadLetter.WorkerID,
+		deadLetter.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert dead letter failed: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id failed: %w", err)
+	}
+	deadLetter.ID = id
+	return nil
+}
+
+// GetByTaskID 根据任务ID查找死信记录
+func (r *DeadLetterRepositoryImpl) GetByTaskID(ctx context.Context, taskID string) (*model.DeadLetter, error) {
+	query := `SELECT ` + deadLetterColumns + ` FROM dead_letter WHERE task_id = ?`
+
+	deadLetter, err := scanDeadLetter(r.client.db.QueryRowContext(ctx, query, taskID))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query dead letter failed: %w", err)
+	}
+	return deadLetter, nil
+}
+
+// List 按条件分页查询死信
+func (r *DeadLetterRepositoryImpl) List(ctx context.Context, query *repository.DeadLetterQuery) ([]*model.DeadLetter, int64, error) {
+	where, args := buildDeadLetterQueryConditions(query)
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM dead_letter` + where
+	if err := r.client.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count dead letters failed: %w", err)
+	}
+
+	// keyset 分页：主键倒序，从游标之后开始
+	if query.BeforeID > 0 {
+		if where == "" {
+			where = " WHERE id < ?"
+		} else {
+			where += " AND id < ?"
+		}
+		args = append(args, query.BeforeID)
+	}
+	args = append(args, query.Limit)
+
+	listQuery := `SELECT ` + deadLetterColumns + `
+		FROM dead_letter` + where + ` ORDER BY id DESC LIMIT ?`
+
+	rows, err := r.client.db.QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query dead letters failed: %w", err)
+	}
+	defer rows.Close()
+
+	deadLetters := make([]*model.DeadLetter, 0)
+	for rows.Next() {
+		deadLetter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan dead letter failed: %w", err)
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	return deadLetters, total, nil
+}
+
+// Delete 删除指定任务的死信记录
+func (r *DeadLetterRepositoryImpl) Delete(ctx context.Context, taskIDs []string) (int64, error) {
+	if len(taskIDs) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, 0, len(taskIDs))
+	args := make([]interface{}, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		placeholders = append(placeholders, "?")
+		args = append(args, taskID)
+	}
+
+	query := `DELETE FROM dead_letter WHERE task_id IN (` + strings.Join(placeholders, ", ") + `)`
+	result, err := r.client.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("delete dead letters failed: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get affected rows failed: %w", err)
+	}
+	return deleted, nil
+}
+
+// buildDeadLetterQueryConditions 构建过滤条件（不含分页游标）
+func buildDeadLetterQueryConditions(query *repository.DeadLetterQuery) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if query.TaskType != "" {
+		conditions = append(conditions, "task_type = ?")
+		args = append(args, query.TaskType)
+	}
+	if query.ErrorContains != "" {
+		conditions = append(conditions, "error_message LIKE ?")
+		args = append(args, "%"+likeEscaper.Replace(query.ErrorContains)+"%")
+	}
+	if len(query.TaskIDs) > 0 {
+		placeholders := make([]string, 0, len(query.TaskIDs))
+		for _, taskID := range query.TaskIDs {
+			placeholders = append(placeholders, "?")
+			args = append(args, taskID)
+		}
+		conditions = append(conditions, "task_id IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// scanDeadLetter 按 deadLetterColumns 的顺序扫描死信记录
+func scanDeadLetter(row rowScanner) (*model.DeadLetter, error) {
+	deadLetter := &model.DeadLetter{}
+	var errorMsg, workerID sql.NullString
+
+	err := row.Scan(
+		&deadLetter.ID,
+		&deadLetter.TaskID,
+		&deadLetter.TaskType,
+		&errorMsg,
+		&deadLetter.RetryCount,
+		&workerID,
+		&deadLetter.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetter.ErrorMsg = errorMsg.String
+	deadLetter.WorkerID = workerID.String
+	return deadLetter, nil
+}
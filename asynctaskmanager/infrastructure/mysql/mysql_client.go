@@ -2,10 +2,12 @@ package mysql
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
 )
 
 // Client MySQL 客户端
@@ -78,6 +80,7 @@ func (c *Client) InitSchema() error {
 			result JSON,
 			error_message TEXT,
 			worker_id VARCHAR(64),
+			fencing_token BIGINT NOT NULL DEFAULT 0,
 			retry_count INT NOT NULL DEFAULT 0,
 			max_retry INT NOT NULL DEFAULT 3,
 			timeout INT NOT NULL DEFAULT 30,
@@ -86,6 +89,12 @@ func (c *Client) InitSchema() error {
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
 			started_at TIMESTAMP NULL,
 			completed_at TIMESTAMP NULL,
+			idempotency_key VARCHAR(128) NULL,
+			tenant VARCHAR(64) NOT NULL DEFAULT 'default',
+			result_uri VARCHAR(512) NOT NULL DEFAULT '',
+			result_size BIGINT NOT NULL DEFAULT 0,
+			UNIQUE INDEX uk_task_type_idempotency_key (task_type, idempotency_key),
+			INDEX idx_tenant_status (tenant, status),
 			INDEX idx_task_id (task_id),
 			INDEX idx_status (status),
 			INDEX idx_task_type (task_type),
@@ -108,7 +117,12 @@ func (c *Client) InitSchema() error {
 			retry_strategy VARCHAR(32) NOT NULL,
 			retry_delay INT NOT NULL DEFAULT 5,
 			backoff_rate DECIMAL(10,2) NOT NULL DEFAULT 2.0,
+			max_retry_delay INT NOT NULL DEFAULT 0,
+			retry_jitter DECIMAL(4,2) NOT NULL DEFAULT 0,
 			max_concurrent INT NOT NULL DEFAULT 10,
+			rate_limit DECIMAL(10,3) NOT NULL DEFAULT 0,
+			rate_burst INT NOT NULL DEFAULT 0,
+			payload_schema JSON NULL,
 			enabled BOOLEAN NOT NULL DEFAULT TRUE,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
@@ -116,6 +130,18 @@ func (c *Client) InitSchema() error {
 			INDEX idx_enabled (enabled)
 		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
 
+		// tenant_config 表
+		`CREATE TABLE IF NOT EXISTS tenant_config (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			tenant VARCHAR(64) UNIQUE NOT NULL,
+			quantum INT NOT NULL DEFAULT 1,
+			max_in_flight INT NOT NULL DEFAULT 0,
+			rate_limit DECIMAL(10,3) NOT NULL DEFAULT 0,
+			rate_burst INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+
 		// task_log 表
 		`CREATE TABLE IF NOT EXISTS task_log (
 			id BIGINT AUTO_INCREMENT PRIMARY KEY,
@@ -150,6 +176,48 @@ func (c *Client) InitSchema() error {
 			INDEX idx_status (status),
 			INDEX idx_last_heartbeat (last_heartbeat)
 		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+
+		// workflow 表
+		`CREATE TABLE IF NOT EXISTS workflow (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			workflow_id VARCHAR(64) UNIQUE NOT NULL,
+			name VARCHAR(128) NOT NULL DEFAULT '',
+			status VARCHAR(32) NOT NULL,
+			failure_policy VARCHAR(32) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			completed_at TIMESTAMP NULL,
+			INDEX idx_status (status)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+
+		// workflow_node 表
+		`CREATE TABLE IF NOT EXISTS workflow_node (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			workflow_id VARCHAR(64) NOT NULL,
+			node_id VARCHAR(64) NOT NULL,
+			task_type VARCHAR(64) NOT NULL,
+			priority INT NOT NULL DEFAULT 0,
+			payload JSON,
+			depends_on JSON,
+			task_id VARCHAR(64),
+			status VARCHAR(32) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			UNIQUE INDEX uk_workflow_node (workflow_id, node_id),
+			INDEX idx_task_id (task_id)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+
+		// dead_letter 表
+		`CREATE TABLE IF NOT EXISTS dead_letter (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			task_id VARCHAR(64) UNIQUE NOT NULL,
+			task_type VARCHAR(64) NOT NULL,
+			error_message TEXT,
+			retry_count INT NOT NULL DEFAULT 0,
+			worker_id VARCHAR(64),
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			INDEX idx_task_type (task_type)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
 	}
 
 	for _, schema := range schemas {
@@ -158,5 +226,56 @@ func (c *Client) InitSchema() error {
 		}
 	}
 
+	return c.migrateSchema()
+}
+
+// schemaMigrations 对已存在的表补充新增的列和索引（按顺序执行，重复执行安全）
+var schemaMigrations = []string{
+	`ALTER TABLE task ADD COLUMN fencing_token BIGINT NOT NULL DEFAULT 0 AFTER worker_id`,
+	`ALTER TABLE task_config ADD COLUMN max_retry_delay INT NOT NULL DEFAULT 0 AFTER backoff_rate`,
+	`ALTER TABLE task_config ADD COLUMN retry_jitter DECIMAL(4,2) NOT NULL DEFAULT 0 AFTER max_retry_delay`,
+	`ALTER TABLE task_config ADD COLUMN rate_limit DECIMAL(10,3) NOT NULL DEFAULT 0 AFTER max_concurrent`,
+	`ALTER TABLE task_config ADD COLUMN rate_burst INT NOT NULL DEFAULT 0 AFTER rate_limit`,
+	`ALTER TABLE task ADD COLUMN idempotency_key VARCHAR(128) NULL AFTER completed_at`,
+	`ALTER TABLE task ADD UNIQUE INDEX uk_task_type_idempotency_key (task_type, idempotency_key)`,
+	`ALTER TABLE task ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default' AFTER idempotency_key`,
+	`ALTER TABLE task ADD INDEX idx_tenant_status (tenant, status)`,
+	`ALTER TABLE task ADD COLUMN result_uri VARCHAR(512) NOT NULL DEFAULT '' AFTER result`,
+	`ALTER TABLE task ADD COLUMN result_size BIGINT NOT NULL DEFAULT 0 AFTER result_uri`,
+	`ALTER TABLE task_config ADD COLUMN payload_schema JSON NULL AFTER rate_burst`,
+	`ALTER TABLE tenant_config ADD COLUMN rate_limit DECIMAL(10,3) NOT NULL DEFAULT 0 AFTER max_in_flight`,
+	`ALTER TABLE tenant_config ADD COLUMN rate_burst INT NOT NULL DEFAULT 0 AFTER rate_limit`,
+}
+
+// migrateSchema 执行增量迁移，忽略列或索引已存在的错误
+func (c *Client) migrateSchema() error {
+	for _, migration := range schemaMigrations {
+		if _, err := c.db.Exec(migration); err != nil && !isAlreadyExistsError(err) {
+			return fmt.Errorf("migrate schema failed: %w", err)
+		}
+	}
 	return nil
 }
+
+// idempotencyKeyIndex 任务类型与幂等键的唯一索引
+const idempotencyKeyIndex = "uk_task_type_idempotency_key"
+
+// isDuplicateKeyError 判断是否为违反指定唯一索引的错误
+func isDuplicateKeyError(err error, index string) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	// 1062: Duplicate entry '...' for key '...'
+	return mysqlErr.Number == 1062 && strings.Contains(mysqlErr.Message, index)
+}
+
+// isAlreadyExistsError 判断是否为列/索引已存在的错误
+func isAlreadyExistsError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	// 1060: Duplicate column name, 1061: Duplicate key name
+	return mysqlErr.Number == 1060 || mysqlErr.Number == 1061
+}
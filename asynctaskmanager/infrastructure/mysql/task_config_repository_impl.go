@@ -28,8 +28,8 @@ func (r *TaskConfigRepositoryImpl) Create(ctx context.Context, config *model.Tas
 	}
 
 	query := `INSERT INTO task_config (task_type, task_name, description, executor_type, executor_config,
-		default_timeout, default_max_retry, retry_strategy, retry_delay, backoff_rate, max_concurrent, enabled, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		default_timeout, default_max_retry, retry_strategy, retry_delay, backoff_rate, max_retry_delay, retry_jitter, max_concurrent, rate_limit, rate_burst, payload_schema, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err = r.client.db.ExecContext(ctx, query,
 		config.TaskType,
@@ -42,7 +42,12 @@ func (r *TaskConfigRepositoryImpl) Create(ctx context.Context, config *model.Tas
 		config.RetryStrategy,
 		config.RetryDelay,
 		config.BackoffRate,
+		config.MaxRetryDelay,
+		config.RetryJitter,
 		config.MaxConcurrent,
+		config.RateLimit,
+		config.RateBurst,
+		payloadSchemaValue(config.PayloadSchema),
 		config.Enabled,
 		config.CreatedAt,
 		config.UpdatedAt,
@@ -58,13 +63,13 @@ func (r *TaskConfigRepositoryImpl) Create(ctx context.Context, config *model.Tas
 // GetByType 根据任务类型查找配置
 func (r *TaskConfigRepositoryImpl) GetByType(ctx context.Context, taskType string) (*model.TaskConfig, error) {
 	query := `SELECT task_type, task_name, description, executor_type, executor_config,
-		default_timeout, default_max_retry, retry_strategy, retry_delay, backoff_rate, max_concurrent, enabled, created_at, updated_at
+		default_timeout, default_max_retry, retry_strategy, retry_delay, backoff_rate, max_retry_delay, retry_jitter, max_concurrent, rate_limit, rate_burst, payload_schema, enabled, created_at, updated_at
 		FROM task_config WHERE task_type = ?`
 
 	row := r.client.db.QueryRowContext(ctx, query, taskType)
 
 	config := &model.TaskConfig{}
-	var executorConfig []byte
+	var executorConfig, payloadSchema []byte
 	var description sql.NullString
 
 	err := row.Scan(
@@ -78,7 +83,12 @@ func (r *TaskConfigRepositoryImpl) GetByType(ctx context.Context, taskType strin
 		&config.RetryStrategy,
 		&config.RetryDelay,
 		&config.BackoffRate,
+		&config.MaxRetryDelay,
+		&config.RetryJitter,
 		&config.MaxConcurrent,
+		&config.RateLimit,
+		&config.RateBurst,
+		&payloadSchema,
 		&config.Enabled,
 		&config.CreatedAt,
 		&config.UpdatedAt,
@@ -95,6 +105,7 @@ func (r *TaskConfigRepositoryImpl) GetByType(ctx context.Context, taskType strin
 	if err := json.Unmarshal(executorConfig, &config.ExecutorConfig); err != nil {
 		return nil, fmt.Errorf("unmarshal executor config failed: %w", err)
 	}
+	config.PayloadSchema = loadPayloadSchema(payloadSchema)
 
 	// 处理可空字段
 	if description.Valid {
@@ -113,7 +124,7 @@ func (r *TaskConfigRepositoryImpl) Update(ctx context.Context, config *model.Tas
 
 	query := `UPDATE task_config SET task_name = ?, description = ?, executor_type = ?, executor_config = ?,
 		default_timeout = ?, default_max_retry = ?, retry_strategy = ?, retry_delay = ?, backoff_rate = ?, 
-		max_concurrent = ?, enabled = ?, updated_at = ? WHERE task_type = ?`
+		max_retry_delay = ?, retry_jitter = ?, max_concurrent = ?, rate_limit = ?, rate_burst = ?, payload_schema = ?, enabled = ?, updated_at = ? WHERE task_type = ?`
 
 	_, err = r.client.db.ExecContext(ctx, query,
 		config.TaskName,
@@ -125,7 +136,12 @@ func (r *TaskConfigRepositoryImpl) Update(ctx context.Context, config *model.Tas
 		config.RetryStrategy,
 		config.RetryDelay,
 		config.BackoffRate,
+		config.MaxRetryDelay,
+		config.RetryJitter,
 		config.MaxConcurrent,
+		config.RateLimit,
+		config.RateBurst,
+		payloadSchemaValue(config.PayloadSchema),
 		config.Enabled,
 		config.UpdatedAt,
 		config.TaskType,
@@ -151,7 +167,7 @@ func (r *TaskConfigRepositoryImpl) Delete(ctx context.Context, taskType string)
 // FindAll 查找所有任务配置
 func (r *TaskConfigRepositoryImpl) FindAll(ctx context.Context) ([]*model.TaskConfig, error) {
 	query := `SELECT task_type, task_name, description, executor_type, executor_config,
-		default_timeout, default_max_retry, retry_strategy, retry_delay, backoff_rate, max_concurrent, enabled, created_at, updated_at
+		default_timeout, default_max_retry, retry_strategy, retry_delay, backoff_rate, max_retry_delay, retry_jitter, max_concurrent, rate_limit, rate_burst, payload_schema, enabled, created_at, updated_at
 		FROM task_config ORDER BY task_type`
 
 	rows, err := r.client.db.QueryContext(ctx, query)
@@ -166,7 +182,7 @@ func (r *TaskConfigRepositoryImpl) FindAll(ctx context.Context) ([]*model.TaskCo
 // FindEnabled 查找启用的任务配置
 func (r *TaskConfigRepositoryImpl) FindEnabled(ctx context.Context) ([]*model.TaskConfig, error) {
 	query := `SELECT task_type, task_name, description, executor_type, executor_config,
-		default_timeout, default_max_retry, retry_strategy, retry_delay, backoff_rate, max_concurrent, enabled, created_at, updated_at
+		default_timeout, default_max_retry, retry_strategy, retry_delay, backoff_rate, max_retry_delay, retry_jitter, max_concurrent, rate_limit, rate_burst, payload_schema, enabled, created_at, updated_at
 		FROM task_config WHERE enabled = TRUE ORDER BY task_type`
 
 	rows, err := r.client.db.QueryContext(ctx, query)
@@ -184,7 +200,7 @@ func (r *TaskConfigRepositoryImpl) scanConfigs(rows *sql.Rows) ([]*model.TaskCon
 
 	for rows.Next() {
 		config := &model.TaskConfig{}
-		var executorConfig []byte
+		var executorConfig, payloadSchema []byte
 		var description sql.NullString
 
 		err := rows.Scan(
@@ -198,7 +214,12 @@ func (r *TaskConfigRepositoryImpl) scanConfigs(rows *sql.Rows) ([]*model.TaskCon
 			&config.RetryStrategy,
 			&config.RetryDelay,
 			&config.BackoffRate,
+			&config.MaxRetryDelay,
+			&config.RetryJitter,
 			&config.MaxConcurrent,
+			&config.RateLimit,
+			&config.RateBurst,
+			&payloadSchema,
 			&config.Enabled,
 			&config.CreatedAt,
 			&config.UpdatedAt,
@@ -212,6 +233,7 @@ func (r *TaskConfigRepositoryImpl) scanConfigs(rows *sql.Rows) ([]*model.TaskCon
 		if err := json.Unmarshal(executorConfig, &config.ExecutorConfig); err != nil {
 			return nil, fmt.Errorf("unmarshal executor config failed: %w", err)
 		}
+		config.PayloadSchema = loadPayloadSchema(payloadSchema)
 
 		// 处理可空字段
 		if description.Valid {
@@ -227,3 +249,20 @@ func (r *TaskConfigRepositoryImpl) scanConfigs(rows *sql.Rows) ([]*model.TaskCon
 
 	return configs, nil
 }
+
+// payloadSchemaValue payload_schema 列的写入值，未配置时写入 NULL
+func payloadSchemaValue(schema *model.PayloadSchema) interface{} {
+	if schema == nil {
+		return nil
+	}
+	return []byte(schema.Raw())
+}
+
+// loadPayloadSchema 加载 payload_schema 列，NULL 表示不校验
+// schema 在写入时已严格校验，读取时宽松解析，不会因 schema 导致读取配置失败
+func loadPayloadSchema(data []byte) *model.PayloadSchema {
+	if len(data) == 0 {
+		return nil
+	}
+	return model.LoadPayloadSchema(data)
+}
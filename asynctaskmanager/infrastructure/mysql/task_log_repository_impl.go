@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"bamboo/asynctaskmanager/domain/model"
 	"bamboo/asynctaskmanager/domain/repository"
@@ -43,6 +44,37 @@ func (r *TaskLogRepositoryImpl) Create(ctx context.Context, log *model.TaskLog)
 	return nil
 }
 
+// CreateBatch 以多行 INSERT 批量创建任务日志
+func (r *TaskLogRepositoryImpl) CreateBatch(ctx context.Context, logs []*model.TaskLog) error {
+	for start := 0; start < len(logs); start += insertBatchSize {
+		chunk := logs[start:min(start+insertBatchSize, len(logs))]
+
+		placeholders := make([]string, 0, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*9)
+		for _, log := range chunk {
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			args = append(args,
+				log.TaskID,
+				log.LogType,
+				log.FromStatus,
+				log.ToStatus,
+				log.Message,
+				log.WorkerID,
+				log.RetryCount,
+				log.ErrorDetail,
+				log.CreatedAt,
+			)
+		}
+
+		query := `INSERT INTO task_log (task_id, log_type, from_status, to_status, message, worker_id, retry_count, error_detail, created_at)
+		VALUES ` + strings.Join(placeholders, ", ")
+		if _, err := r.client.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("insert task logs failed: %w", err)
+		}
+	}
+	return nil
+}
+
 // GetByTaskID 根据任务ID查找日志
 func (r *TaskLogRepositoryImpl) GetByTaskID(ctx context.Context, taskID string) ([]*model.TaskLog, error) {
 	query := `SELECT id, task_id, log_type, from_status, to_status, message, worker_id, retry_count, error_detail, created_at
@@ -5,12 +5,20 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"bamboo/asynctaskmanager/domain/model"
 	"bamboo/asynctaskmanager/domain/repository"
 )
 
+// taskColumns 任务查询的列，顺序与 scanTask 一致
+const taskColumns = `id, task_id, task_type, priority, status, payload, result, error_message, worker_id, fencing_token,
+		retry_count, max_retry, timeout, scheduled_at, created_at, updated_at, started_at, completed_at, idempotency_key, tenant, result_uri, result_size`
+
+// insertBatchSize 多行 INSERT 单条语句的最大行数，避免超出占位符数量与 max_allowed_packet 限制
+const insertBatchSize = 500
+
 // TaskRepositoryImpl Task 仓储 MySQL 实现
 type TaskRepositoryImpl struct {
 	client *Client
@@ -28,8 +36,8 @@ func (r *TaskRepositoryImpl) Create(ctx context.Context, task *model.Task) error
 		return fmt.Errorf("marshal payload failed: %w", err)
 	}
 
-	query := `INSERT INTO task (task_id, task_type, priority, status, payload, retry_count, max_retry, timeout, scheduled_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO task (task_id, task_type, priority, status, payload, fencing_token, retry_count, max_retry, timeout, scheduled_at, created_at, updated_at, idempotency_key, tenant)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	now := time.Now()
 	_, err = r.client.db.ExecContext(ctx, query,
@@ -38,14 +46,20 @@ func (r *TaskRepositoryImpl) Create(ctx context.Context, task *model.Task) error
 		task.Priority.Value(),
 		task.Status,
 		payload,
+		task.FencingToken,
 		task.RetryCount,
 		task.MaxRetry,
 		task.Timeout,
 		task.ScheduledAt,
 		task.CreatedAt,
 		now,
+		sql.NullString{String: task.IdempotencyKey, Valid: task.IdempotencyKey != ""},
+		task.Tenant,
 	)
 
+	if isDuplicateKeyError(err, idempotencyKeyIndex) {
+		return fmt.Errorf("insert task failed: %w", repository.ErrDuplicateIdempotencyKey)
+	}
 	if err != nil {
 		return fmt.Errorf("insert task failed: %w", err)
 	}
@@ -53,40 +67,72 @@ func (r *TaskRepositoryImpl) Create(ctx context.Context, task *model.Task) error
 	return nil
 }
 
-// GetByID 根据ID查找任务
-func (r *TaskRepositoryImpl) GetByID(ctx context.Context, taskID string) (*model.Task, error) {
-	query := `SELECT id, task_id, task_type, priority, status, payload, result, error_message, worker_id, 
-		retry_count, max_retry, timeout, scheduled_at, created_at, updated_at, started_at, completed_at
-		FROM task WHERE task_id = ?`
+// CreateBatch 在同一事务中以多行 INSERT 批量创建任务
+func (r *TaskRepositoryImpl) CreateBatch(ctx context.Context, tasks []*model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
 
-	row := r.client.db.QueryRowContext(ctx, query, taskID)
+	tx, err := r.client.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction failed: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
 
-	task := &model.Task{}
-	var payload, result []byte
-	var errorMessage, workerID sql.NullString
-	var startedAt, completedAt sql.NullTime
-	var priority int
+	now := time.Now()
+	for start := 0; start < len(tasks); start += insertBatchSize {
+		chunk := tasks[start:min(start+insertBatchSize, len(tasks))]
+
+		placeholders := make([]string, 0, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*14)
+		for _, task := range chunk {
+			payload, err := json.Marshal(task.Payload)
+			if err != nil {
+				return fmt.Errorf("marshal payload of task %s failed: %w", task.TaskID, err)
+			}
 
-	err := row.Scan(
-		&task.ID,
-		&task.TaskID,
-		&task.TaskType,
-		&priority,
-		&task.Status,
-		&payload,
-		&result,
-		&errorMessage,
-		&workerID,
-		&task.RetryCount,
-		&task.MaxRetry,
-		&task.Timeout,
-		&task.ScheduledAt,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-		&startedAt,
-		&completedAt,
-	)
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			args = append(args,
+				task.TaskID,
+				task.TaskType,
+				task.Priority.Value(),
+				task.Status,
+				payload,
+				task.FencingToken,
+				task.RetryCount,
+				task.MaxRetry,
+				task.Timeout,
+				task.ScheduledAt,
+				task.CreatedAt,
+				now,
+				sql.NullString{String: task.IdempotencyKey, Valid: task.IdempotencyKey != ""},
+				task.Tenant,
+			)
+		}
+
+		query := `INSERT INTO task (task_id, task_type, priority, status, payload, fencing_token, retry_count, max_retry, timeout, scheduled_at, created_at, updated_at, idempotency_key, tenant)
+		VALUES ` + strings.Join(placeholders, ", ")
+		_, err := tx.ExecContext(ctx, query, args...)
+		if isDuplicateKeyError(err, idempotencyKeyIndex) {
+			return fmt.Errorf("insert tasks failed: %w", repository.ErrDuplicateIdempotencyKey)
+		}
+		if err != nil {
+			return fmt.Errorf("insert tasks failed: %w", err)
+		}
+	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tasks failed: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID查找任务
+func (r *TaskRepositoryImpl) GetByID(ctx context.Context, taskID string) (*model.Task, error) {
+	query := `SELECT ` + taskColumns + `
+		FROM task WHERE task_id = ?`
+
+	task, err := scanTask(r.client.db.QueryRowContext(ctx, query, taskID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("task not found: %s", taskID)
 	}
@@ -94,69 +140,116 @@ func (r *TaskRepositoryImpl) GetByID(ctx context.Context, taskID string) (*model
 		return nil, fmt.Errorf("query task failed: %w", err)
 	}
 
-	// 解析 priority
-	if priority == 1 {
-		task.Priority = model.PriorityHigh
-	} else {
-		task.Priority = model.PriorityNormal
-	}
-
-	// 解析 payload
-	if err := json.Unmarshal(payload, &task.Payload); err != nil {
-		return nil, fmt.Errorf("unmarshal payload failed: %w", err)
-	}
+	return task, nil
+}
 
-	// 解析 result
-	if len(result) > 0 {
-		if err := json.Unmarshal(result, &task.Result); err != nil {
-			return nil, fmt.Errorf("unmarshal result failed: %w", err)
-		}
-	}
+// GetByIdempotencyKey 根据任务类型与幂等键查找任务
+func (r *TaskRepositoryImpl) GetByIdempotencyKey(ctx context.Context, taskType, idempotencyKey string) (*model.Task, error) {
+	query := `SELECT ` + taskColumns + `
+		FROM task WHERE task_type = ? AND idempotency_key = ?`
 
-	// 处理可空字段
-	if errorMessage.Valid {
-		task.ErrorMsg = errorMessage.String
-	}
-	if workerID.Valid {
-		task.WorkerID = workerID.String
-	}
-	if startedAt.Valid {
-		task.StartedAt = &startedAt.Time
+	task, err := scanTask(r.client.db.QueryRowContext(ctx, query, taskType, idempotencyKey))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("task not found: %s/%s", taskType, idempotencyKey)
 	}
-	if completedAt.Valid {
-		task.CompletedAt = &completedAt.Time
+	if err != nil {
+		return nil, fmt.Errorf("query task failed: %w", err)
 	}
 
 	return task, nil
 }
 
+// FindByIdempotencyKeys 查找任务类型下幂等键对应的已有任务
+func (r *TaskRepositoryImpl) FindByIdempotencyKeys(ctx context.Context, taskType string, idempotencyKeys []string) ([]*model.Task, error) {
+	var tasks []*model.Task
+	for start := 0; start < len(idempotencyKeys); start += insertBatchSize {
+		chunk := idempotencyKeys[start:min(start+insertBatchSize, len(idempotencyKeys))]
+
+		placeholders := make([]string, 0, len(chunk))
+		args := make([]interface{}, 0, len(chunk)+1)
+		args = append(args, taskType)
+		for _, key := range chunk {
+			placeholders = append(placeholders, "?")
+			args = append(args, key)
+		}
+
+		query := `SELECT ` + taskColumns + `
+		FROM task WHERE task_type = ? AND idempotency_key IN (` + strings.Join(placeholders, ", ") + `)`
+		rows, err := r.client.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("query tasks by idempotency keys failed: %w", err)
+		}
+		found, err := r.scanTasks(rows)
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, found...)
+	}
+	return tasks, nil
+}
+
 // Update 更新任务
 func (r *TaskRepositoryImpl) Update(ctx context.Context, task *model.Task) error {
+	payload, err := json.Marshal(task.Payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload failed: %w", err)
+	}
+
 	result, err := json.Marshal(task.Result)
 	if err != nil {
 		return fmt.Errorf("marshal result failed: %w", err)
 	}
 
-	query := `UPDATE task SET status = ?, result = ?, error_message = ?, worker_id = ?, 
-		retry_count = ?, scheduled_at = ?, started_at = ?, completed_at = ?, updated_at = ? WHERE task_id = ?`
+	// 只有携带的 fencing token 不小于已记录的 token 时才写入
+	query := `UPDATE task SET status = ?, priority = ?, payload = ?, result = ?, result_uri = ?, result_size = ?, error_message = ?,
+		worker_id = ?, fencing_token = ?, retry_count = ?, scheduled_at = ?, started_at = ?, completed_at = ?, updated_at = ?
+		WHERE task_id = ? AND fencing_token <= ?`
 
-	_, err = r.client.db.ExecContext(ctx, query,
+	res, err := r.client.db.ExecContext(ctx, query,
 		task.Status,
+		task.Priority.Value(),
+		payload,
 		result,
+		task.ResultURI,
+		task.ResultSize,
 		task.ErrorMsg,
 		task.WorkerID,
+		task.FencingToken,
 		task.RetryCount,
 		task.ScheduledAt,
 		task.StartedAt,
 		task.CompletedAt,
 		time.Now(),
 		task.TaskID,
+		task.FencingToken,
 	)
 
 	if err != nil {
 		return fmt.Errorf("update task failed: %w", err)
 	}
 
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows failed: %w", err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	// 未更新任何行：任务不存在、token 过期，或内容未变化
+	var current int64
+	err = r.client.db.QueryRowContext(ctx, `SELECT fencing_token FROM task WHERE task_id = ?`, task.TaskID).Scan(&current)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("task not found: %s", task.TaskID)
+	}
+	if err != nil {
+		return fmt.Errorf("query fencing token failed: %w", err)
+	}
+	if current > task.FencingToken {
+		return fmt.Errorf("update task %s rejected: %w", task.TaskID, repository.ErrStaleFencingToken)
+	}
+
 	return nil
 }
 
@@ -172,8 +265,7 @@ func (r *TaskRepositoryImpl) Delete(ctx context.Context, taskID string) error {
 
 // FindPendingTasks 查找待执行的任务
 func (r *TaskRepositoryImpl) FindPendingTasks(ctx context.Context, limit int) ([]*model.Task, error) {
-	query := `SELECT id, task_id, task_type, priority, status, payload, result, error_message, worker_id, 
-		retry_count, max_retry, timeout, scheduled_at, created_at, updated_at, started_at, completed_at
+	query := `SELECT ` + taskColumns + `
 		FROM task WHERE status = ? AND scheduled_at <= ?
 		ORDER BY priority DESC, created_at ASC LIMIT ?`
 
@@ -188,8 +280,7 @@ func (r *TaskRepositoryImpl) FindPendingTasks(ctx context.Context, limit int) ([
 
 // FindProcessingTasks 查找正在执行的任务
 func (r *TaskRepositoryImpl) FindProcessingTasks(ctx context.Context) ([]*model.Task, error) {
-	query := `SELECT id, task_id, task_type, priority, status, payload, result, error_message, worker_id, 
-		retry_count, max_retry, timeout, scheduled_at, created_at, updated_at, started_at, completed_at
+	query := `SELECT ` + taskColumns + `
 		FROM task WHERE status = ?`
 
 	rows, err := r.client.db.QueryContext(ctx, query, model.StatusProcessing)
@@ -203,8 +294,7 @@ func (r *TaskRepositoryImpl) FindProcessingTasks(ctx context.Context) ([]*model.
 
 // FindTimeoutTasks 查找超时的任务
 func (r *TaskRepositoryImpl) FindTimeoutTasks(ctx context.Context) ([]*model.Task, error) {
-	query := `SELECT id, task_id, task_type, priority, status, payload, result, error_message, worker_id, 
-		retry_count, max_retry, timeout, scheduled_at, created_at, updated_at, started_at, completed_at
+	query := `SELECT ` + taskColumns + `
 		FROM task WHERE status = ? AND started_at IS NOT NULL 
 		AND TIMESTAMPDIFF(SECOND, started_at, NOW()) > timeout`
 
@@ -219,8 +309,7 @@ func (r *TaskRepositoryImpl) FindTimeoutTasks(ctx context.Context) ([]*model.Tas
 
 // FindByStatus 根据状态查找任务
 func (r *TaskRepositoryImpl) FindByStatus(ctx context.Context, status model.TaskStatus, limit int) ([]*model.Task, error) {
-	query := `SELECT id, task_id, task_type, priority, status, payload, result, error_message, worker_id, 
-		retry_count, max_retry, timeout, scheduled_at, created_at, updated_at, started_at, completed_at
+	query := `SELECT ` + taskColumns + `
 		FROM task WHERE status = ? ORDER BY created_at DESC LIMIT ?`
 
 	rows, err := r.client.db.QueryContext(ctx, query, status, limit)
@@ -232,74 +321,95 @@ func (r *TaskRepositoryImpl) FindByStatus(ctx context.Context, status model.Task
 	return r.scanTasks(rows)
 }
 
-// scanTasks 扫描任务列表
-func (r *TaskRepositoryImpl) scanTasks(rows *sql.Rows) ([]*model.Task, error) {
-	tasks := make([]*model.Task, 0)
-
-	for rows.Next() {
-		task := &model.Task{}
-		var payload, result []byte
-		var errorMessage, workerID sql.NullString
-		var startedAt, completedAt sql.NullTime
-		var priority int
-
-		err := rows.Scan(
-			&task.ID,
-			&task.TaskID,
-			&task.TaskType,
-			&priority,
-			&task.Status,
-			&payload,
-			&result,
-			&errorMessage,
-			&workerID,
-			&task.RetryCount,
-			&task.MaxRetry,
-			&task.Timeout,
-			&task.ScheduledAt,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&startedAt,
-			&completedAt,
-		)
+// List 按条件分页查询任务
+func (r *TaskRepositoryImpl) List(ctx context.Context, query *repository.TaskQuery) ([]*model.Task, int64, error) {
+	where, args := buildTaskQueryConditions(query)
 
-		if err != nil {
-			return nil, fmt.Errorf("scan task failed: %w", err)
+	var total int64
+	if !query.SkipCount {
+		countQuery := `SELECT COUNT(*) FROM task` + where
+		if err := r.client.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("count tasks failed: %w", err)
 		}
+	}
 
-		// 解析 priority
-		if priority == 1 {
-			task.Priority = model.PriorityHigh
+	// keyset 分页：主键倒序，从游标之后开始
+	if query.BeforeID > 0 {
+		if where == "" {
+			where = " WHERE id < ?"
 		} else {
-			task.Priority = model.PriorityNormal
+			where += " AND id < ?"
 		}
+		args = append(args, query.BeforeID)
+	}
+	args = append(args, query.Limit)
 
-		// 解析 payload
-		if err := json.Unmarshal(payload, &task.Payload); err != nil {
-			return nil, fmt.Errorf("unmarshal payload failed: %w", err)
-		}
+	listQuery := `SELECT ` + taskColumns + `
+		FROM task` + where + ` ORDER BY id DESC LIMIT ?`
 
-		// 解析 result
-		if len(result) > 0 {
-			if err := json.Unmarshal(result, &task.Result); err != nil {
-				return nil, fmt.Errorf("unmarshal result failed: %w", err)
-			}
-		}
+	rows, err := r.client.db.QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query tasks failed: %w", err)
+	}
+	defer rows.Close()
 
-		// 处理可空字段
-		if errorMessage.Valid {
-			task.ErrorMsg = errorMessage.String
-		}
-		if workerID.Valid {
-			task.WorkerID = workerID.String
-		}
-		if startedAt.Valid {
-			task.StartedAt = &startedAt.Time
-		}
-		if completedAt.Valid {
-			task.CompletedAt = &completedAt.Time
-		}
+	tasks, err := r.scanTasks(rows)
+	if err != nil {
+		return nil, 0, err
+	}
 
+	return tasks, total, nil
+}
+
+// buildTaskQueryConditions 构建过滤条件（不含分页游标）
+func buildTaskQueryConditions(query *repository.TaskQuery) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if query.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, query.Status)
+	}
+	if query.Priority != nil {
+		conditions = append(conditions, "priority = ?")
+		args = append(args, query.Priority.Value())
+	}
+	if query.TaskType != "" {
+		conditions = append(conditions, "task_type = ?")
+		args = append(args, query.TaskType)
+	}
+	if query.Tenant != "" {
+		conditions = append(conditions, "tenant = ?")
+		args = append(args, query.Tenant)
+	}
+	if query.WorkerID != "" {
+		conditions = append(conditions, "worker_id = ?")
+		args = append(args, query.WorkerID)
+	}
+	if !query.CreatedAfter.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.CreatedAfter)
+	}
+	if !query.CreatedBefore.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.CreatedBefore)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// scanTasks 扫描任务列表
+func (r *TaskRepositoryImpl) scanTasks(rows *sql.Rows) ([]*model.Task, error) {
+	tasks := make([]*model.Task, 0)
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan task failed: %w", err)
+		}
 		tasks = append(tasks, task)
 	}
 
@@ -309,3 +419,84 @@ func (r *TaskRepositoryImpl) scanTasks(rows *sql.Rows) ([]*model.Task, error) {
 
 	return tasks, nil
 }
+
+// rowScanner 抽象 *sql.Row 与 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// priorityFromDB 将 priority 列转换为任务优先级，超出取值范围的值按最近的边界处理
+func priorityFromDB(priority int) model.TaskPriority {
+	return max(model.PriorityMin, min(model.TaskPriority(priority), model.PriorityMax))
+}
+
+// scanTask 按 taskColumns 的顺序扫描单个任务
+func scanTask(row rowScanner) (*model.Task, error) {
+	task := &model.Task{}
+	var payload, result []byte
+	var errorMessage, workerID, idempotencyKey sql.NullString
+	var startedAt, completedAt sql.NullTime
+	var priority int
+
+	err := row.Scan(
+		&task.ID,
+		&task.TaskID,
+		&task.TaskType,
+		&priority,
+		&task.Status,
+		&payload,
+		&result,
+		&errorMessage,
+		&workerID,
+		&task.FencingToken,
+		&task.RetryCount,
+		&task.MaxRetry,
+		&task.Timeout,
+		&task.ScheduledAt,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&startedAt,
+		&completedAt,
+		&idempotencyKey,
+		&task.Tenant,
+		&task.ResultURI,
+		&task.ResultSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// 解析 priority
+	task.Priority = priorityFromDB(priority)
+
+	// 解析 payload
+	if err := json.Unmarshal(payload, &task.Payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload failed: %w", err)
+	}
+
+	// 解析 result
+	if len(result) > 0 {
+		if err := json.Unmarshal(result, &task.Result); err != nil {
+			return nil, fmt.Errorf("unmarshal result failed: %w", err)
+		}
+	}
+
+	// 处理可空字段
+	if errorMessage.Valid {
+		task.ErrorMsg = errorMessage.String
+	}
+	if workerID.Valid {
+		task.WorkerID = workerID.String
+	}
+	if startedAt.Valid {
+		task.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		task.CompletedAt = &completedAt.Time
+	}
+	if idempotencyKey.Valid {
+		task.IdempotencyKey = idempotencyKey.String
+	}
+
+	return task, nil
+}
@@ -0,0 +1,112 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+)
+
+// tenantConfigColumns 租户配置查询的列，顺序与 scanTenantConfig 一致
+const tenantConfigColumns = `id, tenant, quantum, max_in_flight, rate_limit, rate_burst, created_at, updated_at`
+
+// TenantConfigRepositoryImpl TenantConfig 仓储 MySQL 实现
+type TenantConfigRepositoryImpl struct {
+	client *Client
+}
+
+// NewTenantConfigRepository 创建 TenantConfig 仓储
+func NewTenantConfigRepository(client *Client) repository.TenantConfigRepository {
+	return &TenantConfigRepositoryImpl{client: client}
+}
+
+// Save 创建或更新租户配置
+func (r *TenantConfigRepositoryImpl) Save(ctx context.Context, config *model.TenantConfig) error {
+	query := `INSERT INTO tenant_config (tenant, quantum, max_in_flight, rate_limit, rate_burst, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE quantum = VALUES(quantum), max_in_flight = VALUES(max_in_flight),
+			rate_limit = VALUES(rate_limit), rate_burst = VALUES(rate_burst), updated_at = VALUES(updated_at)`
+
+	now := time.Now()
+	_, err := r.client.db.ExecContext(ctx, query,
+		config.Tenant,
+		config.Quantum,
+		config.MaxInFlight,
+		config.RateLimit,
+		config.RateBurst,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("save tenant config failed: %w", err)
+	}
+	return nil
+}
+
+// GetByTenant 根据租户查找配置
+func (r *TenantConfigRepositoryImpl) GetByTenant(ctx context.Context, tenant string) (*model.TenantConfig, error) {
+	query := `SELECT ` + tenantConfigColumns + ` FROM tenant_config WHERE tenant = ?`
+
+	config, err := scanTenantConfig(r.client.db.QueryRowContext(ctx, query, tenant))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrTenantConfigNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query tenant config failed: %w", err)
+	}
+	return config, nil
+}
+
+// FindAll 查找所有租户配置
+func (r *TenantConfigRepositoryImpl) FindAll(ctx context.Context) ([]*model.TenantConfig, error) {
+	query := `SELECT ` + tenantConfigColumns + ` FROM tenant_config ORDER BY tenant`
+
+	rows, err := r.client.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query tenant configs failed: %w", err)
+	}
+	defer rows.Close()
+
+	configs := make([]*model.TenantConfig, 0)
+	for rows.Next() {
+		config, err := scanTenantConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan tenant config failed: %w", err)
+		}
+		configs = append(configs, config)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+	return configs, nil
+}
+
+// Delete 删除租户配置
+func (r *TenantConfigRepositoryImpl) Delete(ctx context.Context, tenant string) error {
+	if _, err := r.client.db.ExecContext(ctx, `DELETE FROM tenant_config WHERE tenant = ?`, tenant); err != nil {
+		return fmt.Errorf("delete tenant config failed: %w", err)
+	}
+	return nil
+}
+
+// scanTenantConfig 按 tenantConfigColumns 的顺序扫描单个租户配置
+func scanTenantConfig(row rowScanner) (*model.TenantConfig, error) {
+	config := &model.TenantConfig{}
+	err := row.Scan(
+		&config.ID,
+		&config.Tenant,
+		&config.Quantum,
+		&config.MaxInFlight,
+		&config.RateLimit,
+		&config.RateBurst,
+		&config.CreatedAt,
+		&config.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -133,6 +133,33 @@ func (r *WorkerRepositoryImpl) Remove(ctx context.Context, workerID string) erro
 	return nil
 }
 
+// FindExpired 查找心跳已过期的 Worker ID
+func (r *WorkerRepositoryImpl) FindExpired(ctx context.Context, timeout time.Duration) ([]string, error) {
+	query := `SELECT worker_id FROM worker WHERE last_heartbeat < ?`
+
+	rows, err := r.client.db.QueryContext(ctx, query, time.Now().Add(-timeout))
+	if err != nil {
+		return nil, fmt.Errorf("query expired workers failed: %w", err)
+	}
+	defer rows.Close()
+
+	workerIDs := make([]string, 0)
+	for rows.Next() {
+		var workerID string
+		if err := rows.Scan(&workerID); err != nil {
+			return nil, fmt.Errorf("scan worker id failed: %w", err)
+		}
+		workerIDs = append(workerIDs, workerID)
+	}
+
+	return workerIDs, rows.Err()
+}
+
+// Purge 清除已过期 Worker
+func (r *WorkerRepositoryImpl) Purge(ctx context.Context, workerID string) error {
+	return r.Remove(ctx, workerID)
+}
+
 // FindAll 查找所有 Worker
 func (r *WorkerRepositoryImpl) FindAll(ctx context.Context) ([]*model.Worker, error) {
 	query := `SELECT id, worker_id, worker_name, address, status, capacity, current_load, supported_types, last_heartbeat, created_at, updated_at
@@ -197,6 +224,16 @@ func (r *WorkerRepositoryImpl) UpdateLoad(ctx context.Context, workerID string,
 	return nil
 }
 
+// IncrLoad 原子地增减负载
+func (r *WorkerRepositoryImpl) IncrLoad(ctx context.Context, workerID string, delta int) error {
+	query := `UPDATE worker SET current_load = GREATEST(current_load + ?, 0) WHERE worker_id = ?`
+	_, err := r.client.db.ExecContext(ctx, query, delta, workerID)
+	if err != nil {
+		return fmt.Errorf("incr load failed: %w", err)
+	}
+	return nil
+}
+
 // scanWorkers 扫描 Worker 列表
 func (r *WorkerRepositoryImpl) scanWorkers(rows *sql.Rows) ([]*model.Worker, error) {
 	workers := make([]*model.Worker, 0)
@@ -0,0 +1,277 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+)
+
+// workflowColumns 工作流查询的列，顺序与 scanWorkflow 一致
+const workflowColumns = `workflow_id, name, status, failure_policy, created_at, updated_at, completed_at, id`
+
+// workflowNodeColumns 节点查询的列，顺序与 scanWorkflowNode 一致
+const workflowNodeColumns = `id, workflow_id, node_id, task_type, priority, payload, depends_on, task_id, status, created_at, updated_at`
+
+// WorkflowRepositoryImpl Workflow 仓储 MySQL 实现
+type WorkflowRepositoryImpl struct {
+	client *Client
+}
+
+// NewWorkflowRepository 创建 Workflow 仓储
+func NewWorkflowRepository(client *Client) repository.WorkflowRepository {
+	return &WorkflowRepositoryImpl{client: client}
+}
+
+// Create 在同一事务中写入工作流及其节点
+func (r *WorkflowRepositoryImpl) Create(ctx context.Context, workflow *model.Workflow) error {
+	tx, err := r.client.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction failed: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO workflow (workflow_id, name, status, failure_policy, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		workflow.WorkflowID,
+		workflow.Name,
+		workflow.Status,
+		workflow.FailurePolicy,
+		workflow.CreatedAt,
+		workflow.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert workflow failed: %w", err)
+	}
+	if workflow.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("get workflow id failed: %w", err)
+	}
+
+	// 多行插入所有节点
+	placeholders := make([]string, 0, len(workflow.Nodes))
+	args := make([]interface{}, 0, len(workflow.Nodes)*9)
+	for _, node := range workflow.Nodes {
+		payload, err := json.Marshal(node.Payload)
+		if err != nil {
+			return fmt.Errorf("marshal node payload failed: %w", err)
+		}
+		dependsOn, err := json.Marshal(node.DependsOn)
+		if err != nil {
+			return fmt.Errorf("marshal node dependencies failed: %w", err)
+		}
+
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			node.WorkflowID,
+			node.NodeID,
+			node.TaskType,
+			node.Priority.Value(),
+			payload,
+			dependsOn,
+			node.Status,
+			node.CreatedAt,
+			node.UpdatedAt,
+		)
+	}
+
+	query := `INSERT INTO workflow_node (workflow_id, node_id, task_type, priority, payload, depends_on, status, created_at, updated_at)
+		VALUES ` + strings.Join(placeholders, ", ")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("insert workflow nodes failed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit workflow failed: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID查找工作流
+func (r *WorkflowRepositoryImpl) GetByID(ctx context.Context, workflowID string) (*model.Workflow, error) {
+	query := `SELECT ` + workflowColumns + ` FROM workflow WHERE workflow_id = ?`
+
+	workflow, err := scanWorkflow(r.client.db.QueryRowContext(ctx, query, workflowID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("workflow not found: %s", workflowID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query workflow failed: %w", err)
+	}
+
+	if err := r.loadNodes(ctx, []*model.Workflow{workflow}); err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// UpdateStatus 更新工作流状态
+func (r *WorkflowRepositoryImpl) UpdateStatus(ctx context.Context, workflow *model.Workflow) error {
+	query := `UPDATE workflow SET status = ?, completed_at = ?, updated_at = ? WHERE workflow_id = ?`
+
+	_, err := r.client.db.ExecContext(ctx, query,
+		workflow.Status,
+		workflow.CompletedAt,
+		time.Now(),
+		workflow.WorkflowID,
+	)
+	if err != nil {
+		return fmt.Errorf("update workflow failed: %w", err)
+	}
+	return nil
+}
+
+// UpdateNode 更新节点状态与关联的任务ID
+func (r *WorkflowRepositoryImpl) UpdateNode(ctx context.Context, node *model.WorkflowNode) error {
+	query := `UPDATE workflow_node SET task_id = ?, status = ?, updated_at = ? WHERE workflow_id = ? AND node_id = ?`
+
+	_, err := r.client.db.ExecContext(ctx, query,
+		sql.NullString{String: node.TaskID, Valid: node.TaskID != ""},
+		node.Status,
+		time.Now(),
+		node.WorkflowID,
+		node.NodeID,
+	)
+	if err != nil {
+		return fmt.Errorf("update workflow node failed: %w", err)
+	}
+	return nil
+}
+
+// FindRunning 查找执行中的工作流，按创建顺序返回
+func (r *WorkflowRepositoryImpl) FindRunning(ctx context.Context, limit int) ([]*model.Workflow, error) {
+	query := `SELECT ` + workflowColumns + ` FROM workflow WHERE status = ? ORDER BY id ASC LIMIT ?`
+
+	rows, err := r.client.db.QueryContext(ctx, query, model.WorkflowRunning, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query running workflows failed: %w", err)
+	}
+	defer rows.Close()
+
+	workflows := make([]*model.Workflow, 0)
+	for rows.Next() {
+		workflow, err := scanWorkflow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan workflow failed: %w", err)
+		}
+		workflows = append(workflows, workflow)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	if err := r.loadNodes(ctx, workflows); err != nil {
+		return nil, err
+	}
+	return workflows, nil
+}
+
+// loadNodes 一次查询加载多个工作流的节点
+func (r *WorkflowRepositoryImpl) loadNodes(ctx context.Context, workflows []*model.Workflow) error {
+	if len(workflows) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*model.Workflow, len(workflows))
+	placeholders := make([]string, 0, len(workflows))
+	args := make([]interface{}, 0, len(workflows))
+	for _, workflow := range workflows {
+		byID[workflow.WorkflowID] = workflow
+		placeholders = append(placeholders, "?")
+		args = append(args, workflow.WorkflowID)
+	}
+
+	query := `SELECT ` + workflowNodeColumns + ` FROM workflow_node
+		WHERE workflow_id IN (` + strings.Join(placeholders, ", ") + `) ORDER BY id ASC`
+
+	rows, err := r.client.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query workflow nodes failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		node, err := scanWorkflowNode(rows)
+		if err != nil {
+			return fmt.Errorf("scan workflow node failed: %w", err)
+		}
+		if workflow, ok := byID[node.WorkflowID]; ok {
+			workflow.Nodes = append(workflow.Nodes, node)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration failed: %w", err)
+	}
+	return nil
+}
+
+// scanWorkflow 按 workflowColumns 的顺序扫描工作流
+func scanWorkflow(row rowScanner) (*model.Workflow, error) {
+	workflow := &model.Workflow{}
+	var completedAt sql.NullTime
+
+	err := row.Scan(
+		&workflow.WorkflowID,
+		&workflow.Name,
+		&workflow.Status,
+		&workflow.FailurePolicy,
+		&workflow.CreatedAt,
+		&workflow.UpdatedAt,
+		&completedAt,
+		&workflow.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if completedAt.Valid {
+		workflow.CompletedAt = &completedAt.Time
+	}
+	return workflow, nil
+}
+
+// scanWorkflowNode 按 workflowNodeColumns 的顺序扫描节点
+func scanWorkflowNode(row rowScanner) (*model.WorkflowNode, error) {
+	node := &model.WorkflowNode{}
+	var payload, dependsOn []byte
+	var taskID sql.NullString
+	var priority int
+
+	err := row.Scan(
+		&node.ID,
+		&node.WorkflowID,
+		&node.NodeID,
+		&node.TaskType,
+		&priority,
+		&payload,
+		&dependsOn,
+		&taskID,
+		&node.Status,
+		&node.CreatedAt,
+		&node.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	node.Priority = priorityFromDB(priority)
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &node.Payload); err != nil {
+			return nil, fmt.Errorf("unmarshal node payload failed: %w", err)
+		}
+	}
+	if len(dependsOn) > 0 {
+		if err := json.Unmarshal(dependsOn, &node.DependsOn); err != nil {
+			return nil, fmt.Errorf("unmarshal node dependencies failed: %w", err)
+		}
+	}
+	if taskID.Valid {
+		node.TaskID = taskID.String
+	}
+	return node, nil
+}
@@ -0,0 +1,189 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+const (
+	// concurrencyTypesKey 存在执行中或暂存任务的并发范围集合（任务类型或租户）
+	concurrencyTypesKey = "concurrency:types"
+	// tenantScopePrefix 租户并发范围的前缀
+	tenantScopePrefix = "tenant:"
+	// parkedTargetKey 暂存任务放回时的目标就绪队列（task_id -> queue）
+	parkedTargetKey = "concurrency:parked:target"
+)
+
+// acquireSlotScript 并发范围的执行中任务数未达上限时占用一个槽位，重复占用视为成功
+// KEYS[1]=执行中集合 KEYS[2]=并发范围集合 ARGV[1]=任务ID ARGV[2]=上限 ARGV[3]=并发范围
+var acquireSlotScript = redis.NewScript(`
+if redis.call('SISMEMBER', KEYS[1], ARGV[1]) == 1 then
+	return 1
+end
+if redis.call('SCARD', KEYS[1]) >= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('SADD', KEYS[1], ARGV[1])
+redis.call('SADD', KEYS[2], ARGV[3])
+return 1
+`)
+
+// parkScript 将超出并发上限的任务暂存到并发范围的等待列表
+// KEYS[1]=等待列表 KEYS[2]=目标队列哈希 KEYS[3]=并发范围集合 ARGV[1]=任务ID ARGV[2]=目标队列 ARGV[3]=并发范围
+var parkScript = redis.NewScript(`
+redis.call('LPUSH', KEYS[1], ARGV[1])
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+redis.call('SADD', KEYS[3], ARGV[3])
+return 1
+`)
+
+// unparkScript 将至多 ARGV[1] 个暂存任务按先进先出放回就绪队列并写入唤醒信号
+// 等待列表与执行中集合都为空时从并发范围集合移除
+// KEYS[1]=等待列表 KEYS[2]=目标队列哈希 KEYS[3]=唤醒信号 KEYS[4]=执行中集合 KEYS[5]=并发范围集合 KEYS[6]=非空就绪队列索引
+// ARGV[1]=数量上限 ARGV[2]=默认队列 ARGV[3]=并发范围
+var unparkScript = redis.NewScript(`
+local n = 0
+while n < tonumber(ARGV[1]) do
+	local id = redis.call('RPOP', KEYS[1])
+	if not id then
+		break
+	end
+	local queue = redis.call('HGET', KEYS[2], id)
+	if not queue then
+		queue = ARGV[2]
+	end
+	redis.call('HDEL', KEYS[2], id)
+	redis.call('LPUSH', queue, id)
+	redis.call('SADD', KEYS[6], queue)
+	n = n + 1
+end
+if n > 0 then
+	redis.call('LPUSH', KEYS[3], 1)
+	redis.call('LTRIM', KEYS[3], 0, 0)
+end
+if redis.call('LLEN', KEYS[1]) == 0 and redis.call('SCARD', KEYS[4]) == 0 then
+	redis.call('SREM', KEYS[5], ARGV[3])
+end
+return n
+`)
+
+// ConcurrencyLimiter 按并发范围限制集群内同时执行的任务数，范围为任务类型或租户
+// 调度器分配前占用槽位，超出上限的任务暂存到等待列表；Worker 处理结束后释放槽位，并放回一个暂存任务
+type ConcurrencyLimiter struct {
+	client *Client
+}
+
+// NewConcurrencyLimiter 创建并发限制器
+func NewConcurrencyLimiter(client *Client) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{client: client}
+}
+
+// TaskTypeConcurrencyScope 任务类型的并发范围
+func TaskTypeConcurrencyScope(taskType string) string {
+	return taskType
+}
+
+// TenantConcurrencyScope 租户的并发范围，未指定租户时为默认租户
+func TenantConcurrencyScope(tenant string) string {
+	if tenant == "" {
+		tenant = model.DefaultTenant
+	}
+	return tenantScopePrefix + tenant
+}
+
+// ParseTenantConcurrencyScope 解析租户并发范围，不是租户范围时返回 false
+func ParseTenantConcurrencyScope(scope string) (string, bool) {
+	return strings.CutPrefix(scope, tenantScopePrefix)
+}
+
+// Acquire 为任务占用并发范围的一个槽位，limit 小于等于 0 表示不限制
+func (cl *ConcurrencyLimiter) Acquire(ctx context.Context, scope, taskID string, limit int) (bool, error) {
+	if limit <= 0 {
+		return true, nil
+	}
+
+	ok, err := cl.client.RunScript(ctx, acquireSlotScript,
+		[]string{runningSlotsKey(scope), concurrencyTypesKey},
+		taskID, limit, scope,
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("acquire concurrency slot failed: %w", err)
+	}
+	return ok == 1, nil
+}
+
+// Release 释放任务占用的槽位，并将一个暂存任务放回就绪队列重新调度
+// 放回的任务仍需重新占用槽位，重复释放只会带来一次多余的调度
+func (cl *ConcurrencyLimiter) Release(ctx context.Context, scope, taskID string) error {
+	if err := cl.client.SRem(ctx, runningSlotsKey(scope), taskID); err != nil {
+		return fmt.Errorf("release concurrency slot failed: %w", err)
+	}
+
+	_, err := cl.Unpark(ctx, scope, 1)
+	return err
+}
+
+// ReleaseTask 释放任务在任务类型与所属租户上占用的槽位
+func (cl *ConcurrencyLimiter) ReleaseTask(ctx context.Context, task *model.Task) error {
+	return errors.Join(
+		cl.Release(ctx, TaskTypeConcurrencyScope(task.TaskType), task.TaskID),
+		cl.Release(ctx, TenantConcurrencyScope(task.Tenant), task.TaskID),
+	)
+}
+
+// Park 将超出并发范围上限的任务暂存，释放槽位或 Leader 巡检时放回任务所属的就绪队列
+func (cl *ConcurrencyLimiter) Park(ctx context.Context, scope string, task *model.Task) error {
+	err := cl.client.RunScript(ctx, parkScript,
+		[]string{parkedKey(scope), parkedTargetKey, concurrencyTypesKey},
+		task.TaskID, QueueNameFor(task.Tenant, task.Priority), scope,
+	).Err()
+	if err != nil {
+		return fmt.Errorf("park task %s failed: %w", task.TaskID, err)
+	}
+	return nil
+}
+
+// Unpark 将至多 n 个暂存任务放回就绪队列，返回放回的任务数
+func (cl *ConcurrencyLimiter) Unpark(ctx context.Context, scope string, n int) (int, error) {
+	moved, err := cl.client.RunScript(ctx, unparkScript,
+		[]string{
+			parkedKey(scope),
+			parkedTargetKey,
+			readySignalKey,
+			runningSlotsKey(scope),
+			concurrencyTypesKey,
+			readyActiveKey,
+		},
+		n, QueueNormal, scope,
+	).Int()
+	if err != nil {
+		return 0, fmt.Errorf("unpark %s tasks failed: %w", scope, err)
+	}
+	return moved, nil
+}
+
+// Types 存在执行中或暂存任务的并发范围
+func (cl *ConcurrencyLimiter) Types(ctx context.Context) ([]string, error) {
+	return cl.client.SMembers(ctx, concurrencyTypesKey)
+}
+
+// Running 占用并发范围槽位的任务ID
+func (cl *ConcurrencyLimiter) Running(ctx context.Context, scope string) ([]string, error) {
+	return cl.client.SMembers(ctx, runningSlotsKey(scope))
+}
+
+// runningSlotsKey 并发范围的执行中任务集合
+func runningSlotsKey(scope string) string {
+	return fmt.Sprintf("concurrency:%s:running", scope)
+}
+
+// parkedKey 并发范围的暂存任务列表
+func parkedKey(scope string) string {
+	return fmt.Sprintf("concurrency:%s:parked", scope)
+}
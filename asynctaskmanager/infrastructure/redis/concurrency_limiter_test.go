@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+func newTestConcurrencyLimiter(t *testing.T) (*ConcurrencyLimiter, *QueueManager, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewConcurrencyLimiter(client), NewQueueManager(client), mr
+}
+
+func TestConcurrencyLimiter_Acquire(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		taskID string
+		want   bool
+	}{
+		{name: "unlimited", limit: 0, taskID: "task-3", want: true},
+		{name: "below limit", limit: 3, taskID: "task-3", want: true},
+		{name: "at limit", limit: 2, taskID: "task-3", want: false},
+		{name: "already acquired", limit: 2, taskID: "task-1", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, _, _ := newTestConcurrencyLimiter(t)
+			ctx := context.Background()
+
+			for _, taskID := range []string{"task-1", "task-2"} {
+				if ok, err := cl.Acquire(ctx, "report", taskID, 2); err != nil || !ok {
+					t.Fatalf("Acquire(%s) = %v, %v", taskID, ok, err)
+				}
+			}
+
+			got, err := cl.Acquire(ctx, "report", tt.taskID, tt.limit)
+			if err != nil {
+				t.Fatalf("Acquire() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Acquire() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimiter_ReleaseUnparks(t *testing.T) {
+	cl, qm, mr := newTestConcurrencyLimiter(t)
+	ctx := context.Background()
+
+	if ok, _ := cl.Acquire(ctx, "report", "task-1", 1); !ok {
+		t.Fatal("Acquire() = false, want true")
+	}
+	_ = cl.Park(ctx, "report", &model.Task{TaskID: "task-2", Priority: model.PriorityHigh})
+	_ = cl.Park(ctx, "report", &model.Task{TaskID: "task-3", Priority: model.PriorityNormal})
+
+	if types, _ := cl.Types(ctx); len(types) != 1 || types[0] != "report" {
+		t.Errorf("Types() = %v, want [report]", types)
+	}
+
+	// 释放后按先进先出放回一个暂存任务到原优先级队列
+	if err := cl.Release(ctx, "report", "task-1"); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if got, err := qm.ReserveTask(ctx, "scheduler-1"); err != nil || got != "task-2" {
+		t.Errorf("ReserveTask() = %s, %v, want task-2", got, err)
+	}
+	if n, _ := qm.GetQueueLength(ctx, QueueNormal); n != 0 {
+		t.Errorf("normal queue length = %d, want 0", n)
+	}
+	if !mr.Exists(readySignalKey) {
+		t.Error("ready signal should be written when tasks are unparked")
+	}
+
+	// 暂存与执行中均为空后移出任务类型集合
+	if n, err := cl.Unpark(ctx, "report", 10); err != nil || n != 1 {
+		t.Errorf("Unpark() = %d, %v, want 1", n, err)
+	}
+	if types, _ := cl.Types(ctx); len(types) != 0 {
+		t.Errorf("Types() = %v, want empty", types)
+	}
+}
@@ -0,0 +1,342 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+const (
+	// readyCreditsKey 就绪队列的加权轮询积分（queue -> credit）
+	readyCreditsKey = "queue:ready:credits"
+	// readyHeadSinceKey 就绪队列队首任务到达队首的时间（task_id -> 毫秒时间戳），用于老化
+	readyHeadSinceKey = "queue:ready:head_since"
+	// readyActiveKey 可能非空的就绪队列索引，任务进入就绪队列时加入，出队时发现队列为空后移除
+	readyActiveKey = "queue:ready:active"
+
+	// defaultAgingThreshold 默认老化阈值
+	defaultAgingThreshold = 30 * time.Second
+)
+
+// readyQueues 默认租户的全部就绪队列，按优先级从高到低排列，其他租户的队列名在其后追加 ":{tenant}"
+var readyQueues = func() []string {
+	queues := make([]string, 0, model.PriorityLevels)
+	for p := model.PriorityMax; p >= model.PriorityMin; p-- {
+		queues = append(queues, priorityQueueName(p))
+	}
+	return queues
+}()
+
+// reserveReadyScript 按租户配额与出队策略将至多 ARGV[2] 个任务从就绪队列移入消费者的处理中列表，并记录来源队列
+// 租户之间按赤字轮询（DRR）调度：轮到某个租户时其赤字加上配额，每取出一个任务扣减 1，赤字不足或队列取空时轮到下一个租户
+// 租户内部：队首任务等待超过老化阈值的队列优先出队（等待最久的优先），否则在非空队列间按权重做平滑加权轮询，积分相同时优先级高的队列优先
+// 轮询位置、赤字与积分持久化在 Redis 中，多个调度器轮流出队或分批取出时比例依然成立
+// 只处理调用方传入的租户，每个租户的就绪队列按优先级从高到低依次传入；发现为空的队列从非空就绪队列索引移除
+// KEYS[1]=处理中列表 KEYS[2]=来源哈希 KEYS[3]=消费者集合 KEYS[4]=积分哈希 KEYS[5]=队首时间哈希
+// KEYS[6]=非空就绪队列索引 KEYS[7]=租户配额哈希 KEYS[8]=租户轮询状态 KEYS[9..]=各租户的就绪队列
+// ARGV[1]=消费者ID ARGV[2]=数量上限 ARGV[3]=老化阈值毫秒（0 表示不老化） ARGV[4]=每个租户的就绪队列数 n
+// ARGV[5..4+n]=与租户就绪队列一一对应的权重 ARGV[5+n..]=按名称排序的租户
+var reserveReadyScript = redis.NewScript(`
+local limit = tonumber(ARGV[2])
+local aging = tonumber(ARGV[3])
+local n = tonumber(ARGV[4])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+
+local tenants = {}
+for i = n + 5, #ARGV do
+	table.insert(tenants, ARGV[i])
+end
+
+local function queues_of(t)
+	local queues = {}
+	for i = 1, n do
+		queues[i] = KEYS[8 + (t - 1) * n + i]
+	end
+	return queues
+end
+
+local credits = {}
+local function credit(queue)
+	if credits[queue] == nil then
+		credits[queue] = tonumber(redis.call('HGET', KEYS[4], queue) or '0')
+	end
+	return credits[queue]
+end
+
+local function pop(queues)
+	local heads = {}
+	local chosen = nil
+	local oldest = nil
+	for i = 1, n do
+		local head = redis.call('LINDEX', queues[i], -1)
+		if head then
+			heads[i] = true
+			local since = tonumber(redis.call('HGET', KEYS[5], head) or '0')
+			if since == 0 then
+				since = now
+				redis.call('HSET', KEYS[5], head, now)
+			end
+			if aging > 0 and now - since >= aging and (oldest == nil or since < oldest) then
+				chosen = i
+				oldest = since
+			end
+		elseif credit(queues[i]) ~= 0 then
+			credits[queues[i]] = 0
+		end
+	end
+	if chosen == nil then
+		local total = 0
+		for i = 1, n do
+			local weight = tonumber(ARGV[i + 4])
+			if heads[i] and weight > 0 then
+				credits[queues[i]] = credit(queues[i]) + weight
+				total = total + weight
+				if chosen == nil or credits[queues[i]] > credits[queues[chosen]] then
+					chosen = i
+				end
+			end
+		end
+		if chosen == nil then
+			return nil
+		end
+		credits[queues[chosen]] = credits[queues[chosen]] - total
+	end
+	local id = redis.call('LMOVE', queues[chosen], KEYS[1], 'RIGHT', 'LEFT')
+	redis.call('HSET', KEYS[2], id, queues[chosen])
+	return id
+end
+
+local function quantum(tenant)
+	return math.max(1, tonumber(redis.call('HGET', KEYS[7], tenant) or '1'))
+end
+
+local sizes = {}
+local remaining = 0
+for t = 1, #tenants do
+	local size = 0
+	for _, queue in ipairs(queues_of(t)) do
+		local length = redis.call('LLEN', queue)
+		if length == 0 then
+			redis.call('SREM', KEYS[6], queue)
+		end
+		size = size + length
+	end
+	sizes[t] = size
+	remaining = remaining + size
+end
+
+local state = redis.call('HMGET', KEYS[8], 'tenant', 'deficit')
+local pos = nil
+local deficit = 0
+for t, tenant in ipairs(tenants) do
+	if tenant == state[1] then
+		pos = t
+		deficit = tonumber(state[2]) or 0
+	end
+end
+if pos == nil then
+	-- 上次轮到的租户已没有就绪任务，从排在它之后的租户继续
+	pos = 1
+	for t, tenant in ipairs(tenants) do
+		if state[1] and tenant > state[1] then
+			pos = t
+			break
+		end
+	end
+	deficit = quantum(tenants[pos])
+end
+
+local ids = {}
+while #ids < limit and remaining > 0 do
+	if sizes[pos] > 0 and deficit >= 1 then
+		local id = pop(queues_of(pos))
+		if id then
+			table.insert(ids, id)
+			sizes[pos] = sizes[pos] - 1
+			remaining = remaining - 1
+			deficit = deficit - 1
+		else
+			-- 队首任务暂不能出队（所在优先级权重为 0 且未老化），本次不再轮到该租户
+			remaining = remaining - sizes[pos]
+			sizes[pos] = 0
+		end
+	end
+	if sizes[pos] == 0 or deficit < 1 then
+		pos = pos % #tenants + 1
+		deficit = quantum(tenants[pos])
+	end
+end
+
+redis.call('HSET', KEYS[8], 'tenant', tenants[pos], 'deficit', deficit)
+for queue, value in pairs(credits) do
+	if value == 0 then
+		redis.call('HDEL', KEYS[4], queue)
+	else
+		redis.call('HSET', KEYS[4], queue, value)
+	end
+end
+if #ids > 0 then
+	redis.call('SADD', KEYS[3], ARGV[1])
+end
+return ids
+`)
+
+// DequeuePolicy 就绪队列出队策略
+type DequeuePolicy struct {
+	// Weights 各优先级的权重，下标为优先级，长度须为 model.PriorityLevels
+	// 非空就绪队列按权重比例轮流出队，权重为 0 的优先级只在老化后出队
+	Weights []int
+	// AgingThreshold 老化阈值，任务在队首等待超过该时长后不论权重优先出队，0 表示不老化
+	AgingThreshold time.Duration
+}
+
+// DefaultDequeuePolicy 默认出队策略：优先级 p 的权重为 p+1，队首等待超过 30 秒的任务优先出队
+func DefaultDequeuePolicy() DequeuePolicy {
+	weights := make([]int, model.PriorityLevels)
+	for i := range weights {
+		weights[i] = i + 1
+	}
+	return DequeuePolicy{Weights: weights, AgingThreshold: defaultAgingThreshold}
+}
+
+// Validate 校验出队策略
+func (p DequeuePolicy) Validate() error {
+	if len(p.Weights) != model.PriorityLevels {
+		return fmt.Errorf("dequeue policy needs %d weights, got %d", model.PriorityLevels, len(p.Weights))
+	}
+	positive := false
+	for priority, weight := range p.Weights {
+		if weight < 0 {
+			return fmt.Errorf("weight of priority %d must not be negative", priority)
+		}
+		if weight > 0 {
+			positive = true
+		}
+	}
+	if !positive {
+		return errors.New("dequeue policy needs at least one positive weight")
+	}
+	if p.AgingThreshold < 0 {
+		return errors.New("aging threshold must not be negative")
+	}
+	return nil
+}
+
+// SetDequeuePolicy 设置就绪队列出队策略，需在开始出队前调用
+func (qm *QueueManager) SetDequeuePolicy(policy DequeuePolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	qm.policy = policy
+	return nil
+}
+
+// reserveReady 按租户配额与出队策略从就绪队列取出至多 limit 个任务
+// 只有非空就绪队列索引中的租户参与轮询，脚本访问的队列全部通过 KEYS 传入
+func (qm *QueueManager) reserveReady(ctx context.Context, consumerID string, limit int) ([]string, error) {
+	active, err := qm.client.SMembers(ctx, readyActiveKey)
+	if err != nil {
+		return nil, fmt.Errorf("list active ready queues failed: %w", err)
+	}
+	tenants := activeTenants(active)
+	if len(tenants) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, 8+len(tenants)*len(readyQueues))
+	keys = append(keys,
+		processingKey(consumerID),
+		processingOriginKey(consumerID),
+		processingConsumersKey,
+		readyCreditsKey,
+		readyHeadSinceKey,
+		readyActiveKey,
+		tenantQuantaKey,
+		tenantRoundKey,
+	)
+	for _, tenant := range tenants {
+		for p := model.PriorityMax; p >= model.PriorityMin; p-- {
+			keys = append(keys, QueueNameFor(tenant, p))
+		}
+	}
+
+	args := make([]interface{}, 0, 4+len(readyQueues)+len(tenants))
+	args = append(args, consumerID, limit, qm.policy.AgingThreshold.Milliseconds(), len(readyQueues))
+	for p := model.PriorityMax; p >= model.PriorityMin; p-- {
+		args = append(args, qm.policy.Weights[p])
+	}
+	for _, tenant := range tenants {
+		args = append(args, tenant)
+	}
+
+	ids, err := qm.client.RunScript(ctx, reserveReadyScript, keys, args...).StringSlice()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("reserve ready tasks failed: %w", err)
+	}
+	return ids, nil
+}
+
+// RebuildReadyIndex 将默认租户与旧版本租户集合中各租户的全部就绪队列加入非空就绪队列索引，并删除旧的租户集合
+// 升级前已在就绪队列中的任务因此可以被取出；其中的空队列在下一次出队时移出索引
+func (qm *QueueManager) RebuildReadyIndex(ctx context.Context) error {
+	tenants, err := qm.client.SMembers(ctx, legacyTenantsKey)
+	if err != nil {
+		return fmt.Errorf("list legacy tenants failed: %w", err)
+	}
+	tenants = append(tenants, model.DefaultTenant)
+
+	queues := make([]interface{}, 0, len(tenants)*len(readyQueues))
+	for _, tenant := range tenants {
+		for p := model.PriorityMax; p >= model.PriorityMin; p-- {
+			queues = append(queues, QueueNameFor(tenant, p))
+		}
+	}
+
+	err = qm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, readyActiveKey, queues...)
+		pipe.Del(ctx, legacyTenantsKey)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("rebuild ready index failed: %w", err)
+	}
+	return nil
+}
+
+// activeTenants 从非空就绪队列索引解析出有就绪任务的租户，按名称排序
+func activeTenants(queues []string) []string {
+	seen := make(map[string]bool)
+	tenants := make([]string, 0)
+	for _, queue := range queues {
+		tenant, ok := readyQueueTenant(queue)
+		if !ok || seen[tenant] {
+			continue
+		}
+		seen[tenant] = true
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// readyQueueTenant 按 QueueNameFor 的规则从就绪队列名解析所属租户，不是就绪队列时返回 false
+// 租户名不含 ":"，队列名的解析没有歧义
+func readyQueueTenant(queue string) (string, bool) {
+	for _, name := range readyQueues {
+		if queue == name {
+			return model.DefaultTenant, true
+		}
+		if tenant, found := strings.CutPrefix(queue, name+":"); found && tenant != "" {
+			return tenant, true
+		}
+	}
+	return "", false
+}
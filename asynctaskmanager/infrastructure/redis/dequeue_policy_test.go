@@ -0,0 +1,280 @@
+package redis
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+func TestQueueNameFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		tenant   string
+		priority model.TaskPriority
+		want     string
+	}{
+		{"普通优先级沿用原队列", model.DefaultTenant, model.PriorityNormal, QueueNormal},
+		{"高优先级沿用原队列", model.DefaultTenant, model.PriorityHigh, QueueHigh},
+		{"其他优先级独立队列", model.DefaultTenant, model.TaskPriority(5), "queue:p5"},
+		{"最高优先级", model.DefaultTenant, model.PriorityMax, "queue:p9"},
+		{"超出范围归入普通队列", model.DefaultTenant, model.PriorityMax + 1, QueueNormal},
+		{"未指定租户归入默认租户", "", model.PriorityHigh, QueueHigh},
+		{"其他租户独立队列", "acme", model.PriorityNormal, "queue:normal:acme"},
+		{"其他租户其他优先级", "acme", model.TaskPriority(5), "queue:p5:acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QueueNameFor(tt.tenant, tt.priority); got != tt.want {
+				t.Errorf("QueueNameFor(%q, %d) = %s, want %s", tt.tenant, tt.priority, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDequeuePolicy_Validate(t *testing.T) {
+	weights := func(w ...int) []int {
+		out := make([]int, model.PriorityLevels)
+		copy(out, w)
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		policy  DequeuePolicy
+		wantErr bool
+	}{
+		{"默认策略", DefaultDequeuePolicy(), false},
+		{"部分权重为 0", DequeuePolicy{Weights: weights(0, 1)}, false},
+		{"权重个数不对", DequeuePolicy{Weights: []int{1, 2}}, true},
+		{"权重为负", DequeuePolicy{Weights: weights(1, -1)}, true},
+		{"权重全为 0", DequeuePolicy{Weights: weights()}, true},
+		{"老化阈值为负", DequeuePolicy{Weights: weights(1), AgingThreshold: -time.Second}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueueManager_WeightedDequeue(t *testing.T) {
+	qm, _ := newTestQueueManager(t)
+	ctx := context.Background()
+
+	policy := DequeuePolicy{Weights: make([]int, model.PriorityLevels)}
+	policy.Weights[1] = 1
+	policy.Weights[2] = 2
+	if err := qm.SetDequeuePolicy(policy); err != nil {
+		t.Fatalf("SetDequeuePolicy() error = %v", err)
+	}
+
+	for _, id := range []string{"a1", "a2", "a3", "a4"} {
+		_ = qm.PushTask(ctx, &model.Task{TaskID: id, Priority: model.PriorityHigh})
+	}
+	for _, id := range []string{"b1", "b2", "b3", "b4", "b5", "b6"} {
+		_ = qm.PushTask(ctx, &model.Task{TaskID: id, Priority: model.TaskPriority(2)})
+	}
+
+	// 权重 2:1 交替出队，高优先级队列不会独占；积分保存在 Redis 中，分多次出队时比例不变
+	var ids []string
+	for _, limit := range []int{3, 7} {
+		batch, err := qm.WaitTasks(ctx, "scheduler-1", limit, time.Second)
+		if err != nil {
+			t.Fatalf("WaitTasks() error = %v", err)
+		}
+		ids = append(ids, batch...)
+	}
+	want := []string{"b1", "a1", "b2", "b3", "a2", "b4", "b5", "a3", "b6", "a4"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("WaitTasks() = %v, want %v", ids, want)
+	}
+}
+
+func TestQueueManager_DequeueAging(t *testing.T) {
+	qm, mr := newTestQueueManager(t)
+	ctx := context.Background()
+
+	// 优先级 0 权重为 0，只能靠老化出队
+	policy := DequeuePolicy{Weights: make([]int, model.PriorityLevels), AgingThreshold: time.Minute}
+	policy.Weights[1] = 1
+	if err := qm.SetDequeuePolicy(policy); err != nil {
+		t.Fatalf("SetDequeuePolicy() error = %v", err)
+	}
+
+	start := time.Now()
+	mr.SetTime(start)
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "low-1", Priority: model.PriorityNormal})
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "high-1", Priority: model.PriorityHigh})
+
+	ids, err := qm.WaitTasks(ctx, "scheduler-1", 10, time.Second)
+	if err != nil {
+		t.Fatalf("WaitTasks() error = %v", err)
+	}
+	if !slices.Equal(ids, []string{"high-1"}) {
+		t.Fatalf("WaitTasks() = %v, want [high-1]", ids)
+	}
+
+	// 队首等待未超过阈值时不出队
+	mr.SetTime(start.Add(30 * time.Second))
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "high-2", Priority: model.PriorityHigh})
+	if id, _ := qm.ReserveTask(ctx, "scheduler-1"); id != "high-2" {
+		t.Fatalf("ReserveTask() = %s, want high-2", id)
+	}
+
+	// 超过阈值后不论权重优先出队
+	mr.SetTime(start.Add(2 * time.Minute))
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "high-3", Priority: model.PriorityHigh})
+	for _, want := range []string{"low-1", "high-3"} {
+		got, err := qm.ReserveTask(ctx, "scheduler-1")
+		if err != nil {
+			t.Fatalf("ReserveTask() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("ReserveTask() = %s, want %s", got, want)
+		}
+	}
+	for _, id := range []string{"high-1", "high-2", "low-1", "high-3"} {
+		_ = qm.AckTask(ctx, "scheduler-1", id)
+	}
+	if mr.Exists(readyHeadSinceKey) {
+		t.Error("head since records should be removed after acking")
+	}
+}
+
+func TestQueueManager_TenantDequeue(t *testing.T) {
+	qm, mr := newTestQueueManager(t)
+	ctx := context.Background()
+
+	if err := qm.SetTenantQuanta(ctx, map[string]int{"acme": 2}); err != nil {
+		t.Fatalf("SetTenantQuanta() error = %v", err)
+	}
+
+	// acme 积压大量任务，不影响其他租户按配额轮流出队
+	for _, id := range []string{"a1", "a2", "a3", "a4", "a5", "a6"} {
+		_ = qm.PushTask(ctx, &model.Task{TaskID: id, Tenant: "acme"})
+	}
+	for _, id := range []string{"b1", "b2"} {
+		_ = qm.PushTask(ctx, &model.Task{TaskID: id, Tenant: "beta"})
+	}
+	for _, id := range []string{"d1", "d2"} {
+		_ = qm.PushTask(ctx, &model.Task{TaskID: id})
+	}
+	if got := mr.Exists("queue:normal:acme"); !got {
+		t.Fatal("tenant tasks should be pushed to the tenant queue")
+	}
+
+	// 轮询状态保存在 Redis 中，分多次出队时顺序不变
+	var ids []string
+	for _, limit := range []int{3, 7} {
+		batch, err := qm.WaitTasks(ctx, "scheduler-1", limit, time.Second)
+		if err != nil {
+			t.Fatalf("WaitTasks() error = %v", err)
+		}
+		ids = append(ids, batch...)
+	}
+	want := []string{"a1", "a2", "b1", "d1", "a3", "a4", "b2", "d2", "a5", "a6"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("WaitTasks() = %v, want %v", ids, want)
+	}
+}
+
+func TestReadyQueueTenant(t *testing.T) {
+	tests := []struct {
+		queue      string
+		wantTenant string
+		wantOK     bool
+	}{
+		{QueueNormal, model.DefaultTenant, true},
+		{"queue:p5", model.DefaultTenant, true},
+		{"queue:normal:acme", "acme", true},
+		{"queue:p9:acme.eu", "acme.eu", true},
+		{"queue:normal:", "", false},
+		{QueueDelayed, "", false},
+		{"queue:processing:scheduler-1", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.queue, func(t *testing.T) {
+			tenant, ok := readyQueueTenant(tt.queue)
+			if tenant != tt.wantTenant || ok != tt.wantOK {
+				t.Errorf("readyQueueTenant(%q) = %q, %v, want %q, %v", tt.queue, tenant, ok, tt.wantTenant, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestQueueManager_ReadyIndex(t *testing.T) {
+	qm, mr := newTestQueueManager(t)
+	ctx := context.Background()
+
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "a1", Tenant: "acme"})
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "b1", Tenant: "beta", Priority: model.PriorityHigh})
+	_ = qm.PushDelayedTask(ctx, &model.Task{TaskID: "c1", Tenant: "gamma"}, time.Now().Add(-time.Second))
+	if _, err := qm.PromoteDueTasks(ctx, time.Now(), 10); err != nil {
+		t.Fatalf("PromoteDueTasks() error = %v", err)
+	}
+	for _, queue := range []string{"queue:normal:acme", "queue:high:beta", "queue:normal:gamma"} {
+		if ok, _ := mr.SIsMember(readyActiveKey, queue); !ok {
+			t.Errorf("%s should be indexed after push", queue)
+		}
+	}
+
+	// 上次轮到的租户已不在索引中时，从排在它之后的租户继续
+	mr.HSet(tenantRoundKey, "tenant", "abc", "deficit", "1")
+	ids, err := qm.WaitTasks(ctx, "scheduler-1", 10, time.Second)
+	if err != nil {
+		t.Fatalf("WaitTasks() error = %v", err)
+	}
+	if want := []string{"a1", "b1", "c1"}; !slices.Equal(ids, want) {
+		t.Errorf("WaitTasks() = %v, want %v", ids, want)
+	}
+	mr.HSet(tenantRoundKey, "tenant", "azure", "deficit", "1")
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "a2", Tenant: "acme"})
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "b2", Tenant: "beta"})
+	if id, _ := qm.ReserveTask(ctx, "scheduler-1"); id != "b2" {
+		t.Errorf("ReserveTask() = %s, want b2", id)
+	}
+
+	// 取空的队列在下一次出队时移出索引，空闲租户不再参与轮询
+	if id, _ := qm.ReserveTask(ctx, "scheduler-1"); id != "a2" {
+		t.Errorf("ReserveTask() = %s, want a2", id)
+	}
+	if _, err := qm.ReserveTask(ctx, "scheduler-1"); err == nil {
+		t.Error("ReserveTask() should return redis.Nil when all ready queues are empty")
+	}
+	if members, _ := mr.SMembers(readyActiveKey); len(members) != 0 {
+		t.Errorf("ready index = %v, want empty", members)
+	}
+}
+
+func TestQueueManager_RebuildReadyIndex(t *testing.T) {
+	qm, mr := newTestQueueManager(t)
+	ctx := context.Background()
+
+	// 升级前的任务只在就绪队列与旧的租户集合中
+	_, _ = mr.Lpush("queue:p5:acme", "a1")
+	_, _ = mr.Lpush(QueueNormal, "d1")
+	_, _ = mr.SAdd(legacyTenantsKey, "acme")
+
+	if err := qm.RebuildReadyIndex(ctx); err != nil {
+		t.Fatalf("RebuildReadyIndex() error = %v", err)
+	}
+	ids, err := qm.WaitTasks(ctx, "scheduler-1", 10, time.Second)
+	if err != nil {
+		t.Fatalf("WaitTasks() error = %v", err)
+	}
+	slices.Sort(ids)
+	if want := []string{"a1", "d1"}; !slices.Equal(ids, want) {
+		t.Errorf("WaitTasks() = %v, want %v", ids, want)
+	}
+	if mr.Exists(legacyTenantsKey) {
+		t.Error("legacy tenants set should be removed")
+	}
+}
@@ -3,67 +3,138 @@ package redis
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 const (
 	leaderKey = "scheduler:leader"
 	leaderTTL = 10 * time.Second
+
+	// leaderEpochKey 单调递增的 fencing token 计数器
+	leaderEpochKey = "scheduler:leader:epoch"
 )
 
+// acquireLeaderScript 原子地获取 Leader 锁并分配新的 fencing token
+// 锁值为 "{scheduler_id}|{token}"；锁已被自己持有时（如进程重启）重新分配 token
+// KEYS[1]=Leader 锁 KEYS[2]=token 计数器 ARGV[1]=调度器ID ARGV[2]=TTL 毫秒
+// 获取失败返回 0
+var acquireLeaderScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current and string.match(current, '^(.*)|%d+$') ~= ARGV[1] then
+	return 0
+end
+local token = redis.call('INCR', KEYS[2])
+redis.call('SET', KEYS[1], ARGV[1] .. '|' .. token, 'PX', ARGV[2])
+return token
+`)
+
+// renewLeaderScript 锁值一致时续约
+// KEYS[1]=Leader 锁 ARGV[1]=锁值 ARGV[2]=TTL 毫秒
+var renewLeaderScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLeaderScript 锁值一致时释放
+// KEYS[1]=Leader 锁 ARGV[1]=锁值
+var releaseLeaderScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
 // LeaderElection Leader 选举
 type LeaderElection struct {
-	client     *Client
+	client      *Client
 	schedulerID string
+
+	mu    sync.RWMutex
+	token int64
 }
 
 // NewLeaderElection 创建 Leader 选举
 func NewLeaderElection(client *Client, schedulerID string) *LeaderElection {
 	return &LeaderElection{
-		client:     client,
+		client:      client,
 		schedulerID: schedulerID,
 	}
 }
 
-// TryAcquire 尝试获取 Leader 锁
-func (le *LeaderElection) TryAcquire(ctx context.Context) (bool, error) {
-	acquired, err := le.client.SetNX(ctx, leaderKey, le.schedulerID, leaderTTL)
+// SchedulerID 当前调度器ID
+func (le *LeaderElection) SchedulerID() string {
+	return le.schedulerID
+}
+
+// Token 当前任期的 fencing token，未成为 Leader 时为 0
+func (le *LeaderElection) Token() int64 {
+	le.mu.RLock()
+	defer le.mu.RUnlock()
+	return le.token
+}
+
+// TryAcquire 尝试获取 Leader 锁，成功时返回本任期单调递增的 fencing token
+func (le *LeaderElection) TryAcquire(ctx context.Context) (int64, bool, error) {
+	token, err := le.client.RunScript(ctx, acquireLeaderScript,
+		[]string{leaderKey, leaderEpochKey},
+		le.schedulerID, leaderTTL.Milliseconds(),
+	).Int64()
 	if err != nil {
-		return false, fmt.Errorf("acquire leader lock failed: %w", err)
+		return 0, false, fmt.Errorf("acquire leader lock failed: %w", err)
+	}
+	if token == 0 {
+		return 0, false, nil
 	}
 
-	return acquired, nil
+	le.mu.Lock()
+	le.token = token
+	le.mu.Unlock()
+
+	return token, true, nil
 }
 
 // Renew 续约 Leader 锁
 func (le *LeaderElection) Renew(ctx context.Context) error {
-	// 检查当前 leader 是否是自己
-	currentLeader, err := le.client.Get(ctx, leaderKey)
+	renewed, err := le.client.RunScript(ctx, renewLeaderScript,
+		[]string{leaderKey},
+		le.lockValue(), leaderTTL.Milliseconds(),
+	).Int()
 	if err != nil {
-		return fmt.Errorf("get current leader failed: %w", err)
+		return fmt.Errorf("renew leader lock failed: %w", err)
 	}
 
-	if currentLeader != le.schedulerID {
+	if renewed == 0 {
+		le.resetToken()
 		return fmt.Errorf("not the current leader")
 	}
 
-	// 续约
-	return le.client.Expire(ctx, leaderKey, leaderTTL)
+	return nil
 }
 
 // Release 释放 Leader 锁
 func (le *LeaderElection) Release(ctx context.Context) error {
-	// 检查当前 leader 是否是自己
-	currentLeader, err := le.client.Get(ctx, leaderKey)
+	defer le.resetToken()
+
+	released, err := le.client.RunScript(ctx, releaseLeaderScript,
+		[]string{leaderKey},
+		le.lockValue(),
+	).Int()
 	if err != nil {
-		return fmt.Errorf("get current leader failed: %w", err)
+		return fmt.Errorf("release leader lock failed: %w", err)
 	}
 
-	if currentLeader != le.schedulerID {
+	if released == 0 {
 		return fmt.Errorf("not the current leader")
 	}
 
-	return le.client.Del(ctx, leaderKey)
+	return nil
 }
 
 // IsLeader 判断是否是 Leader
@@ -73,10 +144,42 @@ func (le *LeaderElection) IsLeader(ctx context.Context) (bool, error) {
 		return false, nil
 	}
 
-	return currentLeader == le.schedulerID, nil
+	return currentLeader == le.lockValue(), nil
 }
 
 // GetLeader 获取当前 Leader
 func (le *LeaderElection) GetLeader(ctx context.Context) (string, error) {
-	return le.client.Get(ctx, leaderKey)
+	value, err := le.client.Get(ctx, leaderKey)
+	if err != nil {
+		return "", err
+	}
+
+	leader, _, _ := parseLeaderValue(value)
+	return leader, nil
+}
+
+// lockValue 本任期的锁值
+func (le *LeaderElection) lockValue() string {
+	return le.schedulerID + "|" + strconv.FormatInt(le.Token(), 10)
+}
+
+// resetToken 失去 Leader 身份后清空 token
+func (le *LeaderElection) resetToken() {
+	le.mu.Lock()
+	le.token = 0
+	le.mu.Unlock()
+}
+
+// parseLeaderValue 解析锁值为调度器ID与 token
+func parseLeaderValue(value string) (string, int64, error) {
+	idx := strings.LastIndex(value, "|")
+	if idx < 0 {
+		return value, 0, fmt.Errorf("invalid leader value: %s", value)
+	}
+
+	token, err := strconv.ParseInt(value[idx+1:], 10, 64)
+	if err != nil {
+		return value[:idx], 0, fmt.Errorf("invalid leader token: %s", value)
+	}
+	return value[:idx], token, nil
 }
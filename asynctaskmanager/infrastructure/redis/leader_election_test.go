@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestLeaderElection_FencingToken(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	a := NewLeaderElection(client, "scheduler-a")
+	b := NewLeaderElection(client, "scheduler-b")
+
+	tokenA, acquired, err := a.TryAcquire(ctx)
+	if err != nil || !acquired {
+		t.Fatalf("a.TryAcquire() = %d, %v, %v, want acquired", tokenA, acquired, err)
+	}
+	if a.Token() != tokenA {
+		t.Errorf("a.Token() = %d, want %d", a.Token(), tokenA)
+	}
+
+	if _, acquired, err := b.TryAcquire(ctx); err != nil || acquired {
+		t.Fatalf("b.TryAcquire() acquired = %v, err = %v, want not acquired", acquired, err)
+	}
+	if err := b.Renew(ctx); err == nil {
+		t.Error("b.Renew() expected error when not leader")
+	}
+	if err := b.Release(ctx); err == nil {
+		t.Error("b.Release() expected error when not leader")
+	}
+	if leader, _ := a.GetLeader(ctx); leader != "scheduler-a" {
+		t.Errorf("GetLeader() = %s, want scheduler-a", leader)
+	}
+
+	// 锁过期（如 GC 停顿）后 b 成为 Leader，token 单调递增
+	mr.FastForward(leaderTTL)
+	tokenB, acquired, err := b.TryAcquire(ctx)
+	if err != nil || !acquired {
+		t.Fatalf("b.TryAcquire() after expiry = %d, %v, %v, want acquired", tokenB, acquired, err)
+	}
+	if tokenB <= tokenA {
+		t.Errorf("token not increasing: a=%d b=%d", tokenA, tokenB)
+	}
+
+	// 过期的 a 无法续约或释放 b 的锁
+	if err := a.Renew(ctx); err == nil {
+		t.Error("a.Renew() expected error after losing leadership")
+	}
+	if a.Token() != 0 {
+		t.Errorf("a.Token() after losing leadership = %d, want 0", a.Token())
+	}
+	if err := a.Release(ctx); err == nil {
+		t.Error("a.Release() expected error after losing leadership")
+	}
+	if ok, _ := b.IsLeader(ctx); !ok {
+		t.Error("b should still be leader")
+	}
+
+	if err := b.Renew(ctx); err != nil {
+		t.Errorf("b.Renew() error = %v", err)
+	}
+	if err := b.Release(ctx); err != nil {
+		t.Errorf("b.Release() error = %v", err)
+	}
+
+	// 同一调度器重新获取也会得到新 token
+	tokenA2, acquired, err := a.TryAcquire(ctx)
+	if err != nil || !acquired || tokenA2 <= tokenB {
+		t.Errorf("a.TryAcquire() again = %d, %v, %v, want token > %d", tokenA2, acquired, err, tokenB)
+	}
+}
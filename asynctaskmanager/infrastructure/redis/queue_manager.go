@@ -4,43 +4,301 @@ import (
 	"bamboo/asynctaskmanager/domain/model"
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 const (
+	// QueueHigh、QueueNormal 优先级 1 与 0 的就绪队列，其余优先级的就绪队列见 QueueNameFor
 	QueueHigh   = "queue:high"
 	QueueNormal = "queue:normal"
+
+	// QueueDelayed 延迟队列（score 为到期时间毫秒时间戳）
+	QueueDelayed = "queue:delayed"
+	// delayedTargetKey 延迟任务到期后投递的目标队列（task_id -> queue）
+	delayedTargetKey = "queue:delayed:target"
+
+	// QueueDead 死信队列，最近进入死信队列的任务ID在最前
+	QueueDead = "queue:dead"
+
+	// readySignalKey 就绪队列的唤醒信号，任务进入就绪队列时写入，最多保留一个
+	readySignalKey = "queue:ready:signal"
+
+	// processingConsumersKey 持有处理中列表的消费者集合
+	processingConsumersKey = "queue:processing:consumers"
+	// consumerTTL 消费者存活标记的过期时间
+	consumerTTL = 30 * time.Second
+
+	// cancelChannel 取消通知频道，消息内容为任务ID
+	cancelChannel = "task:cancel"
+	// cancelMarkTTL 取消标记的过期时间
+	cancelMarkTTL = time.Hour
+
+	// idempotencyKeyTTL 幂等键缓存的过期时间，过期后回源 MySQL 唯一索引
+	idempotencyKeyTTL = 24 * time.Hour
 )
 
+// reserveScript 原子地将至多 ARGV[2] 个任务从源队列移入消费者的处理中列表，并记录来源队列
+// KEYS[1]=处理中列表 KEYS[2]=来源哈希 KEYS[3]=消费者集合 KEYS[4..]=按优先级排列的源队列 ARGV[1]=消费者ID ARGV[2]=数量上限
+var reserveScript = redis.NewScript(`
+local ids = {}
+local limit = tonumber(ARGV[2])
+for i = 4, #KEYS do
+	while #ids < limit do
+		local id = redis.call('LMOVE', KEYS[i], KEYS[1], 'RIGHT', 'LEFT')
+		if not id then
+			break
+		end
+		redis.call('HSET', KEYS[2], id, KEYS[i])
+		table.insert(ids, id)
+	end
+end
+if #ids > 0 then
+	redis.call('SADD', KEYS[3], ARGV[1])
+end
+return ids
+`)
+
+// pushReadyScript 推入就绪队列、登记到非空就绪队列索引并写入唤醒信号
+// KEYS[1]=就绪队列 KEYS[2]=唤醒信号 KEYS[3]=非空就绪队列索引 ARGV[1]=任务ID
+var pushReadyScript = redis.NewScript(`
+redis.call('LPUSH', KEYS[1], ARGV[1])
+redis.call('SADD', KEYS[3], KEYS[1])
+redis.call('LPUSH', KEYS[2], 1)
+redis.call('LTRIM', KEYS[2], 0, 0)
+return 1
+`)
+
+// ackScript 确认任务处理完成，从处理中列表移除，并删除其队首等待时间
+// KEYS[1]=处理中列表 KEYS[2]=来源哈希 KEYS[3]=队首时间哈希 ARGV[1]=任务ID
+var ackScript = redis.NewScript(`
+local n = redis.call('LREM', KEYS[1], 1, ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+return n
+`)
+
+// reapScript 消费者已失效时，将其处理中列表的任务放回来源队列的出队端
+// KEYS[1]=处理中列表 KEYS[2]=来源哈希 KEYS[3]=存活标记 KEYS[4]=消费者集合 KEYS[5]=唤醒信号 KEYS[6]=非空就绪队列索引
+// ARGV[1]=默认队列 ARGV[2]=消费者ID
+// 消费者仍存活时返回 -1
+var reapScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[3]) == 1 then
+	return -1
+end
+local n = 0
+while true do
+	local id = redis.call('LPOP', KEYS[1])
+	if not id then
+		break
+	end
+	local queue = redis.call('HGET', KEYS[2], id)
+	if not queue then
+		queue = ARGV[1]
+	end
+	redis.call('RPUSH', queue, id)
+	redis.call('SADD', KEYS[6], queue)
+	n = n + 1
+end
+redis.call('DEL', KEYS[2])
+redis.call('SREM', KEYS[4], ARGV[2])
+if n > 0 then
+	redis.call('LPUSH', KEYS[5], 1)
+	redis.call('LTRIM', KEYS[5], 0, 0)
+end
+return n
+`)
+
+// promoteDueScript 原子地将到期的延迟任务移入目标就绪队列
+// KEYS[1]=延迟队列 KEYS[2]=目标队列哈希 KEYS[3]=唤醒信号 KEYS[4]=非空就绪队列索引 ARGV[1]=当前时间戳 ARGV[2]=单次上限 ARGV[3]=默认队列
+var promoteDueScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, id in ipairs(ids) do
+	local queue = redis.call('HGET', KEYS[2], id)
+	if not queue then
+		queue = ARGV[3]
+	end
+	redis.call('LPUSH', queue, id)
+	redis.call('SADD', KEYS[4], queue)
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('HDEL', KEYS[2], id)
+end
+if #ids > 0 then
+	redis.call('LPUSH', KEYS[3], 1)
+	redis.call('LTRIM', KEYS[3], 0, 0)
+end
+return #ids
+`)
+
+// drainWorkerScript 取出 Worker 处理中列表与队列中的全部任务，并清理相关键
+// KEYS[1]=Worker 队列 KEYS[2]=处理中列表 KEYS[3]=来源哈希 KEYS[4]=消费者集合 ARGV[1]=Worker ID
+var drainWorkerScript = redis.NewScript(`
+local ids = redis.call('LRANGE', KEYS[2], 0, -1)
+for _, id in ipairs(redis.call('LRANGE', KEYS[1], 0, -1)) do
+	table.insert(ids, id)
+end
+redis.call('DEL', KEYS[1], KEYS[2], KEYS[3])
+redis.call('SREM', KEYS[4], ARGV[1])
+return ids
+`)
+
 // QueueManager 队列管理器
 type QueueManager struct {
 	client *Client
+	policy DequeuePolicy
 }
 
-// NewQueueManager 创建队列管理器
+// NewQueueManager 创建队列管理器，使用默认出队策略
 func NewQueueManager(client *Client) *QueueManager {
-	return &QueueManager{client: client}
+	return &QueueManager{client: client, policy: DefaultDequeuePolicy()}
+}
+
+// QueueNameFor 返回租户在该优先级的就绪队列名，每个租户的每个优先级一个队列
+// 默认租户沿用 priorityQueueName 的队列名，其他租户在其后追加 ":{tenant}"
+func QueueNameFor(tenant string, priority model.TaskPriority) string {
+	name := priorityQueueName(priority)
+	if tenant == "" || tenant == model.DefaultTenant {
+		return name
+	}
+	return name + ":" + tenant
+}
+
+// priorityQueueName 根据优先级返回默认租户的就绪队列名
+// 优先级 0 和 1 沿用只有两级优先级时的队列名，超出取值范围的优先级归入普通队列
+func priorityQueueName(priority model.TaskPriority) string {
+	switch {
+	case priority == model.PriorityHigh:
+		return QueueHigh
+	case priority == model.PriorityNormal, !priority.IsValid():
+		return QueueNormal
+	default:
+		return fmt.Sprintf("queue:p%d", priority)
+	}
+}
+
+// PushTask 按任务的租户与优先级推送到就绪队列，并唤醒阻塞等待的调度器
+func (qm *QueueManager) PushTask(ctx context.Context, task *model.Task) error {
+	return qm.client.RunScript(ctx, pushReadyScript,
+		[]string{QueueNameFor(task.Tenant, task.Priority), readySignalKey, readyActiveKey},
+		task.TaskID,
+	).Err()
+}
+
+// PushDelayedTask 推送任务到延迟队列，到期后由 Leader 移入任务租户与优先级对应的就绪队列
+func (qm *QueueManager) PushDelayedTask(ctx context.Context, task *model.Task, runAt time.Time) error {
+	return qm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, delayedTargetKey, task.TaskID, QueueNameFor(task.Tenant, task.Priority))
+		pipe.ZAdd(ctx, QueueDelayed, redis.Z{
+			Score:  float64(runAt.UnixMilli()),
+			Member: task.TaskID,
+		})
+		return nil
+	})
+}
+
+// PushTasks 批量推送任务，按计划执行时间分别进入就绪队列或延迟队列
+// 所有命令在一个事务流水线中发送，最后唤醒一次阻塞等待的调度器
+func (qm *QueueManager) PushTasks(ctx context.Context, tasks []*model.Task, now time.Time) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ready := make(map[string][]interface{})
+	var delayedTargets []interface{}
+	var delayed []redis.Z
+	for _, task := range tasks {
+		queueName := QueueNameFor(task.Tenant, task.Priority)
+		if task.IsDelayed(now) {
+			delayedTargets = append(delayedTargets, task.TaskID, queueName)
+			delayed = append(delayed, redis.Z{Score: float64(task.ScheduledAt.UnixMilli()), Member: task.TaskID})
+			continue
+		}
+		ready[queueName] = append(ready[queueName], task.TaskID)
+	}
+
+	return qm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// LPUSH 多个值时后面的值更靠近入队端，出队顺序与提交顺序一致
+		for queueName, taskIDs := range ready {
+			pipe.LPush(ctx, queueName, taskIDs...)
+			pipe.SAdd(ctx, readyActiveKey, queueName)
+		}
+		if len(delayed) > 0 {
+			pipe.HSet(ctx, delayedTargetKey, delayedTargets...)
+			pipe.ZAdd(ctx, QueueDelayed, delayed...)
+		}
+		if len(ready) > 0 {
+			pipe.LPush(ctx, readySignalKey, 1)
+			pipe.LTrim(ctx, readySignalKey, 0, 0)
+		}
+		return nil
+	})
 }
 
-// PushTask 推送任务到队列
-func (qm *QueueManager) PushTask(ctx context.Context, taskID string, priority model.TaskPriority) error {
-	queueName := QueueNormal
-	if priority.IsHigh() {
-		queueName = QueueHigh
+// RemoveDelayedTask 从延迟队列移除任务
+func (qm *QueueManager) RemoveDelayedTask(ctx context.Context, taskID string) error {
+	if err := qm.client.ZRem(ctx, QueueDelayed, taskID); err != nil {
+		return err
 	}
+	return qm.client.HDel(ctx, delayedTargetKey, taskID)
+}
+
+// PromoteDueTasks 将到期的延迟任务移入就绪队列，返回移动的任务数
+func (qm *QueueManager) PromoteDueTasks(ctx context.Context, now time.Time, limit int) (int, error) {
+	n, err := qm.client.RunScript(ctx, promoteDueScript,
+		[]string{QueueDelayed, delayedTargetKey, readySignalKey, readyActiveKey},
+		now.UnixMilli(), limit, QueueNormal,
+	).Int()
+	if err != nil {
+		return 0, fmt.Errorf("promote due tasks failed: %w", err)
+	}
+	return n, nil
+}
 
-	return qm.client.LPush(ctx, queueName, taskID)
+// GetDelayedQueueLength 获取延迟队列长度
+func (qm *QueueManager) GetDelayedQueueLength(ctx context.Context) (int64, error) {
+	return qm.client.ZCard(ctx, QueueDelayed)
 }
 
-// PopTask 从队列弹出任务
-func (qm *QueueManager) PopTask(ctx context.Context) (string, error) {
-	// 优先从高优先级队列获取
-	taskID, err := qm.client.RPop(ctx, QueueHigh)
-	if err == nil {
-		return taskID, nil
+// ReserveTask 按出队策略从就绪队列取出一个任务，所有就绪队列为空时返回 redis.Nil
+// 任务被移入消费者的处理中列表，处理完成后需调用 AckTask 确认
+func (qm *QueueManager) ReserveTask(ctx context.Context, consumerID string) (string, error) {
+	ids, err := qm.reserveReady(ctx, consumerID, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 {
+		return "", redis.Nil
 	}
+	return ids[0], nil
+}
+
+// WaitTasks 按出队策略批量取出至多 limit 个就绪任务，处理完成后需逐个调用 AckTask 确认
+// 就绪队列为空时阻塞等待唤醒信号，最长 timeout（按秒计，不足 1 秒按 1 秒）；超时返回空列表
+func (qm *QueueManager) WaitTasks(ctx context.Context, consumerID string, limit int, timeout time.Duration) ([]string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		ids, err := qm.reserveReady(ctx, consumerID, limit)
+		if err != nil || len(ids) > 0 {
+			return ids, err
+		}
 
-	// 从普通优先级队列获取
-	return qm.client.RPop(ctx, QueueNormal)
+		// 信号可能是已被取走任务留下的，被唤醒后队列仍为空时继续等待
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, nil
+		}
+		if remaining < time.Second {
+			remaining = time.Second
+		}
+
+		if _, err := qm.client.BLPop(ctx, remaining, readySignalKey); err != nil {
+			if err == redis.Nil {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("wait ready signal failed: %w", err)
+		}
+	}
 }
 
 // GetQueueLength 获取队列长度
@@ -54,22 +312,187 @@ func (qm *QueueManager) PushToWorkerQueue(ctx context.Context, workerID, taskID
 	return qm.client.LPush(ctx, key, taskID)
 }
 
-// PopFromWorkerQueue 从 Worker 队列弹出任务
-func (qm *QueueManager) PopFromWorkerQueue(ctx context.Context, workerID string) (string, error) {
+// ReserveFromWorkerQueue 从 Worker 队列取出任务，处理完成后需以 workerID 调用 AckTask 确认
+func (qm *QueueManager) ReserveFromWorkerQueue(ctx context.Context, workerID string) (string, error) {
 	key := fmt.Sprintf("worker:%s:queue", workerID)
-	return qm.client.RPop(ctx, key)
+	return qm.reserve(ctx, workerID, key)
 }
 
-// SetCancelMark 设置取消标记
+// WaitWorkerTask 阻塞地从 Worker 队列取出任务，最长等待 timeout（按秒计），超时返回空字符串
+// 处理完成后需以 workerID 调用 AckTask 确认；记录来源失败时仍返回已取出的任务ID
+func (qm *QueueManager) WaitWorkerTask(ctx context.Context, workerID string, timeout time.Duration) (string, error) {
+	key := fmt.Sprintf("worker:%s:queue", workerID)
+	taskID, err := qm.client.BLMove(ctx, key, processingKey(workerID), "RIGHT", "LEFT", timeout)
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("wait worker queue failed: %w", err)
+	}
+
+	// 记录来源队列，消费者失效时放回 Worker 队列
+	if err := qm.client.HSet(ctx, processingOriginKey(workerID), taskID, key); err != nil {
+		return taskID, fmt.Errorf("record task origin failed: %w", err)
+	}
+	if err := qm.client.SAdd(ctx, processingConsumersKey, workerID); err != nil {
+		return taskID, fmt.Errorf("register consumer failed: %w", err)
+	}
+	return taskID, nil
+}
+
+// DrainWorkerQueue 取出 Worker 队列及其处理中列表的全部任务ID，用于 Worker 失效后的故障转移
+func (qm *QueueManager) DrainWorkerQueue(ctx context.Context, workerID string) ([]string, error) {
+	ids, err := qm.client.RunScript(ctx, drainWorkerScript,
+		[]string{
+			fmt.Sprintf("worker:%s:queue", workerID),
+			processingKey(workerID),
+			processingOriginKey(workerID),
+			processingConsumersKey,
+		},
+		workerID,
+	).StringSlice()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("drain worker queue failed: %w", err)
+	}
+	return ids, nil
+}
+
+// reserve 按顺序尝试从源队列取出一个任务，所有队列为空时返回 redis.Nil
+func (qm *QueueManager) reserve(ctx context.Context, consumerID string, sources ...string) (string, error) {
+	ids, err := qm.reserveN(ctx, consumerID, 1, sources...)
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 {
+		return "", redis.Nil
+	}
+	return ids[0], nil
+}
+
+// reserveN 按顺序从源队列取出至多 limit 个任务
+func (qm *QueueManager) reserveN(ctx context.Context, consumerID string, limit int, sources ...string) ([]string, error) {
+	keys := append([]string{
+		processingKey(consumerID),
+		processingOriginKey(consumerID),
+		processingConsumersKey,
+	}, sources...)
+
+	ids, err := qm.client.RunScript(ctx, reserveScript, keys, consumerID, limit).StringSlice()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("reserve tasks failed: %w", err)
+	}
+	return ids, nil
+}
+
+// AckTask 确认任务已处理完成，从消费者的处理中列表移除
+func (qm *QueueManager) AckTask(ctx context.Context, consumerID, taskID string) error {
+	err := qm.client.RunScript(ctx, ackScript,
+		[]string{processingKey(consumerID), processingOriginKey(consumerID), readyHeadSinceKey},
+		taskID,
+	).Err()
+	if err != nil {
+		return fmt.Errorf("ack task %s failed: %w", taskID, err)
+	}
+	return nil
+}
+
+// ReturnTasks 将消费者处理中的任务放回各自就绪队列的出队端，tasks 须按取出顺序排列
+// 放回后任务保持原有的先后顺序，队首等待时间保留，老化不会重新计时；不写入唤醒信号
+func (qm *QueueManager) ReturnTasks(ctx context.Context, consumerID string, tasks []*model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	err := qm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// 逆序 RPUSH，最先取出的任务回到最靠近出队端的位置
+		for i := len(tasks) - 1; i >= 0; i-- {
+			taskID := tasks[i].TaskID
+			pipe.LRem(ctx, processingKey(consumerID), 1, taskID)
+			pipe.HDel(ctx, processingOriginKey(consumerID), taskID)
+			queueName := QueueNameFor(tasks[i].Tenant, tasks[i].Priority)
+			pipe.RPush(ctx, queueName, taskID)
+			pipe.SAdd(ctx, readyActiveKey, queueName)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("return tasks failed: %w", err)
+	}
+	return nil
+}
+
+// KeepAlive 刷新消费者存活标记，超过 consumerTTL 未刷新的消费者会被 ReapDeadConsumers 回收
+func (qm *QueueManager) KeepAlive(ctx context.Context, consumerID string) error {
+	return qm.client.Set(ctx, consumerAliveKey(consumerID), "1", consumerTTL)
+}
+
+// ReapDeadConsumers 将已失效消费者处理中列表里未确认的任务放回来源队列，返回放回的任务数
+func (qm *QueueManager) ReapDeadConsumers(ctx context.Context) (int, error) {
+	consumers, err := qm.client.SMembers(ctx, processingConsumersKey)
+	if err != nil {
+		return 0, fmt.Errorf("list consumers failed: %w", err)
+	}
+
+	total := 0
+	for _, consumerID := range consumers {
+		n, err := qm.client.RunScript(ctx, reapScript,
+			[]string{
+				processingKey(consumerID),
+				processingOriginKey(consumerID),
+				consumerAliveKey(consumerID),
+				processingConsumersKey,
+				readySignalKey,
+				readyActiveKey,
+			},
+			QueueNormal, consumerID,
+		).Int()
+		if err != nil {
+			return total, fmt.Errorf("reap consumer %s failed: %w", consumerID, err)
+		}
+		if n > 0 {
+			total += n
+		}
+	}
+	return total, nil
+}
+
+// GetProcessingLength 获取消费者处理中列表长度
+func (qm *QueueManager) GetProcessingLength(ctx context.Context, consumerID string) (int64, error) {
+	return qm.client.LLen(ctx, processingKey(consumerID))
+}
+
+// processingKey 消费者处理中列表
+func processingKey(consumerID string) string {
+	return fmt.Sprintf("queue:processing:%s", consumerID)
+}
+
+// processingOriginKey 消费者处理中任务的来源队列（task_id -> queue）
+func processingOriginKey(consumerID string) string {
+	return fmt.Sprintf("queue:processing:%s:origin", consumerID)
+}
+
+// consumerAliveKey 消费者存活标记
+func consumerAliveKey(consumerID string) string {
+	return fmt.Sprintf("queue:consumer:%s", consumerID)
+}
+
+// SetCancelMark 设置取消标记，并通知正在执行该任务的 Worker
+// 标记用于兜底：通知丢失或任务尚未开始执行时，Worker 通过检查标记发现取消
 func (qm *QueueManager) SetCancelMark(ctx context.Context, taskID string) error {
-	key := fmt.Sprintf("task:cancel:%s", taskID)
-	return qm.client.Set(ctx, key, "1", 3600)
+	if err := qm.client.Set(ctx, cancelMarkKey(taskID), "1", cancelMarkTTL); err != nil {
+		return err
+	}
+	return qm.client.Publish(ctx, cancelChannel, taskID)
+}
+
+// SubscribeCancel 订阅取消通知
+func (qm *QueueManager) SubscribeCancel(ctx context.Context) *redis.PubSub {
+	return qm.client.Subscribe(ctx, cancelChannel)
 }
 
 // CheckCancelMark 检查取消标记
 func (qm *QueueManager) CheckCancelMark(ctx context.Context, taskID string) (bool, error) {
-	key := fmt.Sprintf("task:cancel:%s", taskID)
-	exists, err := qm.client.Exists(ctx, key)
+	exists, err := qm.client.Exists(ctx, cancelMarkKey(taskID))
 	if err != nil {
 		return false, err
 	}
@@ -78,6 +501,56 @@ func (qm *QueueManager) CheckCancelMark(ctx context.Context, taskID string) (boo
 
 // RemoveCancelMark 移除取消标记
 func (qm *QueueManager) RemoveCancelMark(ctx context.Context, taskID string) error {
-	key := fmt.Sprintf("task:cancel:%s", taskID)
-	return qm.client.Del(ctx, key)
+	return qm.client.Del(ctx, cancelMarkKey(taskID))
+}
+
+// pushDeadLetterScript 将任务ID放到死信队列最前，已存在时先移除，保证每个任务只出现一次
+// KEYS[1]=死信队列 ARGV[1]=任务ID
+var pushDeadLetterScript = redis.NewScript(`
+redis.call('LREM', KEYS[1], 0, ARGV[1])
+redis.call('LPUSH', KEYS[1], ARGV[1])
+return 1
+`)
+
+// PushDeadLetter 将重试耗尽的任务放入死信队列
+func (qm *QueueManager) PushDeadLetter(ctx context.Context, taskID string) error {
+	if err := qm.client.RunScript(ctx, pushDeadLetterScript, []string{QueueDead}, taskID).Err(); err != nil {
+		return fmt.Errorf("push dead letter failed: %w", err)
+	}
+	return nil
+}
+
+// RemoveDeadLetter 从死信队列移除任务
+func (qm *QueueManager) RemoveDeadLetter(ctx context.Context, taskID string) error {
+	return qm.client.LRem(ctx, QueueDead, 0, taskID)
+}
+
+// GetIdempotentTask 查询幂等键对应的任务ID，未缓存时返回空字符串
+func (qm *QueueManager) GetIdempotentTask(ctx context.Context, taskType, idempotencyKey string) (string, error) {
+	taskID, err := qm.client.Get(ctx, idempotencyCacheKey(taskType, idempotencyKey))
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get idempotency key failed: %w", err)
+	}
+	return taskID, nil
+}
+
+// SetIdempotentTask 缓存幂等键对应的任务ID
+func (qm *QueueManager) SetIdempotentTask(ctx context.Context, taskType, idempotencyKey, taskID string) error {
+	if err := qm.client.Set(ctx, idempotencyCacheKey(taskType, idempotencyKey), taskID, idempotencyKeyTTL); err != nil {
+		return fmt.Errorf("set idempotency key failed: %w", err)
+	}
+	return nil
+}
+
+// idempotencyCacheKey 任务类型下幂等键的缓存
+func idempotencyCacheKey(taskType, idempotencyKey string) string {
+	return fmt.Sprintf("task:idempotency:%s:%s", taskType, idempotencyKey)
+}
+
+// cancelMarkKey 任务取消标记
+func cancelMarkKey(taskID string) string {
+	return fmt.Sprintf("task:cancel:%s", taskID)
 }
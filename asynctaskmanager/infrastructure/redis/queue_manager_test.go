@@ -0,0 +1,334 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+func newTestQueueManager(t *testing.T) (*QueueManager, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewQueueManager(client), mr
+}
+
+func TestQueueManager_ReserveTask(t *testing.T) {
+	qm, mr := newTestQueueManager(t)
+	ctx := context.Background()
+
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "normal-1", Priority: model.PriorityNormal})
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "high-1", Priority: model.PriorityHigh})
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "normal-2", Priority: model.PriorityNormal})
+
+	// 默认权重下高优先级先出队，同优先级先进先出
+	for _, want := range []string{"high-1", "normal-1", "normal-2"} {
+		got, err := qm.ReserveTask(ctx, "scheduler-1")
+		if err != nil {
+			t.Fatalf("ReserveTask() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("ReserveTask() = %s, want %s", got, want)
+		}
+	}
+
+	if _, err := qm.ReserveTask(ctx, "scheduler-1"); err == nil {
+		t.Error("ReserveTask() expected error on empty queues")
+	}
+
+	if n, _ := qm.GetProcessingLength(ctx, "scheduler-1"); n != 3 {
+		t.Errorf("processing length = %d, want 3", n)
+	}
+	if mr.Exists(QueueHigh) || mr.Exists(QueueNormal) {
+		t.Error("ready queues should be empty after reserving all tasks")
+	}
+}
+
+func TestQueueManager_AckTask(t *testing.T) {
+	qm, _ := newTestQueueManager(t)
+	ctx := context.Background()
+
+	_ = qm.PushToWorkerQueue(ctx, "worker-1", "task-1")
+	_ = qm.PushToWorkerQueue(ctx, "worker-1", "task-2")
+
+	taskID, err := qm.ReserveFromWorkerQueue(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("ReserveFromWorkerQueue() error = %v", err)
+	}
+	if taskID != "task-1" {
+		t.Fatalf("ReserveFromWorkerQueue() = %s, want task-1", taskID)
+	}
+
+	if err := qm.AckTask(ctx, "worker-1", taskID); err != nil {
+		t.Fatalf("AckTask() error = %v", err)
+	}
+	if n, _ := qm.GetProcessingLength(ctx, "worker-1"); n != 0 {
+		t.Errorf("processing length after ack = %d, want 0", n)
+	}
+	if n, _ := qm.GetQueueLength(ctx, "worker:worker-1:queue"); n != 1 {
+		t.Errorf("worker queue length = %d, want 1", n)
+	}
+}
+
+func TestQueueManager_ReturnTasks(t *testing.T) {
+	qm, mr := newTestQueueManager(t)
+	ctx := context.Background()
+
+	tasks := []*model.Task{
+		{TaskID: "task-1", Priority: model.PriorityNormal},
+		{TaskID: "task-2", Priority: model.PriorityNormal},
+		{TaskID: "task-3", Priority: model.PriorityNormal},
+	}
+	for _, task := range tasks {
+		_ = qm.PushTask(ctx, task)
+	}
+
+	ids, err := qm.WaitTasks(ctx, "scheduler-1", 2, time.Second)
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("WaitTasks() = %v, %v", ids, err)
+	}
+	since := mr.HGet(readyHeadSinceKey, "task-1")
+	if since == "" {
+		t.Fatal("head since of task-1 should be recorded")
+	}
+
+	if err := qm.ReturnTasks(ctx, "scheduler-1", tasks[:2]); err != nil {
+		t.Fatalf("ReturnTasks() error = %v", err)
+	}
+
+	// 放回队首后保持原顺序与队首等待时间
+	if n, _ := qm.GetProcessingLength(ctx, "scheduler-1"); n != 0 {
+		t.Errorf("processing length = %d, want 0", n)
+	}
+	if got := mr.HGet(readyHeadSinceKey, "task-1"); got != since {
+		t.Errorf("head since of task-1 = %s, want %s", got, since)
+	}
+	for _, want := range []string{"task-1", "task-2", "task-3"} {
+		got, err := qm.ReserveTask(ctx, "scheduler-1")
+		if err != nil {
+			t.Fatalf("ReserveTask() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("ReserveTask() = %s, want %s", got, want)
+		}
+	}
+}
+
+func TestQueueManager_ReapDeadConsumers(t *testing.T) {
+	qm, mr := newTestQueueManager(t)
+	ctx := context.Background()
+
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "high-1", Priority: model.PriorityHigh})
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "normal-1", Priority: model.PriorityNormal})
+	_ = qm.PushToWorkerQueue(ctx, "dead-worker", "assigned-1")
+	_ = qm.PushToWorkerQueue(ctx, "live-worker", "assigned-2")
+
+	_ = qm.KeepAlive(ctx, "dead-scheduler")
+	_ = qm.KeepAlive(ctx, "dead-worker")
+	_ = qm.KeepAlive(ctx, "live-worker")
+
+	_, _ = qm.ReserveTask(ctx, "dead-scheduler")
+	_, _ = qm.ReserveTask(ctx, "dead-scheduler")
+	_, _ = qm.ReserveFromWorkerQueue(ctx, "dead-worker")
+	_, _ = qm.ReserveFromWorkerQueue(ctx, "live-worker")
+
+	// 存活期内不回收
+	if n, err := qm.ReapDeadConsumers(ctx); err != nil || n != 0 {
+		t.Fatalf("ReapDeadConsumers() = %d, %v, want 0, nil", n, err)
+	}
+
+	mr.FastForward(consumerTTL + time.Second)
+	_ = qm.KeepAlive(ctx, "live-worker")
+
+	n, err := qm.ReapDeadConsumers(ctx)
+	if err != nil {
+		t.Fatalf("ReapDeadConsumers() error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("ReapDeadConsumers() = %d, want 3", n)
+	}
+
+	// 任务回到各自的来源队列
+	if got, err := qm.ReserveTask(ctx, "scheduler-2"); err != nil || got != "high-1" {
+		t.Errorf("ReserveTask() = %s, %v, want high-1", got, err)
+	}
+	if got, err := qm.ReserveTask(ctx, "scheduler-2"); err != nil || got != "normal-1" {
+		t.Errorf("ReserveTask() = %s, %v, want normal-1", got, err)
+	}
+	if got, err := qm.ReserveFromWorkerQueue(ctx, "dead-worker"); err != nil || got != "assigned-1" {
+		t.Errorf("ReserveFromWorkerQueue() = %s, %v, want assigned-1", got, err)
+	}
+
+	// 存活的消费者不受影响
+	if n, _ := qm.GetProcessingLength(ctx, "live-worker"); n != 1 {
+		t.Errorf("live worker processing length = %d, want 1", n)
+	}
+}
+
+func TestQueueManager_PromoteDueTasks(t *testing.T) {
+	qm, _ := newTestQueueManager(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	_ = qm.PushDelayedTask(ctx, &model.Task{TaskID: "due-high", Priority: model.PriorityHigh}, now.Add(-time.Second))
+	_ = qm.PushDelayedTask(ctx, &model.Task{TaskID: "due-normal", Priority: model.PriorityNormal}, now)
+	_ = qm.PushDelayedTask(ctx, &model.Task{TaskID: "future", Priority: model.PriorityNormal}, now.Add(time.Hour))
+
+	n, err := qm.PromoteDueTasks(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("PromoteDueTasks() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("PromoteDueTasks() = %d, want 2", n)
+	}
+	if l, _ := qm.GetQueueLength(ctx, QueueHigh); l != 1 {
+		t.Errorf("high queue length = %d, want 1", l)
+	}
+	if l, _ := qm.GetDelayedQueueLength(ctx); l != 1 {
+		t.Errorf("delayed queue length = %d, want 1", l)
+	}
+}
+
+func TestQueueManager_PushTasks(t *testing.T) {
+	qm, _ := newTestQueueManager(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	tasks := []*model.Task{
+		{TaskID: "normal-1", Priority: model.PriorityNormal, ScheduledAt: now},
+		{TaskID: "high-1", Priority: model.PriorityHigh, ScheduledAt: now},
+		{TaskID: "later", Priority: model.PriorityHigh, ScheduledAt: now.Add(time.Hour)},
+		{TaskID: "normal-2", Priority: model.PriorityNormal, ScheduledAt: now.Add(-time.Second)},
+	}
+	if err := qm.PushTasks(ctx, tasks, now); err != nil {
+		t.Fatalf("PushTasks() error = %v", err)
+	}
+
+	// 与逐个推送的出队顺序一致
+	for _, want := range []string{"high-1", "normal-1", "normal-2"} {
+		got, err := qm.ReserveTask(ctx, "scheduler-1")
+		if err != nil {
+			t.Fatalf("ReserveTask() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("ReserveTask() = %s, want %s", got, want)
+		}
+	}
+
+	if n, _ := qm.PromoteDueTasks(ctx, now.Add(2*time.Hour), 10); n != 1 {
+		t.Errorf("PromoteDueTasks() = %d, want 1", n)
+	}
+	if l, _ := qm.GetQueueLength(ctx, QueueHigh); l != 1 {
+		t.Errorf("high queue length = %d, want 1", l)
+	}
+}
+
+func TestQueueManager_DrainWorkerQueue(t *testing.T) {
+	qm, mr := newTestQueueManager(t)
+	ctx := context.Background()
+
+	_ = qm.PushToWorkerQueue(ctx, "worker-1", "task-1")
+	_ = qm.PushToWorkerQueue(ctx, "worker-1", "task-2")
+	_ = qm.PushToWorkerQueue(ctx, "worker-1", "task-3")
+	_, _ = qm.ReserveFromWorkerQueue(ctx, "worker-1")
+
+	ids, err := qm.DrainWorkerQueue(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("DrainWorkerQueue() error = %v", err)
+	}
+	if len(ids) != 3 {
+		t.Errorf("DrainWorkerQueue() = %v, want 3 tasks", ids)
+	}
+	if mr.Exists("worker:worker-1:queue") || mr.Exists(processingKey("worker-1")) {
+		t.Error("worker queue and processing list should be removed")
+	}
+
+	ids, err = qm.DrainWorkerQueue(ctx, "worker-1")
+	if err != nil || len(ids) != 0 {
+		t.Errorf("DrainWorkerQueue() on empty queue = %v, %v", ids, err)
+	}
+}
+
+func TestQueueManager_WaitTasks(t *testing.T) {
+	qm, _ := newTestQueueManager(t)
+	ctx := context.Background()
+
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "normal-1", Priority: model.PriorityNormal})
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "high-1", Priority: model.PriorityHigh})
+	_ = qm.PushTask(ctx, &model.Task{TaskID: "normal-2", Priority: model.PriorityNormal})
+
+	// 批量取出，高优先级在前，不超过上限
+	ids, err := qm.WaitTasks(ctx, "scheduler-1", 2, time.Second)
+	if err != nil {
+		t.Fatalf("WaitTasks() error = %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "high-1" || ids[1] != "normal-1" {
+		t.Errorf("WaitTasks() = %v, want [high-1 normal-1]", ids)
+	}
+	ids, _ = qm.WaitTasks(ctx, "scheduler-1", 10, time.Second)
+	if len(ids) != 1 || ids[0] != "normal-2" {
+		t.Errorf("WaitTasks() = %v, want [normal-2]", ids)
+	}
+	if n, _ := qm.GetProcessingLength(ctx, "scheduler-1"); n != 3 {
+		t.Errorf("processing length = %d, want 3", n)
+	}
+
+	// 队列为空时阻塞，新任务到达后立即返回
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = qm.PushTask(context.Background(), &model.Task{TaskID: "high-2", Priority: model.PriorityHigh})
+	}()
+	start := time.Now()
+	ids, err = qm.WaitTasks(ctx, "scheduler-1", 10, 5*time.Second)
+	if err != nil {
+		t.Fatalf("WaitTasks() error = %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "high-2" {
+		t.Errorf("WaitTasks() = %v, want [high-2]", ids)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("WaitTasks() took %v, want wake-up on push", elapsed)
+	}
+
+	// 超时返回空列表
+	ids, err = qm.WaitTasks(ctx, "scheduler-1", 10, time.Second)
+	if err != nil || len(ids) != 0 {
+		t.Errorf("WaitTasks() on empty queues = %v, %v, want empty", ids, err)
+	}
+}
+
+func TestQueueManager_WaitWorkerTask(t *testing.T) {
+	qm, mr := newTestQueueManager(t)
+	ctx := context.Background()
+
+	_ = qm.PushToWorkerQueue(ctx, "worker-1", "task-1")
+
+	taskID, err := qm.WaitWorkerTask(ctx, "worker-1", time.Second)
+	if err != nil || taskID != "task-1" {
+		t.Fatalf("WaitWorkerTask() = %s, %v, want task-1", taskID, err)
+	}
+	if origin := mr.HGet(processingOriginKey("worker-1"), "task-1"); origin != "worker:worker-1:queue" {
+		t.Errorf("task origin = %q, want worker:worker-1:queue", origin)
+	}
+
+	taskID, err = qm.WaitWorkerTask(ctx, "worker-1", time.Second)
+	if err != nil || taskID != "" {
+		t.Errorf("WaitWorkerTask() on empty queue = %q, %v, want empty", taskID, err)
+	}
+
+	// 失效后任务回到 Worker 队列
+	mr.FastForward(consumerTTL + time.Second)
+	if n, err := qm.ReapDeadConsumers(ctx); err != nil || n != 1 {
+		t.Fatalf("ReapDeadConsumers() = %d, %v, want 1", n, err)
+	}
+	if n, _ := qm.GetQueueLength(ctx, "worker:worker-1:queue"); n != 1 {
+		t.Errorf("worker queue length = %d, want 1", n)
+	}
+}
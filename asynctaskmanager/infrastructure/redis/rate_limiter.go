@@ -0,0 +1,120 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// reservationTTLMargin 预约记录在到期时间之后的保留时长，覆盖延迟队列的移动间隔
+const reservationTTLMargin = time.Minute
+
+// reserveTokenScript 从令牌桶取一个令牌，令牌不足时预约未来的令牌
+// 桶内令牌可以为负数，表示已被预约；返回需要等待的毫秒数，0 表示立即放行
+// 任务持有预约记录时直接放行并删除记录
+// KEYS[1]=令牌桶 KEYS[2]=预约记录 ARGV[1]=速率（个/秒） ARGV[2]=容量 ARGV[3]=当前毫秒时间戳 ARGV[4]=预约保留毫秒数
+var reserveTokenScript = redis.NewScript(`
+if redis.call('DEL', KEYS[2]) == 1 then
+	return 0
+end
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local tokens = tonumber(redis.call('HGET', KEYS[1], 'tokens'))
+local ts = tonumber(redis.call('HGET', KEYS[1], 'ts'))
+if not tokens or not ts then
+	tokens = burst
+	ts = now
+end
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+	ts = now
+end
+tokens = tokens - 1
+local wait = 0
+if tokens < 0 then
+	wait = math.ceil(-tokens * 1000 / rate)
+	redis.call('SET', KEYS[2], 1, 'PX', wait + tonumber(ARGV[4]))
+end
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + wait + 1000)
+return wait
+`)
+
+// refundTokenScript 向令牌桶归还一个令牌，不超过容量；桶不存在时忽略
+// KEYS[1]=令牌桶 ARGV[1]=容量
+var refundTokenScript = redis.NewScript(`
+local tokens = tonumber(redis.call('HGET', KEYS[1], 'tokens'))
+if not tokens then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'tokens', math.min(tonumber(ARGV[1]), tokens + 1))
+return 1
+`)
+
+// RateLimiter 基于令牌桶的分发限流，按作用域（如任务类型）独立计数
+// 由 Leader 在分发时调用，令牌不足的任务预约未来的令牌并按预约时间延迟分发
+type RateLimiter struct {
+	client *Client
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter(client *Client) *RateLimiter {
+	return &RateLimiter{client: client}
+}
+
+// Reserve 为任务从 scope 的令牌桶取一个令牌，返回分发前需要等待的时间
+// rate 小于等于 0 表示不限制，burst 小于 1 时按 1 处理
+// 返回值大于 0 时任务已持有预约，等待到期后再次调用会直接放行
+func (rl *RateLimiter) Reserve(ctx context.Context, scope, taskID string, rate float64, burst int) (time.Duration, error) {
+	if rate <= 0 {
+		return 0, nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	wait, err := rl.client.RunScript(ctx, reserveTokenScript,
+		[]string{rateBucketKey(scope), rateReservationKey(scope, taskID)},
+		rate, burst, time.Now().UnixMilli(), reservationTTLMargin.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("reserve rate limit token failed: %w", err)
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+// Refund 向 scope 的令牌桶归还一个已立即放行的令牌，用于任务取得令牌后又被其他限流拒绝的情况
+// burst 须与 Reserve 时一致，小于 1 时按 1 处理
+func (rl *RateLimiter) Refund(ctx context.Context, scope string, burst int) error {
+	if burst < 1 {
+		burst = 1
+	}
+
+	if err := rl.client.RunScript(ctx, refundTokenScript, []string{rateBucketKey(scope)}, burst).Err(); err != nil {
+		return fmt.Errorf("refund rate limit token failed: %w", err)
+	}
+	return nil
+}
+
+// rateBucketKey 作用域的令牌桶
+func rateBucketKey(scope string) string {
+	return fmt.Sprintf("ratelimit:%s", scope)
+}
+
+// rateReservationKey 任务在作用域内的令牌预约
+func rateReservationKey(scope, taskID string) string {
+	return fmt.Sprintf("ratelimit:%s:reserved:%s", scope, taskID)
+}
+
+// TaskTypeRateScope 任务类型的限流作用域
+func TaskTypeRateScope(taskType string) string {
+	return "task:" + taskType
+}
+
+// TenantRateScope 租户的限流作用域
+func TenantRateScope(tenant string) string {
+	return "tenant:" + tenant
+}
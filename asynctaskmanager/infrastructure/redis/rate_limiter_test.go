@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRateLimiter(t *testing.T) (*RateLimiter, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewRateLimiter(client), mr
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      float64
+		burst     int
+		requests  int
+		wantWaits []time.Duration
+	}{
+		{
+			name:      "unlimited",
+			rate:      0,
+			burst:     0,
+			requests:  3,
+			wantWaits: []time.Duration{0, 0, 0},
+		},
+		{
+			name:      "burst then reserve future tokens",
+			rate:      2,
+			burst:     2,
+			requests:  4,
+			wantWaits: []time.Duration{0, 0, 500 * time.Millisecond, time.Second},
+		},
+		{
+			name:      "burst below one treated as one",
+			rate:      1,
+			burst:     0,
+			requests:  2,
+			wantWaits: []time.Duration{0, time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl, _ := newTestRateLimiter(t)
+			ctx := context.Background()
+
+			for i := 0; i < tt.requests; i++ {
+				wait, err := rl.Reserve(ctx, "task:http_request", string(rune('a'+i)), tt.rate, tt.burst)
+				if err != nil {
+					t.Fatalf("Reserve() error = %v", err)
+				}
+				// 两次调用之间的耗时会补充少量令牌
+				if diff := tt.wantWaits[i] - wait; diff < 0 || diff > 50*time.Millisecond {
+					t.Errorf("request %d wait = %v, want %v", i, wait, tt.wantWaits[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiter_ReservationAdmitsOnce(t *testing.T) {
+	rl, _ := newTestRateLimiter(t)
+	ctx := context.Background()
+
+	if wait, _ := rl.Reserve(ctx, "task:report", "task-1", 1, 1); wait != 0 {
+		t.Fatalf("first Reserve() wait = %v, want 0", wait)
+	}
+	wait, err := rl.Reserve(ctx, "task:report", "task-2", 1, 1)
+	if err != nil || wait <= 0 {
+		t.Fatalf("Reserve() = %v, %v, want positive wait", wait, err)
+	}
+
+	// 持有预约的任务再次调用直接放行，且只放行一次
+	if wait, _ := rl.Reserve(ctx, "task:report", "task-2", 1, 1); wait != 0 {
+		t.Errorf("Reserve() with reservation wait = %v, want 0", wait)
+	}
+	if wait, _ := rl.Reserve(ctx, "task:report", "task-2", 1, 1); wait <= 0 {
+		t.Errorf("Reserve() after reservation used wait = %v, want positive", wait)
+	}
+}
+
+func TestRateLimiter_Refund(t *testing.T) {
+	rl, mr := newTestRateLimiter(t)
+	ctx := context.Background()
+
+	// 令牌桶不存在时忽略
+	if err := rl.Refund(ctx, "tenant:acme", 1); err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+	if mr.Exists(rateBucketKey("tenant:acme")) {
+		t.Error("Refund() should not create a bucket")
+	}
+
+	if wait, _ := rl.Reserve(ctx, "tenant:acme", "task-1", 1, 1); wait != 0 {
+		t.Fatalf("first Reserve() wait = %v, want 0", wait)
+	}
+
+	// 归还后下一个任务立即放行
+	if err := rl.Refund(ctx, "tenant:acme", 1); err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+	if wait, _ := rl.Reserve(ctx, "tenant:acme", "task-2", 1, 1); wait != 0 {
+		t.Errorf("Reserve() after refund wait = %v, want 0", wait)
+	}
+
+	// 归还不超过容量
+	_ = rl.Refund(ctx, "tenant:acme", 1)
+	_ = rl.Refund(ctx, "tenant:acme", 1)
+	if wait, _ := rl.Reserve(ctx, "tenant:acme", "task-3", 1, 1); wait != 0 {
+		t.Errorf("Reserve() wait = %v, want 0", wait)
+	}
+	if wait, _ := rl.Reserve(ctx, "tenant:acme", "task-4", 1, 1); wait <= 0 {
+		t.Errorf("Reserve() beyond burst wait = %v, want positive", wait)
+	}
+}
@@ -84,6 +84,21 @@ func (c *Client) RPop(ctx context.Context, key string) (string, error) {
 	return c.client.RPop(ctx, key).Result()
 }
 
+// BLPop 阻塞式左侧弹出列表，超时返回 redis.Nil
+func (c *Client) BLPop(ctx context.Context, timeout time.Duration, keys ...string) ([]string, error) {
+	return c.client.BLPop(ctx, timeout, keys...).Result()
+}
+
+// BLMove 阻塞式在列表间移动元素，超时返回 redis.Nil
+func (c *Client) BLMove(ctx context.Context, source, destination, srcpos, destpos string, timeout time.Duration) (string, error) {
+	return c.client.BLMove(ctx, source, destination, srcpos, destpos, timeout).Result()
+}
+
+// LRem 从列表中移除与 value 相等的元素，count 为 0 时移除全部
+func (c *Client) LRem(ctx context.Context, key string, count int64, value interface{}) error {
+	return c.client.LRem(ctx, key, count, value).Err()
+}
+
 // LLen 获取列表长度
 func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
 	return c.client.LLen(ctx, key).Result()
@@ -143,3 +158,24 @@ func (c *Client) Publish(ctx context.Context, channel string, message interface{
 func (c *Client) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
 	return c.client.Subscribe(ctx, channels...)
 }
+
+// ZCard 获取有序集合成员数
+func (c *Client) ZCard(ctx context.Context, key string) (int64, error) {
+	return c.client.ZCard(ctx, key).Result()
+}
+
+// HDel 删除哈希字段
+func (c *Client) HDel(ctx context.Context, key string, fields ...string) error {
+	return c.client.HDel(ctx, key, fields...).Err()
+}
+
+// RunScript 执行 Lua 脚本（优先 EVALSHA，未缓存时回退 EVAL）
+func (c *Client) RunScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) *redis.Cmd {
+	return script.Run(ctx, c.client, keys, args...)
+}
+
+// TxPipelined 在 MULTI/EXEC 事务中批量发送命令，减少网络往返
+func (c *Client) TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) error {
+	_, err := c.client.TxPipelined(ctx, fn)
+	return err
+}
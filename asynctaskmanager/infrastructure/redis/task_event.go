@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+)
+
+// TaskEventChannel 任务状态变更事件频道，消息为 JSON 编码的状态变更日志
+const TaskEventChannel = "task:events"
+
+// TaskEventBus 任务状态变更事件总线，基于 Redis pub/sub，订阅方断线期间的事件会丢失
+type TaskEventBus struct {
+	client *Client
+}
+
+// NewTaskEventBus 创建任务事件总线
+func NewTaskEventBus(client *Client) *TaskEventBus {
+	return &TaskEventBus{client: client}
+}
+
+// Publish 发布状态变更日志，多条日志通过流水线一次发送
+func (b *TaskEventBus) Publish(ctx context.Context, logs ...*model.TaskLog) error {
+	messages := make([][]byte, 0, len(logs))
+	for _, log := range logs {
+		message, err := json.Marshal(log)
+		if err != nil {
+			return fmt.Errorf("marshal task event failed: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	switch len(messages) {
+	case 0:
+		return nil
+	case 1:
+		return b.client.Publish(ctx, TaskEventChannel, messages[0])
+	}
+
+	_, err := b.client.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, message := range messages {
+			pipe.Publish(ctx, TaskEventChannel, message)
+		}
+		return nil
+	})
+	return err
+}
+
+// Subscribe 订阅任务状态变更事件，使用 DecodeTaskEvent 解析消息
+func (b *TaskEventBus) Subscribe(ctx context.Context) *redis.PubSub {
+	return b.client.Subscribe(ctx, TaskEventChannel)
+}
+
+// DecodeTaskEvent 解析任务事件消息
+func DecodeTaskEvent(payload string) (*model.TaskLog, error) {
+	var log model.TaskLog
+	if err := json.Unmarshal([]byte(payload), &log); err != nil {
+		return nil, fmt.Errorf("unmarshal task event failed: %w", err)
+	}
+	return &log, nil
+}
+
+// eventTaskLogRepository 写入日志后发布状态变更事件的 TaskLogRepository 装饰器
+type eventTaskLogRepository struct {
+	repository.TaskLogRepository
+	eventBus *TaskEventBus
+}
+
+// NewEventTaskLogRepository 包装任务日志仓储，记录状态变更的日志写入成功后发布到 TaskEventChannel
+// 发布失败不影响日志写入
+func NewEventTaskLogRepository(repo repository.TaskLogRepository, eventBus *TaskEventBus) repository.TaskLogRepository {
+	return &eventTaskLogRepository{TaskLogRepository: repo, eventBus: eventBus}
+}
+
+// Create 创建任务日志，状态变更日志写入后发布事件
+func (r *eventTaskLogRepository) Create(ctx context.Context, log *model.TaskLog) error {
+	if err := r.TaskLogRepository.Create(ctx, log); err != nil {
+		return err
+	}
+	if log.IsStateChange() {
+		_ = r.eventBus.Publish(ctx, log)
+	}
+	return nil
+}
+
+// CreateBatch 批量创建任务日志，其中的状态变更日志写入后一并发布
+func (r *eventTaskLogRepository) CreateBatch(ctx context.Context, logs []*model.TaskLog) error {
+	if err := r.TaskLogRepository.CreateBatch(ctx, logs); err != nil {
+		return err
+	}
+
+	stateChanges := make([]*model.TaskLog, 0, len(logs))
+	for _, log := range logs {
+		if log.IsStateChange() {
+			stateChanges = append(stateChanges, log)
+		}
+	}
+	_ = r.eventBus.Publish(ctx, stateChanges...)
+	return nil
+}
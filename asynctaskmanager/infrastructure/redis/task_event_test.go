@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/infrastructure/memory"
+)
+
+func TestEventTaskLogRepository_PublishesStateChanges(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	eventBus := NewTaskEventBus(client)
+	pubsub := eventBus.Subscribe(ctx)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	repo := NewEventTaskLogRepository(memory.NewTaskLogRepository(), eventBus)
+	_ = repo.Create(ctx, model.NewInfoLog("task-1", "not a transition"))
+	_ = repo.Create(ctx, model.NewStateChangeLog("task-1", model.StatusPending, model.StatusProcessing, "worker-1", "assigned"))
+	_ = repo.CreateBatch(ctx, []*model.TaskLog{
+		model.NewRetryLog("task-2", 1, "retry").WithTransition(model.StatusProcessing, model.StatusPending),
+		model.NewErrorLog("task-2", "worker-1", "boom", ""),
+	})
+
+	// 只有记录了状态变更的日志会发布
+	want := []struct {
+		taskID   string
+		toStatus model.TaskStatus
+	}{
+		{"task-1", model.StatusProcessing},
+		{"task-2", model.StatusPending},
+	}
+	messages := pubsub.Channel()
+	for _, w := range want {
+		select {
+		case msg := <-messages:
+			event, err := DecodeTaskEvent(msg.Payload)
+			if err != nil {
+				t.Fatalf("DecodeTaskEvent() error = %v", err)
+			}
+			if event.TaskID != w.taskID || event.ToStatus != w.toStatus {
+				t.Errorf("event = %s/%s, want %s/%s", event.TaskID, event.ToStatus, w.taskID, w.toStatus)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for event of %s", w.taskID)
+		}
+	}
+
+	select {
+	case msg := <-messages:
+		t.Errorf("unexpected event %s", msg.Payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if logs, _ := repo.GetByTaskID(ctx, "task-2"); len(logs) != 2 {
+		t.Errorf("logs of task-2 = %d, want 2", len(logs))
+	}
+}
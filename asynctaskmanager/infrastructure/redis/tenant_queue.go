@@ -0,0 +1,37 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// legacyTenantsKey 旧版本记录有过就绪任务的租户集合，仅用于 RebuildReadyIndex 升级
+	legacyTenantsKey = "queue:tenants"
+	// tenantQuantaKey 租户的调度配额（tenant -> quantum），未配置的租户配额为 1
+	tenantQuantaKey = "queue:tenants:quantum"
+	// tenantRoundKey 租户轮询状态：当前轮到的租户及其剩余赤字
+	tenantRoundKey = "queue:tenants:round"
+)
+
+// SetTenantQuanta 以 quanta 覆盖全部租户的调度配额，未出现的租户恢复默认配额
+func (qm *QueueManager) SetTenantQuanta(ctx context.Context, quanta map[string]int) error {
+	values := make([]interface{}, 0, len(quanta)*2)
+	for tenant, quantum := range quanta {
+		values = append(values, tenant, quantum)
+	}
+
+	err := qm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, tenantQuantaKey)
+		if len(values) > 0 {
+			pipe.HSet(ctx, tenantQuantaKey, values...)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("set tenant quanta failed: %w", err)
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+
 	"bamboo/asynctaskmanager/domain/model"
 	"bamboo/asynctaskmanager/domain/repository"
 )
@@ -13,8 +15,24 @@ import (
 const (
 	workerKeyPrefix = "worker:"
 	workerTTL       = 30 * time.Second
+	// workerRegistryKey 已注册的 Worker ID 集合，Worker 信息过期后仍保留，用于故障转移
+	workerRegistryKey = "workers:registered"
 )
 
+// incrLoadScript 增减 Worker 负载且不小于 0，Worker 信息已过期时不做处理
+// KEYS[1]=Worker 信息 ARGV[1]=增量
+var incrLoadScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+local load = redis.call('HINCRBY', KEYS[1], 'current_load', ARGV[1])
+if load < 0 then
+	redis.call('HSET', KEYS[1], 'current_load', 0)
+	return 0
+end
+return load
+`)
+
 type workerRepositoryImpl struct {
 	client *Client
 }
@@ -49,6 +67,11 @@ func (r *workerRepositoryImpl) Register(ctx context.Context, worker *model.Worke
 		return fmt.Errorf("set worker ttl failed: %w", err)
 	}
 
+	// 添加到注册集合
+	if err := r.client.SAdd(ctx, workerRegistryKey, worker.WorkerID); err != nil {
+		return fmt.Errorf("add to worker registry failed: %w", err)
+	}
+
 	// 添加到任务类型索引
 	for _, taskType := range worker.SupportedTypes {
 		indexKey := fmt.Sprintf("worker:type:%s", taskType)
@@ -94,6 +117,30 @@ func (r *workerRepositoryImpl) Remove(ctx context.Context, workerID string) erro
 	return r.client.Del(ctx, key)
 }
 
+func (r *workerRepositoryImpl) FindExpired(ctx context.Context, timeout time.Duration) ([]string, error) {
+	workerIDs, err := r.client.SMembers(ctx, workerRegistryKey)
+	if err != nil {
+		return nil, fmt.Errorf("get worker registry failed: %w", err)
+	}
+
+	expired := make([]string, 0)
+	for _, workerID := range workerIDs {
+		worker, err := r.GetByID(ctx, workerID)
+		if err != nil || !worker.IsHealthy(timeout) {
+			expired = append(expired, workerID)
+		}
+	}
+
+	return expired, nil
+}
+
+func (r *workerRepositoryImpl) Purge(ctx context.Context, workerID string) error {
+	if err := r.Remove(ctx, workerID); err != nil {
+		return err
+	}
+	return r.client.SRem(ctx, workerRegistryKey, workerID)
+}
+
 func (r *workerRepositoryImpl) FindAll(ctx context.Context) ([]*model.Worker, error) {
 	keys, err := r.client.Keys(ctx, workerKeyPrefix+"*")
 	if err != nil {
@@ -175,6 +222,14 @@ func (r *workerRepositoryImpl) UpdateLoad(ctx context.Context, workerID string,
 	return r.client.HSet(ctx, key, "current_load", load)
 }
 
+func (r *workerRepositoryImpl) IncrLoad(ctx context.Context, workerID string, delta int) error {
+	key := workerKeyPrefix + workerID
+	if err := r.client.RunScript(ctx, incrLoadScript, []string{key}, delta).Err(); err != nil {
+		return fmt.Errorf("incr load failed: %w", err)
+	}
+	return nil
+}
+
 // 辅助函数
 func flattenMap(m map[string]interface{}) []interface{} {
 	result := make([]interface{}, 0, len(m)*2)
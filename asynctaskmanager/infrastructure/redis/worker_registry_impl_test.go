@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"bamboo/asynctaskmanager/domain/model"
+)
+
+func TestWorkerRepository_FindExpired(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+
+	repo := NewWorkerRepository(client)
+	ctx := context.Background()
+
+	for _, id := range []string{"worker-1", "worker-2", "worker-3"} {
+		err := repo.Register(ctx, &model.Worker{
+			WorkerID:       id,
+			Status:         model.WorkerOnline,
+			SupportedTypes: []string{"example_task"},
+			LastHeartbeat:  time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	// worker-1 心跳过期后注册信息失效，worker-2 正常下线
+	mr.Del(workerKeyPrefix + "worker-1")
+	if err := repo.Remove(ctx, "worker-2"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	expired, err := repo.FindExpired(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("FindExpired() error = %v", err)
+	}
+	want := map[string]bool{"worker-1": true, "worker-2": true}
+	if len(expired) != len(want) {
+		t.Fatalf("FindExpired() = %v, want worker-1 and worker-2", expired)
+	}
+	for _, id := range expired {
+		if !want[id] {
+			t.Errorf("FindExpired() unexpected worker %s", id)
+		}
+	}
+
+	for _, id := range expired {
+		if err := repo.Purge(ctx, id); err != nil {
+			t.Fatalf("Purge() error = %v", err)
+		}
+	}
+	if expired, _ := repo.FindExpired(ctx, time.Minute); len(expired) != 0 {
+		t.Errorf("FindExpired() after purge = %v, want empty", expired)
+	}
+}
@@ -0,0 +1,116 @@
+package resultstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bamboo/asynctaskmanager/domain/service"
+)
+
+// LocalResultStore 本地文件系统结果存储，每个结果一个文件，URI 形如 file:///data/results/{key}
+// 多个节点共用时目录需位于共享存储上
+type LocalResultStore struct {
+	dir string
+}
+
+// NewLocalResultStore 创建本地文件系统结果存储，目录不存在时自动创建
+func NewLocalResultStore(dir string) (*LocalResultStore, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve result dir failed: %w", err)
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, fmt.Errorf("create result dir failed: %w", err)
+	}
+	return &LocalResultStore{dir: abs}, nil
+}
+
+// Put 先写入临时文件再重命名，读取方不会看到写了一半的结果
+func (s *LocalResultStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("create result dir failed: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".result-*")
+	if err != nil {
+		return "", fmt.Errorf("create result file failed: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("write result file failed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("write result file failed: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("rename result file failed: %w", err)
+	}
+
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String(), nil
+}
+
+// Open 打开结果文件
+func (s *LocalResultStore) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	path, err := s.pathOf(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", service.ErrResultNotFound, uri)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open result file failed: %w", err)
+	}
+	return f, nil
+}
+
+// Delete 删除结果文件
+func (s *LocalResultStore) Delete(ctx context.Context, uri string) error {
+	path, err := s.pathOf(uri)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete result file failed: %w", err)
+	}
+	return nil
+}
+
+// pathOf 将 URI 转换为文件路径，只允许访问存储目录内的文件
+func (s *LocalResultStore) pathOf(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", fmt.Errorf("invalid result uri %q", uri)
+	}
+
+	path := filepath.Clean(filepath.FromSlash(u.Path))
+	if !strings.HasPrefix(path, s.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("result uri %q is outside %s", uri, s.dir)
+	}
+	return path, nil
+}
+
+// validateKey 校验结果的 key：非空的相对路径，不允许 ".." 逃逸
+func validateKey(key string) error {
+	if !fs.ValidPath(key) || key == "." {
+		return fmt.Errorf("invalid result key %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package resultstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bamboo/asynctaskmanager/domain/service"
+)
+
+func TestLocalResultStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "results")
+	store, err := NewLocalResultStore(dir)
+	if err != nil {
+		t.Fatalf("NewLocalResultStore() error = %v", err)
+	}
+	ctx := context.Background()
+
+	uri, err := store.Put(ctx, "2024/task-1.json", []byte(`{"rows":3}`))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2024", "task-1.json")); err != nil {
+		t.Errorf("result file not written: %v", err)
+	}
+
+	// 重复写入时覆盖
+	if _, err := store.Put(ctx, "2024/task-1.json", []byte(`{"rows":4}`)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	r, err := store.Open(ctx, uri)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != `{"rows":4}` {
+		t.Errorf("Open() data = %s", data)
+	}
+
+	if err := store.Delete(ctx, uri); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete(ctx, uri); err != nil {
+		t.Errorf("Delete() missing result error = %v", err)
+	}
+	if _, err := store.Open(ctx, uri); !errors.Is(err, service.ErrResultNotFound) {
+		t.Errorf("Open() after delete error = %v, want %v", err, service.ErrResultNotFound)
+	}
+}
+
+func TestLocalResultStore_RejectsOutsidePaths(t *testing.T) {
+	store, err := NewLocalResultStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalResultStore() error = %v", err)
+	}
+	ctx := context.Background()
+
+	for _, key := range []string{"", ".", "../escape.json", "/etc/passwd"} {
+		if _, err := store.Put(ctx, key, []byte("{}")); err == nil {
+			t.Errorf("Put(%q) should fail", key)
+		}
+	}
+	for _, uri := range []string{"file:///etc/passwd", "s3://bucket/key", "file://" + store.dir + "/../x"} {
+		if _, err := store.Open(ctx, uri); err == nil {
+			t.Errorf("Open(%q) should fail", uri)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package resultstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"bamboo/asynctaskmanager/domain/service"
+)
+
+const (
+	// emptyPayloadHash 空请求体的 SHA-256
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	// s3TimeFormat SigV4 签名时间格式
+	s3TimeFormat = "20060102T150405Z"
+)
+
+// S3Config S3 兼容存储配置
+type S3Config struct {
+	Endpoint        string // 服务地址，例如 https://s3.us-east-1.amazonaws.com 或 http://localhost:9000
+	Region          string // 签名使用的区域，默认 us-east-1
+	Bucket          string
+	Prefix          string // 对象 key 前缀，例如 results/
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3ResultStore S3 兼容对象存储的结果存储，使用 path-style 地址与 SigV4 签名，URI 形如 s3://{bucket}/{prefix}{key}
+type S3ResultStore struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3ResultStore 创建 S3 兼容的结果存储，client 为 nil 时使用默认 HTTP 客户端
+func NewS3ResultStore(config S3Config, client *http.Client) (*S3ResultStore, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, errors.New("s3 bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	return &S3ResultStore{config: config, endpoint: endpoint, client: client, now: time.Now}, nil
+}
+
+// Put 上传结果对象
+func (s *S3ResultStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	objectKey := s.config.Prefix + key
+	resp, err := s.do(ctx, http.MethodPut, objectKey, data)
+	if err != nil {
+		return "", fmt.Errorf("put result object failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("put result object failed: %s", responseError(resp))
+	}
+	return "s3://" + s.config.Bucket + "/" + objectKey, nil
+}
+
+// Open 下载结果对象，返回的响应体需由调用方关闭
+func (s *S3ResultStore) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	objectKey, err := s.objectKeyOf(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, objectKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get result object failed: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", service.ErrResultNotFound, uri)
+	default:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("get result object failed: %s", responseError(resp))
+	}
+}
+
+// Delete 删除结果对象，S3 删除不存在的对象同样返回成功
+func (s *S3ResultStore) Delete(ctx context.Context, uri string) error {
+	objectKey, err := s.objectKeyOf(uri)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodDelete, objectKey, nil)
+	if err != nil {
+		return fmt.Errorf("delete result object failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("delete result object failed: %s", responseError(resp))
+	}
+	return nil
+}
+
+// objectKeyOf 从 URI 解析对象 key，只允许访问配置的 bucket
+func (s *S3ResultStore) objectKeyOf(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "s3" || u.Host != s.config.Bucket {
+		return "", fmt.Errorf("invalid result uri %q", uri)
+	}
+	objectKey := strings.TrimPrefix(u.Path, "/")
+	if objectKey == "" {
+		return "", fmt.Errorf("invalid result uri %q", uri)
+	}
+	return objectKey, nil
+}
+
+// do 发送签名后的对象请求
+func (s *S3ResultStore) do(ctx context.Context, method, objectKey string, body []byte) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.config.Bucket + "/" + objectKey
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	signRequest(req, payloadHash, s.config.Region, s.config.AccessKeyID, s.config.SecretAccessKey, s.now().UTC())
+
+	return s.client.Do(req)
+}
+
+// signRequest 按 AWS Signature Version 4 签名请求
+// 签名 Host、Content-Type、Range 与全部 x-amz-* 请求头
+func signRequest(req *http.Request, payloadHash, region, accessKeyID, secretAccessKey string, now time.Time) {
+	amzDate := now.Format(s3TimeFormat)
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery 按参数名排序并编码查询字符串
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape 按 SigV4 的要求编码，空格编码为 %20
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// responseError 读取错误响应的状态与正文摘要
+func responseError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return strings.TrimSpace(resp.Status + " " + string(body))
+}
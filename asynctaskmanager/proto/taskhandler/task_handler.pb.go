@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.2
+// source: proto/taskhandler/task_handler.proto
+
+package taskhandler
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// HandleTaskRequest 执行任务请求
+type HandleTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TaskType      string                 `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	Payload       *structpb.Struct       `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	RetryCount    int32                  `protobuf:"varint,4,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandleTaskRequest) Reset() {
+	*x = HandleTaskRequest{}
+	mi := &file_proto_taskhandler_task_handler_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandleTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandleTaskRequest) ProtoMessage() {}
+
+func (x *HandleTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_taskhandler_task_handler_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandleTaskRequest.ProtoReflect.Descriptor instead.
+func (*HandleTaskRequest) Descriptor() ([]byte, []int) {
+	return file_proto_taskhandler_task_handler_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HandleTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *HandleTaskRequest) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *HandleTaskRequest) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *HandleTaskRequest) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+// HandleTaskResponse 执行任务响应
+type HandleTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *structpb.Struct       `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandleTaskResponse) Reset() {
+	*x = HandleTaskResponse{}
+	mi := &file_proto_taskhandler_task_handler_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandleTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandleTaskResponse) ProtoMessage() {}
+
+func (x *HandleTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_taskhandler_task_handler_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandleTaskResponse.ProtoReflect.Descriptor instead.
+func (*HandleTaskResponse) Descriptor() ([]byte, []int) {
+	return file_proto_taskhandler_task_handler_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HandleTaskResponse) GetResult() *structpb.Struct {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+var File_proto_taskhandler_task_handler_proto protoreflect.FileDescriptor
+
+const file_proto_taskhandler_task_handler_proto_rawDesc = "" +
+	"\n" +
+	"$proto/taskhandler/task_handler.proto\x12\vtaskhandler\x1a\x1cgoogle/protobuf/struct.proto\"\x9d\x01\n" +
+	"\x11HandleTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\ttask_type\x18\x02 \x01(\tR\btaskType\x121\n" +
+	"\apayload\x18\x03 \x01(\v2\x17.google.protobuf.StructR\apayload\x12\x1f\n" +
+	"\vretry_count\x18\x04 \x01(\x05R\n" +
+	"retryCount\"E\n" +
+	"\x12HandleTaskResponse\x12/\n" +
+	"\x06result\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06result2X\n" +
+	"\vTaskHandler\x12I\n" +
+	"\x06Handle\x12\x1e.taskhandler.HandleTaskRequest\x1a\x1f.taskhandler.HandleTaskResponseB7Z5bamboo/asynctaskmanager/proto/taskhandler;taskhandlerb\x06proto3"
+
+var (
+	file_proto_taskhandler_task_handler_proto_rawDescOnce sync.Once
+	file_proto_taskhandler_task_handler_proto_rawDescData []byte
+)
+
+func file_proto_taskhandler_task_handler_proto_rawDescGZIP() []byte {
+	file_proto_taskhandler_task_handler_proto_rawDescOnce.Do(func() {
+		file_proto_taskhandler_task_handler_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_taskhandler_task_handler_proto_rawDesc), len(file_proto_taskhandler_task_handler_proto_rawDesc)))
+	})
+	return file_proto_taskhandler_task_handler_proto_rawDescData
+}
+
+var file_proto_taskhandler_task_handler_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_taskhandler_task_handler_proto_goTypes = []any{
+	(*HandleTaskRequest)(nil),  // 0: taskhandler.HandleTaskRequest
+	(*HandleTaskResponse)(nil), // 1: taskhandler.HandleTaskResponse
+	(*structpb.Struct)(nil),    // 2: google.protobuf.Struct
+}
+var file_proto_taskhandler_task_handler_proto_depIdxs = []int32{
+	2, // 0: taskhandler.HandleTaskRequest.payload:type_name -> google.protobuf.Struct
+	2, // 1: taskhandler.HandleTaskResponse.result:type_name -> google.protobuf.Struct
+	0, // 2: taskhandler.TaskHandler.Handle:input_type -> taskhandler.HandleTaskRequest
+	1, // 3: taskhandler.TaskHandler.Handle:output_type -> taskhandler.HandleTaskResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_proto_taskhandler_task_handler_proto_init() }
+func file_proto_taskhandler_task_handler_proto_init() {
+	if File_proto_taskhandler_task_handler_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_taskhandler_task_handler_proto_rawDesc), len(file_proto_taskhandler_task_handler_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_taskhandler_task_handler_proto_goTypes,
+		DependencyIndexes: file_proto_taskhandler_task_handler_proto_depIdxs,
+		MessageInfos:      file_proto_taskhandler_task_handler_proto_msgTypes,
+	}.Build()
+	File_proto_taskhandler_task_handler_proto = out.File
+	file_proto_taskhandler_task_handler_proto_goTypes = nil
+	file_proto_taskhandler_task_handler_proto_depIdxs = nil
+}
@@ -0,0 +1,129 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.2
+// source: proto/taskhandler/task_handler.proto
+
+package taskhandler
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TaskHandler_Handle_FullMethodName = "/taskhandler.TaskHandler/Handle"
+)
+
+// TaskHandlerClient is the client API for TaskHandler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TaskHandler 任务处理服务
+// 由独立部署的任务处理方实现，RPC 执行器按任务配置调用
+type TaskHandlerClient interface {
+	// Handle 执行任务，返回非 OK 状态码表示执行失败
+	Handle(ctx context.Context, in *HandleTaskRequest, opts ...grpc.CallOption) (*HandleTaskResponse, error)
+}
+
+type taskHandlerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTaskHandlerClient(cc grpc.ClientConnInterface) TaskHandlerClient {
+	return &taskHandlerClient{cc}
+}
+
+func (c *taskHandlerClient) Handle(ctx context.Context, in *HandleTaskRequest, opts ...grpc.CallOption) (*HandleTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandleTaskResponse)
+	err := c.cc.Invoke(ctx, TaskHandler_Handle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TaskHandlerServer is the server API for TaskHandler service.
+// All implementations must embed UnimplementedTaskHandlerServer
+// for forward compatibility.
+//
+// TaskHandler 任务处理服务
+// 由独立部署的任务处理方实现，RPC 执行器按任务配置调用
+type TaskHandlerServer interface {
+	// Handle 执行任务，返回非 OK 状态码表示执行失败
+	Handle(context.Context, *HandleTaskRequest) (*HandleTaskResponse, error)
+	mustEmbedUnimplementedTaskHandlerServer()
+}
+
+// UnimplementedTaskHandlerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTaskHandlerServer struct{}
+
+func (UnimplementedTaskHandlerServer) Handle(context.Context, *HandleTaskRequest) (*HandleTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Handle not implemented")
+}
+func (UnimplementedTaskHandlerServer) mustEmbedUnimplementedTaskHandlerServer() {}
+func (UnimplementedTaskHandlerServer) testEmbeddedByValue()                     {}
+
+// UnsafeTaskHandlerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaskHandlerServer will
+// result in compilation errors.
+type UnsafeTaskHandlerServer interface {
+	mustEmbedUnimplementedTaskHandlerServer()
+}
+
+func RegisterTaskHandlerServer(s grpc.ServiceRegistrar, srv TaskHandlerServer) {
+	// If the following call panics, it indicates UnimplementedTaskHandlerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TaskHandler_ServiceDesc, srv)
+}
+
+func _TaskHandler_Handle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandleTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).Handle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskHandler_Handle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).Handle(ctx, req.(*HandleTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TaskHandler_ServiceDesc is the grpc.ServiceDesc for TaskHandler service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TaskHandler_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "taskhandler.TaskHandler",
+	HandlerType: (*TaskHandlerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Handle",
+			Handler:    _TaskHandler_Handle_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/taskhandler/task_handler.proto",
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	pb "bamboo/cmd/asynctaskmanager/proto"
 )
@@ -40,23 +44,69 @@ func (c *GRPCClient) Close() error {
 
 // CreateTask 创建任务
 func (c *GRPCClient) CreateTask(ctx context.Context, taskType string, priority int32, payload map[string]interface{}) (*pb.Task, error) {
-	// 转换 payload
-	pbPayload := make(map[string]string)
-	for k, v := range payload {
-		if str, ok := v.(string); ok {
-			pbPayload[k] = str
-		} else {
-			// 将非字符串类型转为 JSON
-			if jsonBytes, err := json.Marshal(v); err == nil {
-				pbPayload[k] = string(jsonBytes)
-			}
-		}
+	pbPayload, err := toProtoPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.CreateTaskRequest{
+		TaskType:      taskType,
+		Priority:      priority,
+		PayloadStruct: pbPayload,
+	}
+
+	resp, err := c.client.CreateTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Task, nil
+}
+
+// CreateTasks 批量创建任务，结果与 reqs 按下标一一对应
+func (c *GRPCClient) CreateTasks(ctx context.Context, reqs []*pb.CreateTaskRequest) ([]*pb.CreateTaskResult, error) {
+	resp, err := c.client.CreateTasks(ctx, &pb.CreateTasksRequest{Tasks: reqs})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Results, nil
+}
+
+// CreateIdempotentTask 携带幂等键创建任务，重复提交返回首次创建的任务
+func (c *GRPCClient) CreateIdempotentTask(ctx context.Context, taskType string, priority int32, payload map[string]interface{}, idempotencyKey string) (*pb.Task, error) {
+	pbPayload, err := toProtoPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.CreateTaskRequest{
+		TaskType:       taskType,
+		Priority:       priority,
+		PayloadStruct:  pbPayload,
+		IdempotencyKey: idempotencyKey,
+	}
+
+	resp, err := c.client.CreateTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Task, nil
+}
+
+// CreateDelayedTask 创建延迟执行的任务
+func (c *GRPCClient) CreateDelayedTask(ctx context.Context, taskType string, priority int32, payload map[string]interface{}, delay time.Duration) (*pb.Task, error) {
+	pbPayload, err := toProtoPayload(payload)
+	if err != nil {
+		return nil, err
 	}
 
 	req := &pb.CreateTaskRequest{
-		TaskType: taskType,
-		Priority: priority,
-		Payload:  pbPayload,
+		TaskType:      taskType,
+		Priority:      priority,
+		PayloadStruct: pbPayload,
+		DelaySeconds:  int64(delay / time.Second),
 	}
 
 	resp, err := c.client.CreateTask(ctx, req)
@@ -67,6 +117,20 @@ func (c *GRPCClient) CreateTask(ctx context.Context, taskType string, priority i
 	return resp.Task, nil
 }
 
+// toProtoPayload 转换 payload 为类型化的 protobuf Struct，值先按 JSON 编码，因此支持任意可 JSON 编码的类型
+func toProtoPayload(payload map[string]interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload failed: %w", err)
+	}
+
+	pbPayload := &structpb.Struct{}
+	if err := pbPayload.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("convert payload failed: %w", err)
+	}
+	return pbPayload, nil
+}
+
 // GetTask 查询任务
 func (c *GRPCClient) GetTask(ctx context.Context, taskID string) (*pb.Task, error) {
 	req := &pb.GetTaskRequest{
@@ -95,6 +159,106 @@ func (c *GRPCClient) CancelTask(ctx context.Context, taskID string) (bool, strin
 	return resp.Success, resp.Message, nil
 }
 
+// RetryTask 手动重试已结束的任务，priority 为 nil 表示保持原优先级
+func (c *GRPCClient) RetryTask(ctx context.Context, taskID string, resetRetryCount bool, priority *int32, triggeredBy string) (*pb.Task, error) {
+	req := &pb.RetryTaskRequest{
+		TaskId:          taskID,
+		ResetRetryCount: resetRetryCount,
+		Priority:        priority,
+		TriggeredBy:     triggeredBy,
+	}
+
+	resp, err := c.client.RetryTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Task, nil
+}
+
+// CreateWorkflow 创建工作流，failurePolicy 为空时使用 FAIL_FAST
+func (c *GRPCClient) CreateWorkflow(ctx context.Context, name, failurePolicy string, nodes []*pb.WorkflowNodeSpec) (*pb.Workflow, error) {
+	req := &pb.CreateWorkflowRequest{
+		Name:          name,
+		FailurePolicy: failurePolicy,
+		Nodes:         nodes,
+	}
+
+	resp, err := c.client.CreateWorkflow(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Workflow, nil
+}
+
+// GetWorkflow 查询工作流
+func (c *GRPCClient) GetWorkflow(ctx context.Context, workflowID string) (*pb.Workflow, error) {
+	req := &pb.GetWorkflowRequest{
+		WorkflowId: workflowID,
+	}
+
+	resp, err := c.client.GetWorkflow(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Workflow, nil
+}
+
+// ListDeadLetters 列出死信，filter 为 nil 表示不过滤
+func (c *GRPCClient) ListDeadLetters(ctx context.Context, filter *pb.DeadLetterFilter, pageSize int32, pageToken string) ([]*pb.DeadLetter, int32, string, error) {
+	req := &pb.ListDeadLettersRequest{
+		Filter:    filter,
+		PageSize:  pageSize,
+		PageToken: pageToken,
+	}
+
+	resp, err := c.client.ListDeadLetters(ctx, req)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return resp.DeadLetters, resp.Total, resp.NextPageToken, nil
+}
+
+// RequeueDeadLetter 重新入队死信任务，payload 不为 nil 时替换原任务参数
+func (c *GRPCClient) RequeueDeadLetter(ctx context.Context, taskID string, payload map[string]interface{}) (*pb.Task, error) {
+	req := &pb.RequeueDeadLetterRequest{
+		TaskId:         taskID,
+		ReplacePayload: payload != nil,
+	}
+	if payload != nil {
+		pbPayload, err := toProtoPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		req.PayloadStruct = pbPayload
+	}
+
+	resp, err := c.client.RequeueDeadLetter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Task, nil
+}
+
+// PurgeDeadLetters 按条件清除死信
+func (c *GRPCClient) PurgeDeadLetters(ctx context.Context, filter *pb.DeadLetterFilter, all bool) (int64, error) {
+	req := &pb.PurgeDeadLettersRequest{
+		Filter: filter,
+		All:    all,
+	}
+
+	resp, err := c.client.PurgeDeadLetters(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.Purged, nil
+}
+
 // GetTaskLogs 获取任务日志
 func (c *GRPCClient) GetTaskLogs(ctx context.Context, taskID string) ([]*pb.TaskLog, error) {
 	req := &pb.GetTaskLogsRequest{
@@ -109,41 +273,102 @@ func (c *GRPCClient) GetTaskLogs(ctx context.Context, taskID string) ([]*pb.Task
 	return resp.Logs, nil
 }
 
-// ListTasks 列出任务
-func (c *GRPCClient) ListTasks(ctx context.Context, status string, priority int32, page, pageSize int32) ([]*pb.Task, int32, error) {
+// ListTasks 列出任务，priority 为负数表示不按优先级过滤，pageToken 为上一页返回的 next_page_token，为空表示第一页
+// 返回当前页任务、满足条件的总数以及下一页的 token
+func (c *GRPCClient) ListTasks(ctx context.Context, status string, priority int32, pageSize int32, pageToken string) ([]*pb.Task, int32, string, error) {
 	req := &pb.ListTasksRequest{
-		Status:   status,
-		Priority: priority,
-		Page:     page,
-		PageSize: pageSize,
+		Status:    status,
+		PageSize:  pageSize,
+		PageToken: pageToken,
+	}
+	if priority >= 0 {
+		req.Priority = &priority
 	}
 
 	resp, err := c.client.ListTasks(ctx, req)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 
-	return resp.Tasks, resp.Total, nil
+	return resp.Tasks, resp.Total, resp.NextPageToken, nil
 }
 
-// WaitForTask 等待任务完成
-func (c *GRPCClient) WaitForTask(ctx context.Context, taskID string, timeout time.Duration) (*pb.Task, error) {
-	deadline := time.Now().Add(timeout)
+// WatchTask 订阅任务状态变更，每个事件调用一次 onEvent，任务结束时返回 nil
+func (c *GRPCClient) WatchTask(ctx context.Context, taskID string, onEvent func(*pb.TaskEvent)) error {
+	stream, err := c.client.WatchTask(ctx, &pb.WatchTaskRequest{TaskId: taskID})
+	if err != nil {
+		return err
+	}
+	return receiveTaskEvents(stream, onEvent)
+}
+
+// WatchTasks 按任务类型与状态订阅任务状态变更，直到 ctx 取消或服务端关闭订阅
+func (c *GRPCClient) WatchTasks(ctx context.Context, taskTypes, statuses []string, onEvent func(*pb.TaskEvent)) error {
+	stream, err := c.client.WatchTasks(ctx, &pb.WatchTasksRequest{TaskTypes: taskTypes, Statuses: statuses})
+	if err != nil {
+		return err
+	}
+	return receiveTaskEvents(stream, onEvent)
+}
 
-	for time.Now().Before(deadline) {
-		task, err := c.GetTask(ctx, taskID)
+// receiveTaskEvents 读取事件流直到结束
+func receiveTaskEvents(stream grpc.ServerStreamingClient[pb.TaskEvent], onEvent func(*pb.TaskEvent)) error {
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		onEvent(event)
+	}
+}
+
+// GetTaskResult 读取任务的完整结果（JSON 编码），结果已转存到结果存储时同样适用
+func (c *GRPCClient) GetTaskResult(ctx context.Context, taskID string) ([]byte, error) {
+	stream, err := c.client.GetTaskResult(ctx, &pb.GetTaskResultRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return data, nil
+		}
 		if err != nil {
 			return nil, err
 		}
+		if data == nil {
+			data = make([]byte, 0, chunk.TotalSize)
+		}
+		data = append(data, chunk.Data...)
+	}
+}
+
+// WaitForTask 通过 WatchTask 等待任务结束，订阅被服务端关闭时重新订阅
+func (c *GRPCClient) WaitForTask(ctx context.Context, taskID string, timeout time.Duration) (*pb.Task, error) {
+	watchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-		// 检查任务是否完成
-		if task.Status == "completed" || task.Status == "failed" || task.Status == "cancelled" {
-			return task, nil
+	for {
+		err := c.WatchTask(watchCtx, taskID, func(*pb.TaskEvent) {})
+		if err == nil {
+			return c.GetTask(ctx, taskID)
 		}
 
-		// 等待一段时间后重试
-		time.Sleep(1 * time.Second)
+		switch status.Code(err) {
+		case codes.ResourceExhausted, codes.Unavailable:
+			select {
+			case <-watchCtx.Done():
+			case <-time.After(time.Second):
+			}
+		case codes.DeadlineExceeded:
+			return nil, fmt.Errorf("task %s timeout after %v", taskID, timeout)
+		default:
+			return nil, err
+		}
 	}
-
-	return nil, fmt.Errorf("task %s timeout after %v", taskID, timeout)
 }
@@ -5,6 +5,8 @@ import (
 	"flag"
 	"log"
 	"time"
+
+	pb "bamboo/cmd/asynctaskmanager/proto"
 )
 
 func main() {
@@ -48,24 +50,41 @@ func main() {
 
 	// 测试 3: 批量创建任务
 	log.Println("\n--- Test 3: Create Multiple Tasks ---")
-	taskIDs := make([]string, 0)
+	reqs := make([]*pb.CreateTaskRequest, 0, 5)
 	for i := 0; i < 5; i++ {
 		priority := int32(0)
 		if i%2 == 0 {
 			priority = 1
 		}
 
-		task, err := grpcClient.CreateTask(ctx, "example_task", priority, map[string]interface{}{
+		payload, err := toProtoPayload(map[string]interface{}{
 			"message": "Batch task",
 			"number":  i + 3,
 		})
 		if err != nil {
-			log.Printf("Failed to create task %d: %v", i+1, err)
+			log.Fatalf("Failed to convert payload: %v", err)
+		}
+
+		reqs = append(reqs, &pb.CreateTaskRequest{
+			TaskType:      "example_task",
+			Priority:      priority,
+			PayloadStruct: payload,
+		})
+	}
+
+	taskIDs := make([]string, 0)
+	results, err := grpcClient.CreateTasks(ctx, reqs)
+	if err != nil {
+		log.Printf("Failed to create tasks: %v", err)
+	}
+	for i, result := range results {
+		if result.Error != "" {
+			log.Printf("Failed to create task %d: %s", i+1, result.Error)
 			continue
 		}
 
-		taskIDs = append(taskIDs, task.TaskId)
-		log.Printf("✓ Task %d created: %s (Priority: %d)", i+1, task.TaskId, task.Priority)
+		taskIDs = append(taskIDs, result.Task.TaskId)
+		log.Printf("✓ Task %d created: %s (Priority: %d)", i+1, result.Task.TaskId, result.Task.Priority)
 	}
 
 	// 等待任务执行
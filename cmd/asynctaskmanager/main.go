@@ -3,7 +3,10 @@ package main
 import (
 	"flag"
 	"log"
+	"os"
+	"time"
 
+	"bamboo/asynctaskmanager/infrastructure/resultstore"
 	"bamboo/cmd/asynctaskmanager/server"
 )
 
@@ -14,6 +17,15 @@ func main() {
 	workerPort := flag.Int("worker-port", 8080, "Worker port")
 	redisAddr := flag.String("redis", "localhost:6379", "Redis address")
 	mysqlDSN := flag.String("mysql", "root:a123456@tcp(localhost:3306)/asynctask?charset=utf8mb4&parseTime=True&loc=Local", "MySQL DSN")
+	priorityWeights := flag.String("priority-weights", "", "Comma-separated dequeue weights for priority 0-9 (default: priority+1)")
+	priorityAging := flag.Duration("priority-aging", 30*time.Second, "Dequeue a task first once it waits at the queue head this long (0 disables)")
+	resultStore := flag.String("result-store", "", "Store for large task results: local or s3 (default: keep all results in MySQL)")
+	resultDir := flag.String("result-dir", "data/results", "Directory of the local result store")
+	resultThreshold := flag.Int("result-offload-threshold", 64<<10, "Offload results larger than this many bytes (JSON encoded) to the result store")
+	resultS3Endpoint := flag.String("result-s3-endpoint", "", "Endpoint of the S3-compatible result store, e.g. http://localhost:9000")
+	resultS3Region := flag.String("result-s3-region", "us-east-1", "Region of the S3-compatible result store")
+	resultS3Bucket := flag.String("result-s3-bucket", "", "Bucket of the S3-compatible result store")
+	resultS3Prefix := flag.String("result-s3-prefix", "results/", "Object key prefix of the S3-compatible result store")
 	flag.Parse()
 
 	log.Printf("Starting Async Task Manager Server")
@@ -24,11 +36,25 @@ func main() {
 
 	// 创建服务器配置
 	cfg := &server.ServerConfig{
-		ServerID:   *serverID,
-		GRPCPort:   *grpcPort,
-		RedisAddr:  *redisAddr,
-		MysqlDSN:   server.MysqlDSN(*mysqlDSN),
-		WorkerPort: *workerPort,
+		ServerID:        *serverID,
+		GRPCPort:        *grpcPort,
+		RedisAddr:       *redisAddr,
+		MysqlDSN:        server.MysqlDSN(*mysqlDSN),
+		WorkerPort:      *workerPort,
+		PriorityWeights: server.PriorityWeights(*priorityWeights),
+		PriorityAging:   *priorityAging,
+		ResultStore:     *resultStore,
+		ResultDir:       *resultDir,
+		ResultS3: resultstore.S3Config{
+			Endpoint: *resultS3Endpoint,
+			Region:   *resultS3Region,
+			Bucket:   *resultS3Bucket,
+			Prefix:   *resultS3Prefix,
+			// 密钥从环境变量读取，避免出现在命令行中
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		},
+		ResultOffloadThreshold: *resultThreshold,
 	}
 
 	// 运行服务器
@@ -9,6 +9,7 @@ package taskservice
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -24,12 +25,17 @@ const (
 
 // CreateTaskRequest 创建任务请求
 type CreateTaskRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskType      string                 `protobuf:"bytes,1,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
-	Priority      int32                  `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"` // 0=Normal, 1=High
-	Payload       map[string]string      `protobuf:"bytes,3,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TaskType       string                 `protobuf:"bytes,1,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	Priority       int32                  `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"`                                                                        // 0-9，数值越大越优先（0=Normal, 1=High）
+	Payload        map[string]string      `protobuf:"bytes,3,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 兼容旧客户端：值均为字符串
+	ScheduledAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`                                                // 可选：计划执行时间，与 delay_seconds 互斥
+	DelaySeconds   int64                  `protobuf:"varint,5,opt,name=delay_seconds,json=delaySeconds,proto3" json:"delay_seconds,omitempty"`                                            // 可选：延迟执行秒数
+	IdempotencyKey string                 `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`                                       // 可选：幂等键，同一任务类型下重复提交返回首次创建的任务
+	Tenant         string                 `protobuf:"bytes,7,opt,name=tenant,proto3" json:"tenant,omitempty"`                                                                             // 可选：所属租户，为空表示 default；仅允许字母、数字、'_'、'-'、'.'，最长 64 字节
+	PayloadStruct  *structpb.Struct       `protobuf:"bytes,8,opt,name=payload_struct,json=payloadStruct,proto3" json:"payload_struct,omitempty"`                                          // 类型化 payload，保留数字、布尔与嵌套对象，与 payload 互斥
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CreateTaskRequest) Reset() {
@@ -83,10 +89,46 @@ func (x *CreateTaskRequest) GetPayload() map[string]string {
 	return nil
 }
 
+func (x *CreateTaskRequest) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetDelaySeconds() int64 {
+	if x != nil {
+		return x.DelaySeconds
+	}
+	return 0
+}
+
+func (x *CreateTaskRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetPayloadStruct() *structpb.Struct {
+	if x != nil {
+		return x.PayloadStruct
+	}
+	return nil
+}
+
 // CreateTaskResponse 创建任务响应
 type CreateTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Duplicate     bool                   `protobuf:"varint,2,opt,name=duplicate,proto3" json:"duplicate,omitempty"` // 幂等键命中已存在的任务，未创建新任务
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -128,6 +170,180 @@ func (x *CreateTaskResponse) GetTask() *Task {
 	return nil
 }
 
+func (x *CreateTaskResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+// CreateTasksRequest 批量创建任务请求
+type CreateTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*CreateTaskRequest   `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"` // 单次最多 10000 个
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTasksRequest) Reset() {
+	*x = CreateTasksRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTasksRequest) ProtoMessage() {}
+
+func (x *CreateTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTasksRequest.ProtoReflect.Descriptor instead.
+func (*CreateTasksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTasksRequest) GetTasks() []*CreateTaskRequest {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+// CreateTaskResult 批量创建中单个任务的结果
+type CreateTaskResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`            // 创建失败时为空
+	Duplicate     bool                   `protobuf:"varint,2,opt,name=duplicate,proto3" json:"duplicate,omitempty"` // 幂等键命中已存在的任务或批内靠前的任务
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`          // 非空表示该任务创建失败
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskResult) Reset() {
+	*x = CreateTaskResult{}
+	mi := &file_proto_task_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskResult) ProtoMessage() {}
+
+func (x *CreateTaskResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskResult.ProtoReflect.Descriptor instead.
+func (*CreateTaskResult) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTaskResult) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *CreateTaskResult) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+func (x *CreateTaskResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// CreateTasksResponse 批量创建任务响应
+type CreateTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*CreateTaskResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`  // 与请求中的 tasks 按下标一一对应
+	Created       int32                  `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"` // 新创建的任务数
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`   // 创建失败的任务数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTasksResponse) Reset() {
+	*x = CreateTasksResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTasksResponse) ProtoMessage() {}
+
+func (x *CreateTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTasksResponse.ProtoReflect.Descriptor instead.
+func (*CreateTasksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateTasksResponse) GetResults() []*CreateTaskResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *CreateTasksResponse) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *CreateTasksResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
 // GetTaskRequest 查询任务请求
 type GetTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -138,7 +354,7 @@ type GetTaskRequest struct {
 
 func (x *GetTaskRequest) Reset() {
 	*x = GetTaskRequest{}
-	mi := &file_proto_task_service_proto_msgTypes[2]
+	mi := &file_proto_task_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -150,7 +366,7 @@ func (x *GetTaskRequest) String() string {
 func (*GetTaskRequest) ProtoMessage() {}
 
 func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[2]
+	mi := &file_proto_task_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -163,7 +379,7 @@ func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
 func (*GetTaskRequest) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{2}
+	return file_proto_task_service_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetTaskRequest) GetTaskId() string {
@@ -183,7 +399,7 @@ type GetTaskResponse struct {
 
 func (x *GetTaskResponse) Reset() {
 	*x = GetTaskResponse{}
-	mi := &file_proto_task_service_proto_msgTypes[3]
+	mi := &file_proto_task_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -195,7 +411,7 @@ func (x *GetTaskResponse) String() string {
 func (*GetTaskResponse) ProtoMessage() {}
 
 func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[3]
+	mi := &file_proto_task_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -208,7 +424,7 @@ func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskResponse.ProtoReflect.Descriptor instead.
 func (*GetTaskResponse) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{3}
+	return file_proto_task_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetTaskResponse) GetTask() *Task {
@@ -228,7 +444,7 @@ type CancelTaskRequest struct {
 
 func (x *CancelTaskRequest) Reset() {
 	*x = CancelTaskRequest{}
-	mi := &file_proto_task_service_proto_msgTypes[4]
+	mi := &file_proto_task_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -240,7 +456,7 @@ func (x *CancelTaskRequest) String() string {
 func (*CancelTaskRequest) ProtoMessage() {}
 
 func (x *CancelTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[4]
+	mi := &file_proto_task_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -253,7 +469,7 @@ func (x *CancelTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelTaskRequest.ProtoReflect.Descriptor instead.
 func (*CancelTaskRequest) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{4}
+	return file_proto_task_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CancelTaskRequest) GetTaskId() string {
@@ -274,7 +490,7 @@ type CancelTaskResponse struct {
 
 func (x *CancelTaskResponse) Reset() {
 	*x = CancelTaskResponse{}
-	mi := &file_proto_task_service_proto_msgTypes[5]
+	mi := &file_proto_task_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -286,7 +502,7 @@ func (x *CancelTaskResponse) String() string {
 func (*CancelTaskResponse) ProtoMessage() {}
 
 func (x *CancelTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[5]
+	mi := &file_proto_task_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -299,7 +515,7 @@ func (x *CancelTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelTaskResponse.ProtoReflect.Descriptor instead.
 func (*CancelTaskResponse) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{5}
+	return file_proto_task_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CancelTaskResponse) GetSuccess() bool {
@@ -326,7 +542,7 @@ type GetTaskLogsRequest struct {
 
 func (x *GetTaskLogsRequest) Reset() {
 	*x = GetTaskLogsRequest{}
-	mi := &file_proto_task_service_proto_msgTypes[6]
+	mi := &file_proto_task_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -338,7 +554,7 @@ func (x *GetTaskLogsRequest) String() string {
 func (*GetTaskLogsRequest) ProtoMessage() {}
 
 func (x *GetTaskLogsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[6]
+	mi := &file_proto_task_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -351,7 +567,7 @@ func (x *GetTaskLogsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskLogsRequest.ProtoReflect.Descriptor instead.
 func (*GetTaskLogsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{6}
+	return file_proto_task_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetTaskLogsRequest) GetTaskId() string {
@@ -371,7 +587,7 @@ type GetTaskLogsResponse struct {
 
 func (x *GetTaskLogsResponse) Reset() {
 	*x = GetTaskLogsResponse{}
-	mi := &file_proto_task_service_proto_msgTypes[7]
+	mi := &file_proto_task_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -383,7 +599,7 @@ func (x *GetTaskLogsResponse) String() string {
 func (*GetTaskLogsResponse) ProtoMessage() {}
 
 func (x *GetTaskLogsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[7]
+	mi := &file_proto_task_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -396,7 +612,7 @@ func (x *GetTaskLogsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskLogsResponse.ProtoReflect.Descriptor instead.
 func (*GetTaskLogsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{7}
+	return file_proto_task_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetTaskLogsResponse) GetLogs() []*TaskLog {
@@ -409,17 +625,23 @@ func (x *GetTaskLogsResponse) GetLogs() []*TaskLog {
 // ListTasksRequest 列出任务请求
 type ListTasksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`      // 可选：按状态过滤
-	Priority      int32                  `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"` // 可选：按优先级过滤，-1表示不过滤
-	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`                                    // 可选：按状态过滤
+	Priority      *int32                 `protobuf:"varint,2,opt,name=priority,proto3,oneof" json:"priority,omitempty"`                         // 可选：按优先级过滤，不传表示不过滤；兼容旧客户端，-1 同样表示不过滤
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`                                       // 已废弃：使用 page_token 翻页
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`               // 默认 20，最大 100
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`             // 上一页返回的 next_page_token，为空表示第一页
+	TaskType      string                 `protobuf:"bytes,6,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`                // 可选：按任务类型过滤
+	WorkerId      string                 `protobuf:"bytes,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`                // 可选：按 Worker 过滤
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`    // 可选：创建时间下界（含）
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"` // 可选：创建时间上界（不含）
+	Tenant        string                 `protobuf:"bytes,10,opt,name=tenant,proto3" json:"tenant,omitempty"`                                   // 可选：按租户过滤
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListTasksRequest) Reset() {
 	*x = ListTasksRequest{}
-	mi := &file_proto_task_service_proto_msgTypes[8]
+	mi := &file_proto_task_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -431,7 +653,7 @@ func (x *ListTasksRequest) String() string {
 func (*ListTasksRequest) ProtoMessage() {}
 
 func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[8]
+	mi := &file_proto_task_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -444,7 +666,7 @@ func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
 func (*ListTasksRequest) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{8}
+	return file_proto_task_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ListTasksRequest) GetStatus() string {
@@ -455,8 +677,8 @@ func (x *ListTasksRequest) GetStatus() string {
 }
 
 func (x *ListTasksRequest) GetPriority() int32 {
-	if x != nil {
-		return x.Priority
+	if x != nil && x.Priority != nil {
+		return *x.Priority
 	}
 	return 0
 }
@@ -475,18 +697,61 @@ func (x *ListTasksRequest) GetPageSize() int32 {
 	return 0
 }
 
+func (x *ListTasksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
 // ListTasksResponse 列出任务响应
 type ListTasksResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                       // 满足过滤条件的任务总数
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // 为空表示没有下一页
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListTasksResponse) Reset() {
 	*x = ListTasksResponse{}
-	mi := &file_proto_task_service_proto_msgTypes[9]
+	mi := &file_proto_task_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -498,7 +763,7 @@ func (x *ListTasksResponse) String() string {
 func (*ListTasksResponse) ProtoMessage() {}
 
 func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[9]
+	mi := &file_proto_task_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -511,7 +776,7 @@ func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
 func (*ListTasksResponse) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{9}
+	return file_proto_task_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ListTasksResponse) GetTasks() []*Task {
@@ -528,41 +793,36 @@ func (x *ListTasksResponse) GetTotal() int32 {
 	return 0
 }
 
-// Task 任务信息
-type Task struct {
+func (x *ListTasksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// WatchTaskRequest 订阅任务状态变更请求
+type WatchTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	TaskType      string                 `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
-	Priority      int32                  `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
-	Payload       map[string]string      `protobuf:"bytes,5,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Result        map[string]string      `protobuf:"bytes,6,rep,name=result,proto3" json:"result,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	WorkerId      string                 `protobuf:"bytes,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
-	RetryCount    int32                  `protobuf:"varint,8,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
-	MaxRetry      int32                  `protobuf:"varint,9,opt,name=max_retry,json=maxRetry,proto3" json:"max_retry,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,10,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Task) Reset() {
-	*x = Task{}
-	mi := &file_proto_task_service_proto_msgTypes[10]
+func (x *WatchTaskRequest) Reset() {
+	*x = WatchTaskRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Task) String() string {
+func (x *WatchTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Task) ProtoMessage() {}
+func (*WatchTaskRequest) ProtoMessage() {}
 
-func (x *Task) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[10]
+func (x *WatchTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -573,132 +833,1807 @@ func (x *Task) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Task.ProtoReflect.Descriptor instead.
-func (*Task) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use WatchTaskRequest.ProtoReflect.Descriptor instead.
+func (*WatchTaskRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *Task) GetTaskId() string {
+func (x *WatchTaskRequest) GetTaskId() string {
 	if x != nil {
 		return x.TaskId
 	}
 	return ""
 }
 
-func (x *Task) GetTaskType() string {
-	if x != nil {
+// WatchTasksRequest 按条件订阅任务状态变更请求，空字段表示不过滤
+type WatchTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskTypes     []string               `protobuf:"bytes,1,rep,name=task_types,json=taskTypes,proto3" json:"task_types,omitempty"` // 可选：任务类型
+	Statuses      []string               `protobuf:"bytes,2,rep,name=statuses,proto3" json:"statuses,omitempty"`                    // 可选：变更后的状态
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTasksRequest) Reset() {
+	*x = WatchTasksRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksRequest) ProtoMessage() {}
+
+func (x *WatchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksRequest.ProtoReflect.Descriptor instead.
+func (*WatchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchTasksRequest) GetTaskTypes() []string {
+	if x != nil {
+		return x.TaskTypes
+	}
+	return nil
+}
+
+func (x *WatchTasksRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+// TaskEvent 任务状态变更事件
+type TaskEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TaskType      string                 `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	LogType       string                 `protobuf:"bytes,3,opt,name=log_type,json=logType,proto3" json:"log_type,omitempty"` // 产生变更的日志类型：STATE_CHANGE、RETRY、ERROR
+	FromStatus    string                 `protobuf:"bytes,4,opt,name=from_status,json=fromStatus,proto3" json:"from_status,omitempty"`
+	ToStatus      string                 `protobuf:"bytes,5,opt,name=to_status,json=toStatus,proto3" json:"to_status,omitempty"`
+	Message       string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	RetryCount    int32                  `protobuf:"varint,8,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	Snapshot      bool                   `protobuf:"varint,10,opt,name=snapshot,proto3" json:"snapshot,omitempty"` // WatchTask 的首个事件，表示订阅时任务的当前状态而非一次变更
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEvent) Reset() {
+	*x = TaskEvent{}
+	mi := &file_proto_task_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEvent) ProtoMessage() {}
+
+func (x *TaskEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
+func (*TaskEvent) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TaskEvent) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetLogType() string {
+	if x != nil {
+		return x.LogType
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetFromStatus() string {
+	if x != nil {
+		return x.FromStatus
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetToStatus() string {
+	if x != nil {
+		return x.ToStatus
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *TaskEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+// GetTaskResultRequest 读取任务结果请求
+type GetTaskResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskResultRequest) Reset() {
+	*x = GetTaskResultRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskResultRequest) ProtoMessage() {}
+
+func (x *GetTaskResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskResultRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskResultRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetTaskResultRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// TaskResultChunk 任务结果分块，按顺序拼接即为 JSON 编码的完整结果
+type TaskResultChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	TotalSize     int64                  `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`      // 结果总字节数，仅首个分块设置
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // 结果的内容类型，仅首个分块设置
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskResultChunk) Reset() {
+	*x = TaskResultChunk{}
+	mi := &file_proto_task_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskResultChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskResultChunk) ProtoMessage() {}
+
+func (x *TaskResultChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskResultChunk.ProtoReflect.Descriptor instead.
+func (*TaskResultChunk) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TaskResultChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *TaskResultChunk) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *TaskResultChunk) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// RetryTaskRequest 手动重试任务请求
+type RetryTaskRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TaskId          string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	ResetRetryCount bool                   `protobuf:"varint,2,opt,name=reset_retry_count,json=resetRetryCount,proto3" json:"reset_retry_count,omitempty"` // 为 true 时重试次数清零
+	Priority        *int32                 `protobuf:"varint,3,opt,name=priority,proto3,oneof" json:"priority,omitempty"`                                  // 可选：重新入队的优先级，不传表示保持原优先级
+	TriggeredBy     string                 `protobuf:"bytes,4,opt,name=triggered_by,json=triggeredBy,proto3" json:"triggered_by,omitempty"`                // 触发重试的用户，记录在重试日志中
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RetryTaskRequest) Reset() {
+	*x = RetryTaskRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryTaskRequest) ProtoMessage() {}
+
+func (x *RetryTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryTaskRequest.ProtoReflect.Descriptor instead.
+func (*RetryTaskRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RetryTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *RetryTaskRequest) GetResetRetryCount() bool {
+	if x != nil {
+		return x.ResetRetryCount
+	}
+	return false
+}
+
+func (x *RetryTaskRequest) GetPriority() int32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
+func (x *RetryTaskRequest) GetTriggeredBy() string {
+	if x != nil {
+		return x.TriggeredBy
+	}
+	return ""
+}
+
+// RetryTaskResponse 手动重试任务响应
+type RetryTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryTaskResponse) Reset() {
+	*x = RetryTaskResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryTaskResponse) ProtoMessage() {}
+
+func (x *RetryTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryTaskResponse.ProtoReflect.Descriptor instead.
+func (*RetryTaskResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RetryTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// RetryTasksRequest 批量手动重试任务请求
+type RetryTasksRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Status          string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`                                    // 必填：FAILED、TIMEOUT 或 CANCELLED
+	TaskType        string                 `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`                // 可选：按任务类型过滤
+	WorkerId        string                 `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`                // 可选：按 Worker 过滤
+	CreatedAfter    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`    // 可选：创建时间下界（含）
+	CreatedBefore   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"` // 可选：创建时间上界（不含）
+	ResetRetryCount bool                   `protobuf:"varint,6,opt,name=reset_retry_count,json=resetRetryCount,proto3" json:"reset_retry_count,omitempty"`
+	Priority        *int32                 `protobuf:"varint,7,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
+	TriggeredBy     string                 `protobuf:"bytes,8,opt,name=triggered_by,json=triggeredBy,proto3" json:"triggered_by,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RetryTasksRequest) Reset() {
+	*x = RetryTasksRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryTasksRequest) ProtoMessage() {}
+
+func (x *RetryTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryTasksRequest.ProtoReflect.Descriptor instead.
+func (*RetryTasksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RetryTasksRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RetryTasksRequest) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *RetryTasksRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *RetryTasksRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *RetryTasksRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *RetryTasksRequest) GetResetRetryCount() bool {
+	if x != nil {
+		return x.ResetRetryCount
+	}
+	return false
+}
+
+func (x *RetryTasksRequest) GetPriority() int32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
+func (x *RetryTasksRequest) GetTriggeredBy() string {
+	if x != nil {
+		return x.TriggeredBy
+	}
+	return ""
+}
+
+// RetryTasksResponse 批量手动重试任务响应
+type RetryTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Retried       int32                  `protobuf:"varint,1,opt,name=retried,proto3" json:"retried,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryTasksResponse) Reset() {
+	*x = RetryTasksResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryTasksResponse) ProtoMessage() {}
+
+func (x *RetryTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryTasksResponse.ProtoReflect.Descriptor instead.
+func (*RetryTasksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RetryTasksResponse) GetRetried() int32 {
+	if x != nil {
+		return x.Retried
+	}
+	return 0
+}
+
+// Task 任务信息
+type Task struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TaskId         string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TaskType       string                 `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Priority       int32                  `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	Payload        map[string]string      `protobuf:"bytes,5,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 兼容旧客户端：非字符串的值编码为 JSON 字符串
+	Result         map[string]string      `protobuf:"bytes,6,rep,name=result,proto3" json:"result,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	WorkerId       string                 `protobuf:"bytes,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	RetryCount     int32                  `protobuf:"varint,8,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	MaxRetry       int32                  `protobuf:"varint,9,opt,name=max_retry,json=maxRetry,proto3" json:"max_retry,omitempty"`
+	ErrorMessage   string                 `protobuf:"bytes,10,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt    *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	ScheduledAt    *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,15,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Tenant         string                 `protobuf:"bytes,16,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	ResultUri      string                 `protobuf:"bytes,17,opt,name=result_uri,json=resultUri,proto3" json:"result_uri,omitempty"`             // 结果超过阈值转存到结果存储时的地址，此时 result 为空，需通过 GetTaskResult 读取
+	ResultSize     int64                  `protobuf:"varint,18,opt,name=result_size,json=resultSize,proto3" json:"result_size,omitempty"`         // 转存结果的字节数
+	PayloadStruct  *structpb.Struct       `protobuf:"bytes,19,opt,name=payload_struct,json=payloadStruct,proto3" json:"payload_struct,omitempty"` // 类型化 payload，数字以 double 表示
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_proto_task_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Task) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *Task) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *Task) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Task) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Task) GetPayload() map[string]string {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Task) GetResult() map[string]string {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Task) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *Task) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *Task) GetMaxRetry() int32 {
+	if x != nil {
+		return x.MaxRetry
+	}
+	return 0
+}
+
+func (x *Task) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Task) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Task) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *Task) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *Task) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *Task) GetResultUri() string {
+	if x != nil {
+		return x.ResultUri
+	}
+	return ""
+}
+
+func (x *Task) GetResultSize() int64 {
+	if x != nil {
+		return x.ResultSize
+	}
+	return 0
+}
+
+func (x *Task) GetPayloadStruct() *structpb.Struct {
+	if x != nil {
+		return x.PayloadStruct
+	}
+	return nil
+}
+
+// TaskLog 任务日志
+type TaskLog struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LogId         string                 `protobuf:"bytes,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	LogType       string                 `protobuf:"bytes,3,opt,name=log_type,json=logType,proto3" json:"log_type,omitempty"`
+	FromStatus    string                 `protobuf:"bytes,4,opt,name=from_status,json=fromStatus,proto3" json:"from_status,omitempty"`
+	ToStatus      string                 `protobuf:"bytes,5,opt,name=to_status,json=toStatus,proto3" json:"to_status,omitempty"`
+	Message       string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskLog) Reset() {
+	*x = TaskLog{}
+	mi := &file_proto_task_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskLog) ProtoMessage() {}
+
+func (x *TaskLog) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskLog.ProtoReflect.Descriptor instead.
+func (*TaskLog) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *TaskLog) GetLogId() string {
+	if x != nil {
+		return x.LogId
+	}
+	return ""
+}
+
+func (x *TaskLog) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskLog) GetLogType() string {
+	if x != nil {
+		return x.LogType
+	}
+	return ""
+}
+
+func (x *TaskLog) GetFromStatus() string {
+	if x != nil {
+		return x.FromStatus
+	}
+	return ""
+}
+
+func (x *TaskLog) GetToStatus() string {
+	if x != nil {
+		return x.ToStatus
+	}
+	return ""
+}
+
+func (x *TaskLog) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TaskLog) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *TaskLog) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// WorkflowNodeSpec 工作流节点定义
+type WorkflowNodeSpec struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"` // 工作流内唯一
+	TaskType      string                 `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	Priority      int32                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"` // 0-9，数值越大越优先（0=Normal, 1=High）
+	Payload       map[string]string      `protobuf:"bytes,4,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DependsOn     []string               `protobuf:"bytes,5,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`             // 上游节点ID
+	PayloadStruct *structpb.Struct       `protobuf:"bytes,6,opt,name=payload_struct,json=payloadStruct,proto3" json:"payload_struct,omitempty"` // 类型化 payload，与 payload 互斥
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowNodeSpec) Reset() {
+	*x = WorkflowNodeSpec{}
+	mi := &file_proto_task_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowNodeSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowNodeSpec) ProtoMessage() {}
+
+func (x *WorkflowNodeSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowNodeSpec.ProtoReflect.Descriptor instead.
+func (*WorkflowNodeSpec) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WorkflowNodeSpec) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *WorkflowNodeSpec) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *WorkflowNodeSpec) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *WorkflowNodeSpec) GetPayload() map[string]string {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *WorkflowNodeSpec) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *WorkflowNodeSpec) GetPayloadStruct() *structpb.Struct {
+	if x != nil {
+		return x.PayloadStruct
+	}
+	return nil
+}
+
+// CreateWorkflowRequest 创建工作流请求
+type CreateWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FailurePolicy string                 `protobuf:"bytes,2,opt,name=failure_policy,json=failurePolicy,proto3" json:"failure_policy,omitempty"` // FAIL_FAST（默认）、CONTINUE、SKIP_DESCENDANTS
+	Nodes         []*WorkflowNodeSpec    `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWorkflowRequest) Reset() {
+	*x = CreateWorkflowRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkflowRequest) ProtoMessage() {}
+
+func (x *CreateWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*CreateWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CreateWorkflowRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateWorkflowRequest) GetFailurePolicy() string {
+	if x != nil {
+		return x.FailurePolicy
+	}
+	return ""
+}
+
+func (x *CreateWorkflowRequest) GetNodes() []*WorkflowNodeSpec {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+// CreateWorkflowResponse 创建工作流响应
+type CreateWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workflow      *Workflow              `protobuf:"bytes,1,opt,name=workflow,proto3" json:"workflow,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWorkflowResponse) Reset() {
+	*x = CreateWorkflowResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkflowResponse) ProtoMessage() {}
+
+func (x *CreateWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*CreateWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CreateWorkflowResponse) GetWorkflow() *Workflow {
+	if x != nil {
+		return x.Workflow
+	}
+	return nil
+}
+
+// GetWorkflowRequest 查询工作流请求
+type GetWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkflowRequest) Reset() {
+	*x = GetWorkflowRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkflowRequest) ProtoMessage() {}
+
+func (x *GetWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*GetWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetWorkflowRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+// GetWorkflowResponse 查询工作流响应
+type GetWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workflow      *Workflow              `protobuf:"bytes,1,opt,name=workflow,proto3" json:"workflow,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkflowResponse) Reset() {
+	*x = GetWorkflowResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkflowResponse) ProtoMessage() {}
+
+func (x *GetWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*GetWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetWorkflowResponse) GetWorkflow() *Workflow {
+	if x != nil {
+		return x.Workflow
+	}
+	return nil
+}
+
+// Workflow 工作流信息
+type Workflow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // RUNNING、SUCCESS、FAILED
+	FailurePolicy string                 `protobuf:"bytes,4,opt,name=failure_policy,json=failurePolicy,proto3" json:"failure_policy,omitempty"`
+	Nodes         []*WorkflowNode        `protobuf:"bytes,5,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Workflow) Reset() {
+	*x = Workflow{}
+	mi := &file_proto_task_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Workflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow) ProtoMessage() {}
+
+func (x *Workflow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow.ProtoReflect.Descriptor instead.
+func (*Workflow) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Workflow) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *Workflow) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Workflow) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Workflow) GetFailurePolicy() string {
+	if x != nil {
+		return x.FailurePolicy
+	}
+	return ""
+}
+
+func (x *Workflow) GetNodes() []*WorkflowNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *Workflow) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Workflow) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+// WorkflowNode 工作流节点信息
+type WorkflowNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	TaskType      string                 `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	Priority      int32                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	DependsOn     []string               `protobuf:"bytes,4,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`               // WAITING、RUNNING、SUCCESS、FAILED、SKIPPED
+	TaskId        string                 `protobuf:"bytes,6,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"` // 节点提交任务后才有值
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowNode) Reset() {
+	*x = WorkflowNode{}
+	mi := &file_proto_task_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowNode) ProtoMessage() {}
+
+func (x *WorkflowNode) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowNode.ProtoReflect.Descriptor instead.
+func (*WorkflowNode) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *WorkflowNode) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *WorkflowNode) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *WorkflowNode) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *WorkflowNode) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *WorkflowNode) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkflowNode) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// DeadLetter 死信记录
+type DeadLetter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TaskType      string                 `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	RetryCount    int32                  `protobuf:"varint,4,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,5,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	DeadAt        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=dead_at,json=deadAt,proto3" json:"dead_at,omitempty"` // 进入死信队列的时间
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_proto_task_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeadLetter) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetDeadAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeadAt
+	}
+	return nil
+}
+
+// DeadLetterFilter 死信过滤条件，字段之间为与关系
+type DeadLetterFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskType      string                 `protobuf:"bytes,1,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`                // 可选：按任务类型过滤
+	ErrorContains string                 `protobuf:"bytes,2,opt,name=error_contains,json=errorContains,proto3" json:"error_contains,omitempty"` // 可选：错误信息包含的子串
+	TaskIds       []string               `protobuf:"bytes,3,rep,name=task_ids,json=taskIds,proto3" json:"task_ids,omitempty"`                   // 可选：只匹配指定任务
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetterFilter) Reset() {
+	*x = DeadLetterFilter{}
+	mi := &file_proto_task_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetterFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetterFilter) ProtoMessage() {}
+
+func (x *DeadLetterFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetterFilter.ProtoReflect.Descriptor instead.
+func (*DeadLetterFilter) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeadLetterFilter) GetTaskType() string {
+	if x != nil {
 		return x.TaskType
 	}
-	return ""
+	return ""
+}
+
+func (x *DeadLetterFilter) GetErrorContains() string {
+	if x != nil {
+		return x.ErrorContains
+	}
+	return ""
+}
+
+func (x *DeadLetterFilter) GetTaskIds() []string {
+	if x != nil {
+		return x.TaskIds
+	}
+	return nil
+}
+
+// ListDeadLettersRequest 列出死信请求
+type ListDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *DeadLetterFilter      `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // 默认 20，最大 100
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // 上一页返回的 next_page_token，为空表示第一页
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListDeadLettersRequest) GetFilter() *DeadLetterFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListDeadLettersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDeadLettersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// ListDeadLettersResponse 列出死信响应
+type ListDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetters   []*DeadLetter          `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                       // 满足过滤条件的死信总数
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // 为空表示没有下一页
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersResponse) ProtoMessage() {}
+
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListDeadLettersResponse) GetDeadLetters() []*DeadLetter {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+func (x *ListDeadLettersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListDeadLettersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// GetDeadLetterRequest 查看死信请求
+type GetDeadLetterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeadLetterRequest) Reset() {
+	*x = GetDeadLetterRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeadLetterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeadLetterRequest) ProtoMessage() {}
+
+func (x *GetDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (x *Task) GetStatus() string {
+// Deprecated: Use GetDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*GetDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetDeadLetterRequest) GetTaskId() string {
 	if x != nil {
-		return x.Status
+		return x.TaskId
 	}
 	return ""
 }
 
-func (x *Task) GetPriority() int32 {
+// GetDeadLetterResponse 查看死信响应
+type GetDeadLetterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetter    *DeadLetter            `protobuf:"bytes,1,opt,name=dead_letter,json=deadLetter,proto3" json:"dead_letter,omitempty"`
+	Task          *Task                  `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeadLetterResponse) Reset() {
+	*x = GetDeadLetterResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeadLetterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeadLetterResponse) ProtoMessage() {}
+
+func (x *GetDeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[36]
 	if x != nil {
-		return x.Priority
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *Task) GetPayload() map[string]string {
+// Deprecated: Use GetDeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*GetDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetDeadLetterResponse) GetDeadLetter() *DeadLetter {
 	if x != nil {
-		return x.Payload
+		return x.DeadLetter
 	}
 	return nil
 }
 
-func (x *Task) GetResult() map[string]string {
+func (x *GetDeadLetterResponse) GetTask() *Task {
 	if x != nil {
-		return x.Result
+		return x.Task
 	}
 	return nil
 }
 
-func (x *Task) GetWorkerId() string {
+// RequeueDeadLetterRequest 重新入队单个死信任务请求
+type RequeueDeadLetterRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TaskId         string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	ReplacePayload bool                   `protobuf:"varint,2,opt,name=replace_payload,json=replacePayload,proto3" json:"replace_payload,omitempty"` // 为 true 时使用 payload 或 payload_struct 替换原任务参数
+	Payload        map[string]string      `protobuf:"bytes,3,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PayloadStruct  *structpb.Struct       `protobuf:"bytes,4,opt,name=payload_struct,json=payloadStruct,proto3" json:"payload_struct,omitempty"` // 类型化 payload，与 payload 互斥
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RequeueDeadLetterRequest) Reset() {
+	*x = RequeueDeadLetterRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadLetterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadLetterRequest) ProtoMessage() {}
+
+func (x *RequeueDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[37]
 	if x != nil {
-		return x.WorkerId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *Task) GetRetryCount() int32 {
+// Deprecated: Use RequeueDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RequeueDeadLetterRequest) GetTaskId() string {
 	if x != nil {
-		return x.RetryCount
+		return x.TaskId
 	}
-	return 0
+	return ""
 }
 
-func (x *Task) GetMaxRetry() int32 {
+func (x *RequeueDeadLetterRequest) GetReplacePayload() bool {
 	if x != nil {
-		return x.MaxRetry
+		return x.ReplacePayload
 	}
-	return 0
+	return false
 }
 
-func (x *Task) GetErrorMessage() string {
+func (x *RequeueDeadLetterRequest) GetPayload() map[string]string {
 	if x != nil {
-		return x.ErrorMessage
+		return x.Payload
 	}
-	return ""
+	return nil
 }
 
-func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+func (x *RequeueDeadLetterRequest) GetPayloadStruct() *structpb.Struct {
 	if x != nil {
-		return x.CreatedAt
+		return x.PayloadStruct
 	}
 	return nil
 }
 
-func (x *Task) GetStartedAt() *timestamppb.Timestamp {
+// RequeueDeadLetterResponse 重新入队单个死信任务响应
+type RequeueDeadLetterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadLetterResponse) Reset() {
+	*x = RequeueDeadLetterResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadLetterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadLetterResponse) ProtoMessage() {}
+
+func (x *RequeueDeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[38]
 	if x != nil {
-		return x.StartedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *Task) GetCompletedAt() *timestamppb.Timestamp {
+// Deprecated: Use RequeueDeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RequeueDeadLetterResponse) GetTask() *Task {
 	if x != nil {
-		return x.CompletedAt
+		return x.Task
 	}
 	return nil
 }
 
-// TaskLog 任务日志
-type TaskLog struct {
+// RequeueDeadLettersRequest 批量重新入队死信任务请求
+type RequeueDeadLettersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LogId         string                 `protobuf:"bytes,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
-	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	LogType       string                 `protobuf:"bytes,3,opt,name=log_type,json=logType,proto3" json:"log_type,omitempty"`
-	FromStatus    string                 `protobuf:"bytes,4,opt,name=from_status,json=fromStatus,proto3" json:"from_status,omitempty"`
-	ToStatus      string                 `protobuf:"bytes,5,opt,name=to_status,json=toStatus,proto3" json:"to_status,omitempty"`
-	Message       string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
-	WorkerId      string                 `protobuf:"bytes,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Filter        *DeadLetterFilter      `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"` // 过滤条件为空时必须显式指定，防止误操作
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TaskLog) Reset() {
-	*x = TaskLog{}
-	mi := &file_proto_task_service_proto_msgTypes[11]
+func (x *RequeueDeadLettersRequest) Reset() {
+	*x = RequeueDeadLettersRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskLog) String() string {
+func (x *RequeueDeadLettersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskLog) ProtoMessage() {}
+func (*RequeueDeadLettersRequest) ProtoMessage() {}
 
-func (x *TaskLog) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_service_proto_msgTypes[11]
+func (x *RequeueDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -709,81 +2644,198 @@ func (x *TaskLog) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskLog.ProtoReflect.Descriptor instead.
-func (*TaskLog) Descriptor() ([]byte, []int) {
-	return file_proto_task_service_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use RequeueDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *TaskLog) GetLogId() string {
+func (x *RequeueDeadLettersRequest) GetFilter() *DeadLetterFilter {
 	if x != nil {
-		return x.LogId
+		return x.Filter
 	}
-	return ""
+	return nil
 }
 
-func (x *TaskLog) GetTaskId() string {
+func (x *RequeueDeadLettersRequest) GetAll() bool {
 	if x != nil {
-		return x.TaskId
+		return x.All
 	}
-	return ""
+	return false
 }
 
-func (x *TaskLog) GetLogType() string {
+// RequeueDeadLettersResponse 批量重新入队死信任务响应
+type RequeueDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requeued      int32                  `protobuf:"varint,1,opt,name=requeued,proto3" json:"requeued,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadLettersResponse) Reset() {
+	*x = RequeueDeadLettersResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadLettersResponse) ProtoMessage() {}
+
+func (x *RequeueDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[40]
 	if x != nil {
-		return x.LogType
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *TaskLog) GetFromStatus() string {
+// Deprecated: Use RequeueDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RequeueDeadLettersResponse) GetRequeued() int32 {
 	if x != nil {
-		return x.FromStatus
+		return x.Requeued
 	}
-	return ""
+	return 0
 }
 
-func (x *TaskLog) GetToStatus() string {
+// PurgeDeadLettersRequest 清除死信请求，任务本身保留为失败状态
+type PurgeDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *DeadLetterFilter      `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"` // 过滤条件为空时必须显式指定，防止误操作
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDeadLettersRequest) Reset() {
+	*x = PurgeDeadLettersRequest{}
+	mi := &file_proto_task_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeadLettersRequest) ProtoMessage() {}
+
+func (x *PurgeDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[41]
 	if x != nil {
-		return x.ToStatus
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *TaskLog) GetMessage() string {
+// Deprecated: Use PurgeDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*PurgeDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *PurgeDeadLettersRequest) GetFilter() *DeadLetterFilter {
 	if x != nil {
-		return x.Message
+		return x.Filter
 	}
-	return ""
+	return nil
 }
 
-func (x *TaskLog) GetWorkerId() string {
+func (x *PurgeDeadLettersRequest) GetAll() bool {
 	if x != nil {
-		return x.WorkerId
+		return x.All
 	}
-	return ""
+	return false
 }
 
-func (x *TaskLog) GetCreatedAt() *timestamppb.Timestamp {
+// PurgeDeadLettersResponse 清除死信响应
+type PurgeDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Purged        int64                  `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDeadLettersResponse) Reset() {
+	*x = PurgeDeadLettersResponse{}
+	mi := &file_proto_task_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeadLettersResponse) ProtoMessage() {}
+
+func (x *PurgeDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_service_proto_msgTypes[42]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*PurgeDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PurgeDeadLettersResponse) GetPurged() int64 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
 }
 
 var File_proto_task_service_proto protoreflect.FileDescriptor
 
 const file_proto_task_service_proto_rawDesc = "" +
 	"\n" +
-	"\x18proto/task_service.proto\x12\vtaskservice\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcf\x01\n" +
+	"\x18proto/task_service.proto\x12\vtaskservice\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb4\x03\n" +
 	"\x11CreateTaskRequest\x12\x1b\n" +
 	"\ttask_type\x18\x01 \x01(\tR\btaskType\x12\x1a\n" +
 	"\bpriority\x18\x02 \x01(\x05R\bpriority\x12E\n" +
-	"\apayload\x18\x03 \x03(\v2+.taskservice.CreateTaskRequest.PayloadEntryR\apayload\x1a:\n" +
+	"\apayload\x18\x03 \x03(\v2+.taskservice.CreateTaskRequest.PayloadEntryR\apayload\x12=\n" +
+	"\fscheduled_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12#\n" +
+	"\rdelay_seconds\x18\x05 \x01(\x03R\fdelaySeconds\x12'\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\x12\x16\n" +
+	"\x06tenant\x18\a \x01(\tR\x06tenant\x12>\n" +
+	"\x0epayload_struct\x18\b \x01(\v2\x17.google.protobuf.StructR\rpayloadStruct\x1a:\n" +
 	"\fPayloadEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\";\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"Y\n" +
 	"\x12CreateTaskResponse\x12%\n" +
-	"\x04task\x18\x01 \x01(\v2\x11.taskservice.TaskR\x04task\")\n" +
+	"\x04task\x18\x01 \x01(\v2\x11.taskservice.TaskR\x04task\x12\x1c\n" +
+	"\tduplicate\x18\x02 \x01(\bR\tduplicate\"J\n" +
+	"\x12CreateTasksRequest\x124\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x1e.taskservice.CreateTaskRequestR\x05tasks\"m\n" +
+	"\x10CreateTaskResult\x12%\n" +
+	"\x04task\x18\x01 \x01(\v2\x11.taskservice.TaskR\x04task\x12\x1c\n" +
+	"\tduplicate\x18\x02 \x01(\bR\tduplicate\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x80\x01\n" +
+	"\x13CreateTasksResponse\x127\n" +
+	"\aresults\x18\x01 \x03(\v2\x1d.taskservice.CreateTaskResultR\aresults\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x05R\acreated\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\")\n" +
 	"\x0eGetTaskRequest\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\"8\n" +
 	"\x0fGetTaskResponse\x12%\n" +
@@ -796,15 +2848,73 @@ const file_proto_task_service_proto_rawDesc = "" +
 	"\x12GetTaskLogsRequest\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\"?\n" +
 	"\x13GetTaskLogsResponse\x12(\n" +
-	"\x04logs\x18\x01 \x03(\v2\x14.taskservice.TaskLogR\x04logs\"w\n" +
+	"\x04logs\x18\x01 \x03(\v2\x14.taskservice.TaskLogR\x04logs\"\xfe\x02\n" +
 	"\x10ListTasksRequest\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1a\n" +
-	"\bpriority\x18\x02 \x01(\x05R\bpriority\x12\x12\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1f\n" +
+	"\bpriority\x18\x02 \x01(\x05H\x00R\bpriority\x88\x01\x01\x12\x12\n" +
 	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"R\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\ttask_type\x18\x06 \x01(\tR\btaskType\x12\x1b\n" +
+	"\tworker_id\x18\a \x01(\tR\bworkerId\x12?\n" +
+	"\rcreated_after\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x16\n" +
+	"\x06tenant\x18\n" +
+	" \x01(\tR\x06tenantB\v\n" +
+	"\t_priority\"z\n" +
 	"\x11ListTasksResponse\x12'\n" +
 	"\x05tasks\x18\x01 \x03(\v2\x11.taskservice.TaskR\x05tasks\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"\x8d\x05\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"+\n" +
+	"\x10WatchTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"N\n" +
+	"\x11WatchTasksRequest\x12\x1d\n" +
+	"\n" +
+	"task_types\x18\x01 \x03(\tR\ttaskTypes\x12\x1a\n" +
+	"\bstatuses\x18\x02 \x03(\tR\bstatuses\"\xcb\x02\n" +
+	"\tTaskEvent\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\ttask_type\x18\x02 \x01(\tR\btaskType\x12\x19\n" +
+	"\blog_type\x18\x03 \x01(\tR\alogType\x12\x1f\n" +
+	"\vfrom_status\x18\x04 \x01(\tR\n" +
+	"fromStatus\x12\x1b\n" +
+	"\tto_status\x18\x05 \x01(\tR\btoStatus\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\x12\x1b\n" +
+	"\tworker_id\x18\a \x01(\tR\bworkerId\x12\x1f\n" +
+	"\vretry_count\x18\b \x01(\x05R\n" +
+	"retryCount\x12;\n" +
+	"\voccurred_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x1a\n" +
+	"\bsnapshot\x18\n" +
+	" \x01(\bR\bsnapshot\"/\n" +
+	"\x14GetTaskResultRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"g\n" +
+	"\x0fTaskResultChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\x03R\ttotalSize\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"\xa8\x01\n" +
+	"\x10RetryTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12*\n" +
+	"\x11reset_retry_count\x18\x02 \x01(\bR\x0fresetRetryCount\x12\x1f\n" +
+	"\bpriority\x18\x03 \x01(\x05H\x00R\bpriority\x88\x01\x01\x12!\n" +
+	"\ftriggered_by\x18\x04 \x01(\tR\vtriggeredByB\v\n" +
+	"\t_priority\":\n" +
+	"\x11RetryTaskResponse\x12%\n" +
+	"\x04task\x18\x01 \x01(\v2\x11.taskservice.TaskR\x04task\"\xe6\x02\n" +
+	"\x11RetryTasksRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1b\n" +
+	"\ttask_type\x18\x02 \x01(\tR\btaskType\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\tR\bworkerId\x12?\n" +
+	"\rcreated_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12*\n" +
+	"\x11reset_retry_count\x18\x06 \x01(\bR\x0fresetRetryCount\x12\x1f\n" +
+	"\bpriority\x18\a \x01(\x05H\x00R\bpriority\x88\x01\x01\x12!\n" +
+	"\ftriggered_by\x18\b \x01(\tR\vtriggeredByB\v\n" +
+	"\t_priority\".\n" +
+	"\x12RetryTasksResponse\x12\x18\n" +
+	"\aretried\x18\x01 \x01(\x05R\aretried\"\x8d\a\n" +
 	"\x04Task\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
 	"\ttask_type\x18\x02 \x01(\tR\btaskType\x12\x16\n" +
@@ -822,7 +2932,15 @@ const file_proto_task_service_proto_rawDesc = "" +
 	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
 	"started_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
-	"\fcompleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x1a:\n" +
+	"\fcompleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12=\n" +
+	"\fscheduled_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12'\n" +
+	"\x0fidempotency_key\x18\x0f \x01(\tR\x0eidempotencyKey\x12\x16\n" +
+	"\x06tenant\x18\x10 \x01(\tR\x06tenant\x12\x1d\n" +
+	"\n" +
+	"result_uri\x18\x11 \x01(\tR\tresultUri\x12\x1f\n" +
+	"\vresult_size\x18\x12 \x01(\x03R\n" +
+	"resultSize\x12>\n" +
+	"\x0epayload_struct\x18\x13 \x01(\v2\x17.google.protobuf.StructR\rpayloadStruct\x1a:\n" +
 	"\fPayloadEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a9\n" +
@@ -839,15 +2957,118 @@ const file_proto_task_service_proto_rawDesc = "" +
 	"\amessage\x18\x06 \x01(\tR\amessage\x12\x1b\n" +
 	"\tworker_id\x18\a \x01(\tR\bworkerId\x129\n" +
 	"\n" +
-	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\x8f\x03\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xc5\x02\n" +
+	"\x10WorkflowNodeSpec\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1b\n" +
+	"\ttask_type\x18\x02 \x01(\tR\btaskType\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\x05R\bpriority\x12D\n" +
+	"\apayload\x18\x04 \x03(\v2*.taskservice.WorkflowNodeSpec.PayloadEntryR\apayload\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x05 \x03(\tR\tdependsOn\x12>\n" +
+	"\x0epayload_struct\x18\x06 \x01(\v2\x17.google.protobuf.StructR\rpayloadStruct\x1a:\n" +
+	"\fPayloadEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x87\x01\n" +
+	"\x15CreateWorkflowRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12%\n" +
+	"\x0efailure_policy\x18\x02 \x01(\tR\rfailurePolicy\x123\n" +
+	"\x05nodes\x18\x03 \x03(\v2\x1d.taskservice.WorkflowNodeSpecR\x05nodes\"K\n" +
+	"\x16CreateWorkflowResponse\x121\n" +
+	"\bworkflow\x18\x01 \x01(\v2\x15.taskservice.WorkflowR\bworkflow\"5\n" +
+	"\x12GetWorkflowRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\"H\n" +
+	"\x13GetWorkflowResponse\x121\n" +
+	"\bworkflow\x18\x01 \x01(\v2\x15.taskservice.WorkflowR\bworkflow\"\xa9\x02\n" +
+	"\bWorkflow\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12%\n" +
+	"\x0efailure_policy\x18\x04 \x01(\tR\rfailurePolicy\x12/\n" +
+	"\x05nodes\x18\x05 \x03(\v2\x19.taskservice.WorkflowNodeR\x05nodes\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fcompleted_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\xb0\x01\n" +
+	"\fWorkflowNode\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1b\n" +
+	"\ttask_type\x18\x02 \x01(\tR\btaskType\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\x05R\bpriority\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x04 \x03(\tR\tdependsOn\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x17\n" +
+	"\atask_id\x18\x06 \x01(\tR\x06taskId\"\xda\x01\n" +
+	"\n" +
+	"DeadLetter\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\ttask_type\x18\x02 \x01(\tR\btaskType\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\x12\x1f\n" +
+	"\vretry_count\x18\x04 \x01(\x05R\n" +
+	"retryCount\x12\x1b\n" +
+	"\tworker_id\x18\x05 \x01(\tR\bworkerId\x123\n" +
+	"\adead_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x06deadAt\"q\n" +
+	"\x10DeadLetterFilter\x12\x1b\n" +
+	"\ttask_type\x18\x01 \x01(\tR\btaskType\x12%\n" +
+	"\x0eerror_contains\x18\x02 \x01(\tR\rerrorContains\x12\x19\n" +
+	"\btask_ids\x18\x03 \x03(\tR\ataskIds\"\x8b\x01\n" +
+	"\x16ListDeadLettersRequest\x125\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1d.taskservice.DeadLetterFilterR\x06filter\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"\x93\x01\n" +
+	"\x17ListDeadLettersResponse\x12:\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\x17.taskservice.DeadLetterR\vdeadLetters\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"/\n" +
+	"\x14GetDeadLetterRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"x\n" +
+	"\x15GetDeadLetterResponse\x128\n" +
+	"\vdead_letter\x18\x01 \x01(\v2\x17.taskservice.DeadLetterR\n" +
+	"deadLetter\x12%\n" +
+	"\x04task\x18\x02 \x01(\v2\x11.taskservice.TaskR\x04task\"\xa6\x02\n" +
+	"\x18RequeueDeadLetterRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12'\n" +
+	"\x0freplace_payload\x18\x02 \x01(\bR\x0ereplacePayload\x12L\n" +
+	"\apayload\x18\x03 \x03(\v22.taskservice.RequeueDeadLetterRequest.PayloadEntryR\apayload\x12>\n" +
+	"\x0epayload_struct\x18\x04 \x01(\v2\x17.google.protobuf.StructR\rpayloadStruct\x1a:\n" +
+	"\fPayloadEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"B\n" +
+	"\x19RequeueDeadLetterResponse\x12%\n" +
+	"\x04task\x18\x01 \x01(\v2\x11.taskservice.TaskR\x04task\"d\n" +
+	"\x19RequeueDeadLettersRequest\x125\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1d.taskservice.DeadLetterFilterR\x06filter\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\"8\n" +
+	"\x1aRequeueDeadLettersResponse\x12\x1a\n" +
+	"\brequeued\x18\x01 \x01(\x05R\brequeued\"b\n" +
+	"\x17PurgeDeadLettersRequest\x125\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1d.taskservice.DeadLetterFilterR\x06filter\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\"2\n" +
+	"\x18PurgeDeadLettersResponse\x12\x16\n" +
+	"\x06purged\x18\x01 \x01(\x03R\x06purged2\xed\v\n" +
 	"\vTaskService\x12M\n" +
 	"\n" +
-	"CreateTask\x12\x1e.taskservice.CreateTaskRequest\x1a\x1f.taskservice.CreateTaskResponse\x12D\n" +
+	"CreateTask\x12\x1e.taskservice.CreateTaskRequest\x1a\x1f.taskservice.CreateTaskResponse\x12P\n" +
+	"\vCreateTasks\x12\x1f.taskservice.CreateTasksRequest\x1a .taskservice.CreateTasksResponse\x12D\n" +
 	"\aGetTask\x12\x1b.taskservice.GetTaskRequest\x1a\x1c.taskservice.GetTaskResponse\x12M\n" +
 	"\n" +
 	"CancelTask\x12\x1e.taskservice.CancelTaskRequest\x1a\x1f.taskservice.CancelTaskResponse\x12P\n" +
 	"\vGetTaskLogs\x12\x1f.taskservice.GetTaskLogsRequest\x1a .taskservice.GetTaskLogsResponse\x12J\n" +
-	"\tListTasks\x12\x1d.taskservice.ListTasksRequest\x1a\x1e.taskservice.ListTasksResponseB/Z-bamboo/cmd/asynctaskmanager/proto;taskserviceb\x06proto3"
+	"\tListTasks\x12\x1d.taskservice.ListTasksRequest\x1a\x1e.taskservice.ListTasksResponse\x12D\n" +
+	"\tWatchTask\x12\x1d.taskservice.WatchTaskRequest\x1a\x16.taskservice.TaskEvent0\x01\x12F\n" +
+	"\n" +
+	"WatchTasks\x12\x1e.taskservice.WatchTasksRequest\x1a\x16.taskservice.TaskEvent0\x01\x12R\n" +
+	"\rGetTaskResult\x12!.taskservice.GetTaskResultRequest\x1a\x1c.taskservice.TaskResultChunk0\x01\x12J\n" +
+	"\tRetryTask\x12\x1d.taskservice.RetryTaskRequest\x1a\x1e.taskservice.RetryTaskResponse\x12M\n" +
+	"\n" +
+	"RetryTasks\x12\x1e.taskservice.RetryTasksRequest\x1a\x1f.taskservice.RetryTasksResponse\x12Y\n" +
+	"\x0eCreateWorkflow\x12\".taskservice.CreateWorkflowRequest\x1a#.taskservice.CreateWorkflowResponse\x12P\n" +
+	"\vGetWorkflow\x12\x1f.taskservice.GetWorkflowRequest\x1a .taskservice.GetWorkflowResponse\x12\\\n" +
+	"\x0fListDeadLetters\x12#.taskservice.ListDeadLettersRequest\x1a$.taskservice.ListDeadLettersResponse\x12V\n" +
+	"\rGetDeadLetter\x12!.taskservice.GetDeadLetterRequest\x1a\".taskservice.GetDeadLetterResponse\x12b\n" +
+	"\x11RequeueDeadLetter\x12%.taskservice.RequeueDeadLetterRequest\x1a&.taskservice.RequeueDeadLetterResponse\x12e\n" +
+	"\x12RequeueDeadLetters\x12&.taskservice.RequeueDeadLettersRequest\x1a'.taskservice.RequeueDeadLettersResponse\x12_\n" +
+	"\x10PurgeDeadLetters\x12$.taskservice.PurgeDeadLettersRequest\x1a%.taskservice.PurgeDeadLettersResponseB/Z-bamboo/cmd/asynctaskmanager/proto;taskserviceb\x06proto3"
 
 var (
 	file_proto_task_service_proto_rawDescOnce sync.Once
@@ -861,52 +3082,143 @@ func file_proto_task_service_proto_rawDescGZIP() []byte {
 	return file_proto_task_service_proto_rawDescData
 }
 
-var file_proto_task_service_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_proto_task_service_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
 var file_proto_task_service_proto_goTypes = []any{
-	(*CreateTaskRequest)(nil),     // 0: taskservice.CreateTaskRequest
-	(*CreateTaskResponse)(nil),    // 1: taskservice.CreateTaskResponse
-	(*GetTaskRequest)(nil),        // 2: taskservice.GetTaskRequest
-	(*GetTaskResponse)(nil),       // 3: taskservice.GetTaskResponse
-	(*CancelTaskRequest)(nil),     // 4: taskservice.CancelTaskRequest
-	(*CancelTaskResponse)(nil),    // 5: taskservice.CancelTaskResponse
-	(*GetTaskLogsRequest)(nil),    // 6: taskservice.GetTaskLogsRequest
-	(*GetTaskLogsResponse)(nil),   // 7: taskservice.GetTaskLogsResponse
-	(*ListTasksRequest)(nil),      // 8: taskservice.ListTasksRequest
-	(*ListTasksResponse)(nil),     // 9: taskservice.ListTasksResponse
-	(*Task)(nil),                  // 10: taskservice.Task
-	(*TaskLog)(nil),               // 11: taskservice.TaskLog
-	nil,                           // 12: taskservice.CreateTaskRequest.PayloadEntry
-	nil,                           // 13: taskservice.Task.PayloadEntry
-	nil,                           // 14: taskservice.Task.ResultEntry
-	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+	(*CreateTaskRequest)(nil),          // 0: taskservice.CreateTaskRequest
+	(*CreateTaskResponse)(nil),         // 1: taskservice.CreateTaskResponse
+	(*CreateTasksRequest)(nil),         // 2: taskservice.CreateTasksRequest
+	(*CreateTaskResult)(nil),           // 3: taskservice.CreateTaskResult
+	(*CreateTasksResponse)(nil),        // 4: taskservice.CreateTasksResponse
+	(*GetTaskRequest)(nil),             // 5: taskservice.GetTaskRequest
+	(*GetTaskResponse)(nil),            // 6: taskservice.GetTaskResponse
+	(*CancelTaskRequest)(nil),          // 7: taskservice.CancelTaskRequest
+	(*CancelTaskResponse)(nil),         // 8: taskservice.CancelTaskResponse
+	(*GetTaskLogsRequest)(nil),         // 9: taskservice.GetTaskLogsRequest
+	(*GetTaskLogsResponse)(nil),        // 10: taskservice.GetTaskLogsResponse
+	(*ListTasksRequest)(nil),           // 11: taskservice.ListTasksRequest
+	(*ListTasksResponse)(nil),          // 12: taskservice.ListTasksResponse
+	(*WatchTaskRequest)(nil),           // 13: taskservice.WatchTaskRequest
+	(*WatchTasksRequest)(nil),          // 14: taskservice.WatchTasksRequest
+	(*TaskEvent)(nil),                  // 15: taskservice.TaskEvent
+	(*GetTaskResultRequest)(nil),       // 16: taskservice.GetTaskResultRequest
+	(*TaskResultChunk)(nil),            // 17: taskservice.TaskResultChunk
+	(*RetryTaskRequest)(nil),           // 18: taskservice.RetryTaskRequest
+	(*RetryTaskResponse)(nil),          // 19: taskservice.RetryTaskResponse
+	(*RetryTasksRequest)(nil),          // 20: taskservice.RetryTasksRequest
+	(*RetryTasksResponse)(nil),         // 21: taskservice.RetryTasksResponse
+	(*Task)(nil),                       // 22: taskservice.Task
+	(*TaskLog)(nil),                    // 23: taskservice.TaskLog
+	(*WorkflowNodeSpec)(nil),           // 24: taskservice.WorkflowNodeSpec
+	(*CreateWorkflowRequest)(nil),      // 25: taskservice.CreateWorkflowRequest
+	(*CreateWorkflowResponse)(nil),     // 26: taskservice.CreateWorkflowResponse
+	(*GetWorkflowRequest)(nil),         // 27: taskservice.GetWorkflowRequest
+	(*GetWorkflowResponse)(nil),        // 28: taskservice.GetWorkflowResponse
+	(*Workflow)(nil),                   // 29: taskservice.Workflow
+	(*WorkflowNode)(nil),               // 30: taskservice.WorkflowNode
+	(*DeadLetter)(nil),                 // 31: taskservice.DeadLetter
+	(*DeadLetterFilter)(nil),           // 32: taskservice.DeadLetterFilter
+	(*ListDeadLettersRequest)(nil),     // 33: taskservice.ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),    // 34: taskservice.ListDeadLettersResponse
+	(*GetDeadLetterRequest)(nil),       // 35: taskservice.GetDeadLetterRequest
+	(*GetDeadLetterResponse)(nil),      // 36: taskservice.GetDeadLetterResponse
+	(*RequeueDeadLetterRequest)(nil),   // 37: taskservice.RequeueDeadLetterRequest
+	(*RequeueDeadLetterResponse)(nil),  // 38: taskservice.RequeueDeadLetterResponse
+	(*RequeueDeadLettersRequest)(nil),  // 39: taskservice.RequeueDeadLettersRequest
+	(*RequeueDeadLettersResponse)(nil), // 40: taskservice.RequeueDeadLettersResponse
+	(*PurgeDeadLettersRequest)(nil),    // 41: taskservice.PurgeDeadLettersRequest
+	(*PurgeDeadLettersResponse)(nil),   // 42: taskservice.PurgeDeadLettersResponse
+	nil,                                // 43: taskservice.CreateTaskRequest.PayloadEntry
+	nil,                                // 44: taskservice.Task.PayloadEntry
+	nil,                                // 45: taskservice.Task.ResultEntry
+	nil,                                // 46: taskservice.WorkflowNodeSpec.PayloadEntry
+	nil,                                // 47: taskservice.RequeueDeadLetterRequest.PayloadEntry
+	(*timestamppb.Timestamp)(nil),      // 48: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),            // 49: google.protobuf.Struct
 }
 var file_proto_task_service_proto_depIdxs = []int32{
-	12, // 0: taskservice.CreateTaskRequest.payload:type_name -> taskservice.CreateTaskRequest.PayloadEntry
-	10, // 1: taskservice.CreateTaskResponse.task:type_name -> taskservice.Task
-	10, // 2: taskservice.GetTaskResponse.task:type_name -> taskservice.Task
-	11, // 3: taskservice.GetTaskLogsResponse.logs:type_name -> taskservice.TaskLog
-	10, // 4: taskservice.ListTasksResponse.tasks:type_name -> taskservice.Task
-	13, // 5: taskservice.Task.payload:type_name -> taskservice.Task.PayloadEntry
-	14, // 6: taskservice.Task.result:type_name -> taskservice.Task.ResultEntry
-	15, // 7: taskservice.Task.created_at:type_name -> google.protobuf.Timestamp
-	15, // 8: taskservice.Task.started_at:type_name -> google.protobuf.Timestamp
-	15, // 9: taskservice.Task.completed_at:type_name -> google.protobuf.Timestamp
-	15, // 10: taskservice.TaskLog.created_at:type_name -> google.protobuf.Timestamp
-	0,  // 11: taskservice.TaskService.CreateTask:input_type -> taskservice.CreateTaskRequest
-	2,  // 12: taskservice.TaskService.GetTask:input_type -> taskservice.GetTaskRequest
-	4,  // 13: taskservice.TaskService.CancelTask:input_type -> taskservice.CancelTaskRequest
-	6,  // 14: taskservice.TaskService.GetTaskLogs:input_type -> taskservice.GetTaskLogsRequest
-	8,  // 15: taskservice.TaskService.ListTasks:input_type -> taskservice.ListTasksRequest
-	1,  // 16: taskservice.TaskService.CreateTask:output_type -> taskservice.CreateTaskResponse
-	3,  // 17: taskservice.TaskService.GetTask:output_type -> taskservice.GetTaskResponse
-	5,  // 18: taskservice.TaskService.CancelTask:output_type -> taskservice.CancelTaskResponse
-	7,  // 19: taskservice.TaskService.GetTaskLogs:output_type -> taskservice.GetTaskLogsResponse
-	9,  // 20: taskservice.TaskService.ListTasks:output_type -> taskservice.ListTasksResponse
-	16, // [16:21] is the sub-list for method output_type
-	11, // [11:16] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	43, // 0: taskservice.CreateTaskRequest.payload:type_name -> taskservice.CreateTaskRequest.PayloadEntry
+	48, // 1: taskservice.CreateTaskRequest.scheduled_at:type_name -> google.protobuf.Timestamp
+	49, // 2: taskservice.CreateTaskRequest.payload_struct:type_name -> google.protobuf.Struct
+	22, // 3: taskservice.CreateTaskResponse.task:type_name -> taskservice.Task
+	0,  // 4: taskservice.CreateTasksRequest.tasks:type_name -> taskservice.CreateTaskRequest
+	22, // 5: taskservice.CreateTaskResult.task:type_name -> taskservice.Task
+	3,  // 6: taskservice.CreateTasksResponse.results:type_name -> taskservice.CreateTaskResult
+	22, // 7: taskservice.GetTaskResponse.task:type_name -> taskservice.Task
+	23, // 8: taskservice.GetTaskLogsResponse.logs:type_name -> taskservice.TaskLog
+	48, // 9: taskservice.ListTasksRequest.created_after:type_name -> google.protobuf.Timestamp
+	48, // 10: taskservice.ListTasksRequest.created_before:type_name -> google.protobuf.Timestamp
+	22, // 11: taskservice.ListTasksResponse.tasks:type_name -> taskservice.Task
+	48, // 12: taskservice.TaskEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	22, // 13: taskservice.RetryTaskResponse.task:type_name -> taskservice.Task
+	48, // 14: taskservice.RetryTasksRequest.created_after:type_name -> google.protobuf.Timestamp
+	48, // 15: taskservice.RetryTasksRequest.created_before:type_name -> google.protobuf.Timestamp
+	44, // 16: taskservice.Task.payload:type_name -> taskservice.Task.PayloadEntry
+	45, // 17: taskservice.Task.result:type_name -> taskservice.Task.ResultEntry
+	48, // 18: taskservice.Task.created_at:type_name -> google.protobuf.Timestamp
+	48, // 19: taskservice.Task.started_at:type_name -> google.protobuf.Timestamp
+	48, // 20: taskservice.Task.completed_at:type_name -> google.protobuf.Timestamp
+	48, // 21: taskservice.Task.scheduled_at:type_name -> google.protobuf.Timestamp
+	49, // 22: taskservice.Task.payload_struct:type_name -> google.protobuf.Struct
+	48, // 23: taskservice.TaskLog.created_at:type_name -> google.protobuf.Timestamp
+	46, // 24: taskservice.WorkflowNodeSpec.payload:type_name -> taskservice.WorkflowNodeSpec.PayloadEntry
+	49, // 25: taskservice.WorkflowNodeSpec.payload_struct:type_name -> google.protobuf.Struct
+	24, // 26: taskservice.CreateWorkflowRequest.nodes:type_name -> taskservice.WorkflowNodeSpec
+	29, // 27: taskservice.CreateWorkflowResponse.workflow:type_name -> taskservice.Workflow
+	29, // 28: taskservice.GetWorkflowResponse.workflow:type_name -> taskservice.Workflow
+	30, // 29: taskservice.Workflow.nodes:type_name -> taskservice.WorkflowNode
+	48, // 30: taskservice.Workflow.created_at:type_name -> google.protobuf.Timestamp
+	48, // 31: taskservice.Workflow.completed_at:type_name -> google.protobuf.Timestamp
+	48, // 32: taskservice.DeadLetter.dead_at:type_name -> google.protobuf.Timestamp
+	32, // 33: taskservice.ListDeadLettersRequest.filter:type_name -> taskservice.DeadLetterFilter
+	31, // 34: taskservice.ListDeadLettersResponse.dead_letters:type_name -> taskservice.DeadLetter
+	31, // 35: taskservice.GetDeadLetterResponse.dead_letter:type_name -> taskservice.DeadLetter
+	22, // 36: taskservice.GetDeadLetterResponse.task:type_name -> taskservice.Task
+	47, // 37: taskservice.RequeueDeadLetterRequest.payload:type_name -> taskservice.RequeueDeadLetterRequest.PayloadEntry
+	49, // 38: taskservice.RequeueDeadLetterRequest.payload_struct:type_name -> google.protobuf.Struct
+	22, // 39: taskservice.RequeueDeadLetterResponse.task:type_name -> taskservice.Task
+	32, // 40: taskservice.RequeueDeadLettersRequest.filter:type_name -> taskservice.DeadLetterFilter
+	32, // 41: taskservice.PurgeDeadLettersRequest.filter:type_name -> taskservice.DeadLetterFilter
+	0,  // 42: taskservice.TaskService.CreateTask:input_type -> taskservice.CreateTaskRequest
+	2,  // 43: taskservice.TaskService.CreateTasks:input_type -> taskservice.CreateTasksRequest
+	5,  // 44: taskservice.TaskService.GetTask:input_type -> taskservice.GetTaskRequest
+	7,  // 45: taskservice.TaskService.CancelTask:input_type -> taskservice.CancelTaskRequest
+	9,  // 46: taskservice.TaskService.GetTaskLogs:input_type -> taskservice.GetTaskLogsRequest
+	11, // 47: taskservice.TaskService.ListTasks:input_type -> taskservice.ListTasksRequest
+	13, // 48: taskservice.TaskService.WatchTask:input_type -> taskservice.WatchTaskRequest
+	14, // 49: taskservice.TaskService.WatchTasks:input_type -> taskservice.WatchTasksRequest
+	16, // 50: taskservice.TaskService.GetTaskResult:input_type -> taskservice.GetTaskResultRequest
+	18, // 51: taskservice.TaskService.RetryTask:input_type -> taskservice.RetryTaskRequest
+	20, // 52: taskservice.TaskService.RetryTasks:input_type -> taskservice.RetryTasksRequest
+	25, // 53: taskservice.TaskService.CreateWorkflow:input_type -> taskservice.CreateWorkflowRequest
+	27, // 54: taskservice.TaskService.GetWorkflow:input_type -> taskservice.GetWorkflowRequest
+	33, // 55: taskservice.TaskService.ListDeadLetters:input_type -> taskservice.ListDeadLettersRequest
+	35, // 56: taskservice.TaskService.GetDeadLetter:input_type -> taskservice.GetDeadLetterRequest
+	37, // 57: taskservice.TaskService.RequeueDeadLetter:input_type -> taskservice.RequeueDeadLetterRequest
+	39, // 58: taskservice.TaskService.RequeueDeadLetters:input_type -> taskservice.RequeueDeadLettersRequest
+	41, // 59: taskservice.TaskService.PurgeDeadLetters:input_type -> taskservice.PurgeDeadLettersRequest
+	1,  // 60: taskservice.TaskService.CreateTask:output_type -> taskservice.CreateTaskResponse
+	4,  // 61: taskservice.TaskService.CreateTasks:output_type -> taskservice.CreateTasksResponse
+	6,  // 62: taskservice.TaskService.GetTask:output_type -> taskservice.GetTaskResponse
+	8,  // 63: taskservice.TaskService.CancelTask:output_type -> taskservice.CancelTaskResponse
+	10, // 64: taskservice.TaskService.GetTaskLogs:output_type -> taskservice.GetTaskLogsResponse
+	12, // 65: taskservice.TaskService.ListTasks:output_type -> taskservice.ListTasksResponse
+	15, // 66: taskservice.TaskService.WatchTask:output_type -> taskservice.TaskEvent
+	15, // 67: taskservice.TaskService.WatchTasks:output_type -> taskservice.TaskEvent
+	17, // 68: taskservice.TaskService.GetTaskResult:output_type -> taskservice.TaskResultChunk
+	19, // 69: taskservice.TaskService.RetryTask:output_type -> taskservice.RetryTaskResponse
+	21, // 70: taskservice.TaskService.RetryTasks:output_type -> taskservice.RetryTasksResponse
+	26, // 71: taskservice.TaskService.CreateWorkflow:output_type -> taskservice.CreateWorkflowResponse
+	28, // 72: taskservice.TaskService.GetWorkflow:output_type -> taskservice.GetWorkflowResponse
+	34, // 73: taskservice.TaskService.ListDeadLetters:output_type -> taskservice.ListDeadLettersResponse
+	36, // 74: taskservice.TaskService.GetDeadLetter:output_type -> taskservice.GetDeadLetterResponse
+	38, // 75: taskservice.TaskService.RequeueDeadLetter:output_type -> taskservice.RequeueDeadLetterResponse
+	40, // 76: taskservice.TaskService.RequeueDeadLetters:output_type -> taskservice.RequeueDeadLettersResponse
+	42, // 77: taskservice.TaskService.PurgeDeadLetters:output_type -> taskservice.PurgeDeadLettersResponse
+	60, // [60:78] is the sub-list for method output_type
+	42, // [42:60] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
 }
 
 func init() { file_proto_task_service_proto_init() }
@@ -914,13 +3226,16 @@ func file_proto_task_service_proto_init() {
 	if File_proto_task_service_proto != nil {
 		return
 	}
+	file_proto_task_service_proto_msgTypes[11].OneofWrappers = []any{}
+	file_proto_task_service_proto_msgTypes[18].OneofWrappers = []any{}
+	file_proto_task_service_proto_msgTypes[20].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_task_service_proto_rawDesc), len(file_proto_task_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   48,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -14,6 +14,7 @@ CREATE TABLE IF NOT EXISTS task (
     result JSON,
     error_message TEXT,
     worker_id VARCHAR(64),
+    fencing_token BIGINT NOT NULL DEFAULT 0,
     retry_count INT NOT NULL DEFAULT 0,
     max_retry INT NOT NULL DEFAULT 3,
     timeout INT NOT NULL DEFAULT 30,
@@ -22,6 +23,12 @@ CREATE TABLE IF NOT EXISTS task (
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     started_at TIMESTAMP NULL,
     completed_at TIMESTAMP NULL,
+    idempotency_key VARCHAR(128) NULL,
+    tenant VARCHAR(64) NOT NULL DEFAULT 'default',
+    result_uri VARCHAR(512) NOT NULL DEFAULT '',
+    result_size BIGINT NOT NULL DEFAULT 0,
+    UNIQUE INDEX uk_task_type_idempotency_key (task_type, idempotency_key),
+    INDEX idx_tenant_status (tenant, status),
     INDEX idx_task_id (task_id),
     INDEX idx_status (status),
     INDEX idx_task_type (task_type),
@@ -58,7 +65,12 @@ CREATE TABLE IF NOT EXISTS task_config (
     retry_strategy VARCHAR(32) NOT NULL,
     retry_delay INT NOT NULL DEFAULT 5,
     backoff_rate DECIMAL(10,2) NOT NULL DEFAULT 2.0,
+    max_retry_delay INT NOT NULL DEFAULT 0,
+    retry_jitter DECIMAL(4,2) NOT NULL DEFAULT 0,
     max_concurrent INT NOT NULL DEFAULT 10,
+    rate_limit DECIMAL(10,3) NOT NULL DEFAULT 0,
+    rate_burst INT NOT NULL DEFAULT 0,
+    payload_schema JSON NULL,
     enabled BOOLEAN NOT NULL DEFAULT TRUE,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
@@ -66,6 +78,18 @@ CREATE TABLE IF NOT EXISTS task_config (
     INDEX idx_enabled (enabled)
     ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
+-- 租户配置表
+CREATE TABLE IF NOT EXISTS tenant_config (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant VARCHAR(64) UNIQUE NOT NULL,
+    quantum INT NOT NULL DEFAULT 1,
+    max_in_flight INT NOT NULL DEFAULT 0,
+    rate_limit DECIMAL(10,3) NOT NULL DEFAULT 0,
+    rate_burst INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
 -- Worker 注册表
 CREATE TABLE IF NOT EXISTS worker (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
@@ -84,6 +108,48 @@ CREATE TABLE IF NOT EXISTS worker (
     INDEX idx_last_heartbeat (last_heartbeat)
     ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
+-- 工作流表
+CREATE TABLE IF NOT EXISTS workflow (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    workflow_id VARCHAR(64) UNIQUE NOT NULL,
+    name VARCHAR(128) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL,
+    failure_policy VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL,
+    INDEX idx_status (status)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- 工作流节点表
+CREATE TABLE IF NOT EXISTS workflow_node (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    workflow_id VARCHAR(64) NOT NULL,
+    node_id VARCHAR(64) NOT NULL,
+    task_type VARCHAR(64) NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    payload JSON,
+    depends_on JSON,
+    task_id VARCHAR(64),
+    status VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX uk_workflow_node (workflow_id, node_id),
+    INDEX idx_task_id (task_id)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- 死信表
+CREATE TABLE IF NOT EXISTS dead_letter (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    task_id VARCHAR(64) UNIQUE NOT NULL,
+    task_type VARCHAR(64) NOT NULL,
+    error_message TEXT,
+    retry_count INT NOT NULL DEFAULT 0,
+    worker_id VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_task_type (task_type)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
 -- 插入示例任务配置
 INSERT INTO task_config (
     task_type, task_name, description, executor_type, executor_config,
@@ -3,31 +3,64 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
+	"strconv"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"bamboo/asynctaskmanager/application"
 	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/domain/service"
 	pb "bamboo/cmd/asynctaskmanager/proto"
 )
 
+const (
+	// taskResultChunkSize GetTaskResult 每个分块的字节数
+	taskResultChunkSize = 64 << 10
+	// taskResultContentType 任务结果的内容类型
+	taskResultContentType = "application/json"
+)
+
 // GRPCServer gRPC 服务器
 type GRPCServer struct {
 	pb.UnimplementedTaskServiceServer
-	taskService *application.TaskService
-	grpcServer  *grpc.Server
-	port        int
+	taskService       *application.TaskService
+	workflowService   *application.WorkflowService
+	deadLetterService *application.DeadLetterService
+	taskWatchService  *application.TaskWatchService
+	taskResultService *application.TaskResultService
+	grpcServer        *grpc.Server
+	port              int
 }
 
 // NewGRPCServer 创建 gRPC 服务器
-func NewGRPCServer(taskService *application.TaskService, port int) *GRPCServer {
+func NewGRPCServer(
+	taskService *application.TaskService,
+	workflowService *application.WorkflowService,
+	deadLetterService *application.DeadLetterService,
+	taskWatchService *application.TaskWatchService,
+	taskResultService *application.TaskResultService,
+	port int,
+) *GRPCServer {
 	return &GRPCServer{
-		taskService: taskService,
-		port:        port,
+		taskService:       taskService,
+		workflowService:   workflowService,
+		deadLetterService: deadLetterService,
+		taskWatchService:  taskWatchService,
+		taskResultService: taskResultService,
+		port:              port,
 	}
 }
 
@@ -54,27 +87,96 @@ func (s *GRPCServer) Stop() {
 
 // CreateTask 创建任务
 func (s *GRPCServer) CreateTask(ctx context.Context, req *pb.CreateTaskRequest) (*pb.CreateTaskResponse, error) {
-	// 转换优先级
-	priority := model.PriorityNormal
-	if req.Priority == 1 {
-		priority = model.PriorityHigh
+	params, err := buildCreateTaskParams(req, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// 转换 payload
-	payload := make(map[string]interface{})
-	for k, v := range req.Payload {
-		payload[k] = v
+	// 创建任务，幂等键命中时返回首次创建的任务
+	task, duplicate, err := s.taskService.SubmitTask(ctx, params)
+	if err != nil {
+		return nil, createTaskError(err)
+	}
+
+	return &pb.CreateTaskResponse{
+		Task:      convertTaskToProto(task),
+		Duplicate: duplicate,
+	}, nil
+}
+
+// createTaskError 将创建任务的错误转换为 gRPC 状态
+// payload 校验失败时返回 InvalidArgument，并在 BadRequest 详情中列出各字段的错误
+func createTaskError(err error) error {
+	var validationErr *model.PayloadValidationError
+	if !errors.As(err, &validationErr) {
+		return err
 	}
 
-	// 创建任务
-	task, err := s.taskService.CreateTask(ctx, req.TaskType, priority, payload)
+	badRequest := &errdetails.BadRequest{}
+	for _, fieldErr := range validationErr.Errors {
+		field := "payload"
+		if fieldErr.Field != "" {
+			field += "." + fieldErr.Field
+		}
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: fieldErr.Message,
+		})
+	}
+
+	st, detailErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(badRequest)
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
+
+// CreateTasks 批量创建任务，参数不合法的任务单独报错，其余任务一起提交
+func (s *GRPCServer) CreateTasks(ctx context.Context, req *pb.CreateTasksRequest) (*pb.CreateTasksResponse, error) {
+	if len(req.Tasks) > application.MaxBatchTasks {
+		return nil, status.Errorf(codes.InvalidArgument, "tasks exceeds %d items", application.MaxBatchTasks)
+	}
+
+	now := time.Now()
+	resp := &pb.CreateTasksResponse{Results: make([]*pb.CreateTaskResult, len(req.Tasks))}
+	params := make([]application.CreateTaskParams, 0, len(req.Tasks))
+	indexes := make([]int, 0, len(req.Tasks))
+	for i, item := range req.Tasks {
+		p, err := buildCreateTaskParams(item, now)
+		if err != nil {
+			resp.Results[i] = &pb.CreateTaskResult{Error: err.Error()}
+			continue
+		}
+		params = append(params, p)
+		indexes = append(indexes, i)
+	}
+
+	results, err := s.taskService.CreateTasks(ctx, params)
 	if err != nil {
+		if errors.Is(err, application.ErrBatchTooLarge) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, err
 	}
+	for k, result := range results {
+		item := &pb.CreateTaskResult{Duplicate: result.Duplicate}
+		if result.Err != nil {
+			item.Error = result.Err.Error()
+		} else {
+			item.Task = convertTaskToProto(result.Task)
+		}
+		resp.Results[indexes[k]] = item
+	}
 
-	return &pb.CreateTaskResponse{
-		Task: convertTaskToProto(task),
-	}, nil
+	for _, result := range resp.Results {
+		switch {
+		case result.Error != "":
+			resp.Failed++
+		case !result.Duplicate:
+			resp.Created++
+		}
+	}
+	return resp, nil
 }
 
 // GetTask 查询任务
@@ -124,13 +226,541 @@ func (s *GRPCServer) GetTaskLogs(ctx context.Context, req *pb.GetTaskLogsRequest
 
 // ListTasks 列出任务
 func (s *GRPCServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
-	// 这里简化实现，实际应该在 TaskService 中添加 ListTasks 方法
-	return &pb.ListTasksResponse{
-		Tasks: []*pb.Task{},
-		Total: 0,
+	query, err := buildTaskQuery(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := s.taskService.ListTasks(ctx, query)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list tasks failed: %v", err)
+	}
+
+	tasks := make([]*pb.Task, 0, len(page.Tasks))
+	for _, task := range page.Tasks {
+		tasks = append(tasks, convertTaskToProto(task))
+	}
+
+	resp := &pb.ListTasksResponse{
+		Tasks: tasks,
+		Total: int32(page.Total),
+	}
+	if page.NextCursor > 0 {
+		resp.NextPageToken = strconv.FormatInt(page.NextCursor, 10)
+	}
+
+	return resp, nil
+}
+
+// WatchTask 订阅单个任务的状态变更
+// 先订阅再读取当前状态，避免遗漏两者之间的变更；与当前状态重复的首个变更不再发送
+func (s *GRPCServer) WatchTask(req *pb.WatchTaskRequest, stream pb.TaskService_WatchTaskServer) error {
+	ctx := stream.Context()
+	watcher := s.taskWatchService.Watch(application.TaskWatchFilter{TaskID: req.TaskId})
+	defer s.taskWatchService.Unwatch(watcher)
+
+	task, err := s.taskService.GetTask(ctx, req.TaskId)
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if err := stream.Send(convertTaskSnapshotToProto(task)); err != nil {
+		return err
+	}
+	if application.IsFinishedTransition(task.Status) {
+		return nil
+	}
+
+	lastStatus := task.Status
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.Events():
+			if !ok {
+				return watchClosedError(watcher)
+			}
+			if event.ToStatus == lastStatus {
+				continue
+			}
+			lastStatus = event.ToStatus
+			if err := stream.Send(convertTaskEventToProto(event)); err != nil {
+				return err
+			}
+			if application.IsFinishedTransition(event.ToStatus) {
+				return nil
+			}
+		}
+	}
+}
+
+// WatchTasks 按条件订阅所有任务的状态变更
+func (s *GRPCServer) WatchTasks(req *pb.WatchTasksRequest, stream pb.TaskService_WatchTasksServer) error {
+	ctx := stream.Context()
+	watcher := s.taskWatchService.Watch(buildTaskWatchFilter(req))
+	defer s.taskWatchService.Unwatch(watcher)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.Events():
+			if !ok {
+				return watchClosedError(watcher)
+			}
+			if err := stream.Send(convertTaskEventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchClosedError 订阅被服务端关闭时返回的错误，客户端可重新订阅
+func watchClosedError(watcher *application.TaskWatcher) error {
+	if watcher.Lagged() {
+		return status.Error(codes.ResourceExhausted, "watcher fell behind, resubscribe")
+	}
+	return status.Error(codes.Unavailable, "watch closed")
+}
+
+// GetTaskResult 读取任务结果，按 taskResultChunkSize 分块返回
+func (s *GRPCServer) GetTaskResult(req *pb.GetTaskResultRequest, stream pb.TaskService_GetTaskResultServer) error {
+	result, err := s.taskResultService.Open(stream.Context(), req.TaskId)
+	if err != nil {
+		return taskResultError(err)
+	}
+	defer result.Close()
+
+	return sendTaskResult(result, result.Size, stream.Send)
+}
+
+// sendTaskResult 将结果分块发送，首个分块带上总大小与内容类型；结果为空时也发送一个分块
+func sendTaskResult(r io.Reader, size int64, send func(*pb.TaskResultChunk) error) error {
+	buf := make([]byte, taskResultChunkSize)
+	first := true
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 || first {
+			chunk := &pb.TaskResultChunk{Data: buf[:n]}
+			if first {
+				chunk.TotalSize = size
+				chunk.ContentType = taskResultContentType
+				first = false
+			}
+			if sendErr := send(chunk); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Internal, fmt.Sprintf("read task result failed: %v", err))
+		}
+	}
+}
+
+// taskResultError 将任务结果服务的错误转换为 gRPC 状态
+func taskResultError(err error) error {
+	switch {
+	case errors.Is(err, application.ErrTaskResultUnavailable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrResultNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return err
+}
+
+// RetryTask 手动重试任务
+func (s *GRPCServer) RetryTask(ctx context.Context, req *pb.RetryTaskRequest) (*pb.RetryTaskResponse, error) {
+	opts, err := buildRetryOptions(ctx, req.ResetRetryCount, req.Priority, req.TriggeredBy)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	task, err := s.taskService.RetryTask(ctx, req.TaskId, opts)
+	if err != nil {
+		if errors.Is(err, application.ErrTaskNotRetryable) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+
+	return &pb.RetryTaskResponse{
+		Task: convertTaskToProto(task),
+	}, nil
+}
+
+// RetryTasks 按条件批量手动重试任务
+func (s *GRPCServer) RetryTasks(ctx context.Context, req *pb.RetryTasksRequest) (*pb.RetryTasksResponse, error) {
+	opts, err := buildRetryOptions(ctx, req.ResetRetryCount, req.Priority, req.TriggeredBy)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	query, err := buildTaskQuery(&pb.ListTasksRequest{
+		Status:        req.Status,
+		TaskType:      req.TaskType,
+		WorkerId:      req.WorkerId,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	retried, err := s.taskService.RetryTasks(ctx, query, opts)
+	if err != nil {
+		if errors.Is(err, application.ErrTaskNotRetryable) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "retry tasks failed: %v", err)
+	}
+
+	return &pb.RetryTasksResponse{
+		Retried: int32(retried),
+	}, nil
+}
+
+// buildRetryOptions 构建手动重试选项，未指定触发者时使用调用方地址
+func buildRetryOptions(ctx context.Context, resetRetryCount bool, priority *int32, triggeredBy string) (application.RetryOptions, error) {
+	opts := application.RetryOptions{
+		ResetRetryCount: resetRetryCount,
+		TriggeredBy:     triggeredBy,
+	}
+
+	if priority != nil {
+		p, err := parsePriority(*priority)
+		if err != nil {
+			return opts, err
+		}
+		opts.Priority = &p
+	}
+
+	if opts.TriggeredBy == "" {
+		if p, ok := peer.FromContext(ctx); ok {
+			opts.TriggeredBy = "peer " + p.Addr.String()
+		}
+	}
+	return opts, nil
+}
+
+// CreateWorkflow 创建 DAG 工作流
+func (s *GRPCServer) CreateWorkflow(ctx context.Context, req *pb.CreateWorkflowRequest) (*pb.CreateWorkflowResponse, error) {
+	nodes, err := buildWorkflowNodes(req.Nodes)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	workflow, err := s.workflowService.CreateWorkflow(ctx, req.Name, model.FailurePolicy(req.FailurePolicy), nodes)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidWorkflow) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+
+	return &pb.CreateWorkflowResponse{
+		Workflow: convertWorkflowToProto(workflow),
+	}, nil
+}
+
+// GetWorkflow 查询工作流
+func (s *GRPCServer) GetWorkflow(ctx context.Context, req *pb.GetWorkflowRequest) (*pb.GetWorkflowResponse, error) {
+	workflow, err := s.workflowService.GetWorkflow(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetWorkflowResponse{
+		Workflow: convertWorkflowToProto(workflow),
+	}, nil
+}
+
+// ListDeadLetters 列出死信
+func (s *GRPCServer) ListDeadLetters(ctx context.Context, req *pb.ListDeadLettersRequest) (*pb.ListDeadLettersResponse, error) {
+	query, err := buildDeadLetterQuery(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := s.deadLetterService.ListDeadLetters(ctx, query)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list dead letters failed: %v", err)
+	}
+
+	deadLetters := make([]*pb.DeadLetter, 0, len(page.DeadLetters))
+	for _, deadLetter := range page.DeadLetters {
+		deadLetters = append(deadLetters, convertDeadLetterToProto(deadLetter))
+	}
+
+	resp := &pb.ListDeadLettersResponse{
+		DeadLetters: deadLetters,
+		Total:       int32(page.Total),
+	}
+	if page.NextCursor > 0 {
+		resp.NextPageToken = strconv.FormatInt(page.NextCursor, 10)
+	}
+
+	return resp, nil
+}
+
+// GetDeadLetter 查看死信
+func (s *GRPCServer) GetDeadLetter(ctx context.Context, req *pb.GetDeadLetterRequest) (*pb.GetDeadLetterResponse, error) {
+	deadLetter, task, err := s.deadLetterService.GetDeadLetter(ctx, req.TaskId)
+	if err != nil {
+		return nil, deadLetterError(err)
+	}
+
+	return &pb.GetDeadLetterResponse{
+		DeadLetter: convertDeadLetterToProto(deadLetter),
+		Task:       convertTaskToProto(task),
+	}, nil
+}
+
+// RequeueDeadLetter 重新入队单个死信任务
+func (s *GRPCServer) RequeueDeadLetter(ctx context.Context, req *pb.RequeueDeadLetterRequest) (*pb.RequeueDeadLetterResponse, error) {
+	var payload map[string]interface{}
+	if req.ReplacePayload {
+		var err error
+		payload, err = payloadFromProto(req.Payload, req.PayloadStruct)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	task, err := s.deadLetterService.RequeueDeadLetter(ctx, req.TaskId, payload)
+	if err != nil {
+		return nil, deadLetterError(err)
+	}
+
+	return &pb.RequeueDeadLetterResponse{
+		Task: convertTaskToProto(task),
+	}, nil
+}
+
+// RequeueDeadLetters 按条件批量重新入队死信任务
+func (s *GRPCServer) RequeueDeadLetters(ctx context.Context, req *pb.RequeueDeadLettersRequest) (*pb.RequeueDeadLettersResponse, error) {
+	query, err := buildDeadLetterFilter(req.Filter, req.All)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	requeued, err := s.deadLetterService.RequeueDeadLetters(ctx, query)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "requeue dead letters failed: %v", err)
+	}
+
+	return &pb.RequeueDeadLettersResponse{
+		Requeued: int32(requeued),
+	}, nil
+}
+
+// PurgeDeadLetters 按条件清除死信
+func (s *GRPCServer) PurgeDeadLetters(ctx context.Context, req *pb.PurgeDeadLettersRequest) (*pb.PurgeDeadLettersResponse, error) {
+	query, err := buildDeadLetterFilter(req.Filter, req.All)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	purged, err := s.deadLetterService.PurgeDeadLetters(ctx, query)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "purge dead letters failed: %v", err)
+	}
+
+	return &pb.PurgeDeadLettersResponse{
+		Purged: purged,
+	}, nil
+}
+
+// deadLetterError 将死信服务的错误转换为 gRPC 状态
+// 修改后的 payload 校验失败时与创建任务一样返回 InvalidArgument
+func deadLetterError(err error) error {
+	if errors.Is(err, application.ErrNotDeadLettered) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, application.ErrTaskNotRetryable) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return createTaskError(err)
+}
+
+// buildDeadLetterQuery 将列出死信请求转换为查询条件
+func buildDeadLetterQuery(req *pb.ListDeadLettersRequest) (repository.DeadLetterQuery, error) {
+	query, _ := buildDeadLetterFilter(req.Filter, true)
+	query.Limit = int(req.PageSize)
+
+	if req.PageSize < 0 {
+		return query, fmt.Errorf("page_size must not be negative")
+	}
+
+	if req.PageToken != "" {
+		cursor, err := strconv.ParseInt(req.PageToken, 10, 64)
+		if err != nil || cursor <= 0 {
+			return query, fmt.Errorf("invalid page_token: %s", req.PageToken)
+		}
+		query.BeforeID = cursor
+	}
+
+	return query, nil
+}
+
+// buildDeadLetterFilter 将过滤条件转换为查询条件，条件为空且未指定 all 时拒绝批量操作
+func buildDeadLetterFilter(filter *pb.DeadLetterFilter, all bool) (repository.DeadLetterQuery, error) {
+	query := repository.DeadLetterQuery{
+		TaskType:      filter.GetTaskType(),
+		ErrorContains: filter.GetErrorContains(),
+		TaskIDs:       filter.GetTaskIds(),
+	}
+
+	if !all && query.TaskType == "" && query.ErrorContains == "" && len(query.TaskIDs) == 0 {
+		return query, fmt.Errorf("filter is empty, set all to apply to every dead letter")
+	}
+	return query, nil
+}
+
+// buildTaskQuery 将列表请求转换为查询条件
+func buildTaskQuery(req *pb.ListTasksRequest) (repository.TaskQuery, error) {
+	query := repository.TaskQuery{
+		Status:   model.TaskStatus(req.Status),
+		TaskType: req.TaskType,
+		WorkerID: req.WorkerId,
+		Tenant:   req.Tenant,
+		Limit:    int(req.PageSize),
+	}
+
+	if req.PageSize < 0 {
+		return query, fmt.Errorf("page_size must not be negative")
+	}
+
+	// 未设置时不过滤；旧客户端用 -1 表示不过滤
+	if req.Priority != nil && *req.Priority != -1 {
+		priority, err := parsePriority(*req.Priority)
+		if err != nil {
+			return query, err
+		}
+		query.Priority = &priority
+	}
+
+	if req.PageToken != "" {
+		cursor, err := strconv.ParseInt(req.PageToken, 10, 64)
+		if err != nil || cursor <= 0 {
+			return query, fmt.Errorf("invalid page_token: %s", req.PageToken)
+		}
+		query.BeforeID = cursor
+	}
+
+	if req.CreatedAfter != nil {
+		if err := req.CreatedAfter.CheckValid(); err != nil {
+			return query, fmt.Errorf("invalid created_after: %w", err)
+		}
+		query.CreatedAfter = req.CreatedAfter.AsTime()
+	}
+	if req.CreatedBefore != nil {
+		if err := req.CreatedBefore.CheckValid(); err != nil {
+			return query, fmt.Errorf("invalid created_before: %w", err)
+		}
+		query.CreatedBefore = req.CreatedBefore.AsTime()
+	}
+	if !query.CreatedAfter.IsZero() && !query.CreatedBefore.IsZero() && !query.CreatedAfter.Before(query.CreatedBefore) {
+		return query, fmt.Errorf("created_after must be before created_before")
+	}
+
+	return query, nil
+}
+
+// buildTaskWatchFilter 将订阅请求转换为事件过滤条件
+func buildTaskWatchFilter(req *pb.WatchTasksRequest) application.TaskWatchFilter {
+	filter := application.TaskWatchFilter{TaskTypes: req.TaskTypes}
+	for _, taskStatus := range req.Statuses {
+		filter.Statuses = append(filter.Statuses, model.TaskStatus(taskStatus))
+	}
+	return filter
+}
+
+// buildCreateTaskParams 将创建任务请求转换为创建参数
+func buildCreateTaskParams(req *pb.CreateTaskRequest, now time.Time) (application.CreateTaskParams, error) {
+	// 转换优先级
+	priority, err := parsePriority(req.Priority)
+	if err != nil {
+		return application.CreateTaskParams{}, err
+	}
+
+	// 转换 payload
+	payload, err := payloadFromProto(req.Payload, req.PayloadStruct)
+	if err != nil {
+		return application.CreateTaskParams{}, err
+	}
+
+	// 解析计划执行时间
+	scheduledAt, err := resolveScheduledAt(req, now)
+	if err != nil {
+		return application.CreateTaskParams{}, err
+	}
+
+	if len(req.IdempotencyKey) > application.MaxIdempotencyKeyLength {
+		return application.CreateTaskParams{}, fmt.Errorf("idempotency_key exceeds %d bytes", application.MaxIdempotencyKeyLength)
+	}
+
+	tenant, err := model.NormalizeTenant(req.Tenant)
+	if err != nil {
+		return application.CreateTaskParams{}, err
+	}
+
+	return application.CreateTaskParams{
+		TaskType:       req.TaskType,
+		Priority:       priority,
+		Payload:        payload,
+		ScheduledAt:    scheduledAt,
+		IdempotencyKey: req.IdempotencyKey,
+		Tenant:         tenant,
 	}, nil
 }
 
+// payloadFromProto 将请求中的 payload 转换为任务参数
+// payload_struct 保留原始类型，旧的 map<string,string> 字段的值均为字符串，两者不能同时设置
+func payloadFromProto(payload map[string]string, payloadStruct *structpb.Struct) (map[string]interface{}, error) {
+	if payloadStruct != nil {
+		if len(payload) > 0 {
+			return nil, errors.New("payload and payload_struct are mutually exclusive")
+		}
+		return payloadStruct.AsMap(), nil
+	}
+
+	converted := make(map[string]interface{}, len(payload))
+	for k, v := range payload {
+		converted[k] = v
+	}
+	return converted, nil
+}
+
+// parsePriority 校验并转换优先级，取值范围外的优先级返回错误
+func parsePriority(priority int32) (model.TaskPriority, error) {
+	p := model.TaskPriority(priority)
+	if !p.IsValid() {
+		return p, fmt.Errorf("invalid priority %d, must be %d-%d", priority, model.PriorityMin, model.PriorityMax)
+	}
+	return p, nil
+}
+
+// resolveScheduledAt 根据 scheduled_at / delay_seconds 计算计划执行时间
+func resolveScheduledAt(req *pb.CreateTaskRequest, now time.Time) (time.Time, error) {
+	if req.ScheduledAt != nil && req.DelaySeconds != 0 {
+		return time.Time{}, fmt.Errorf("scheduled_at and delay_seconds are mutually exclusive")
+	}
+	if req.DelaySeconds < 0 {
+		return time.Time{}, fmt.Errorf("delay_seconds must not be negative")
+	}
+
+	if req.ScheduledAt != nil {
+		if err := req.ScheduledAt.CheckValid(); err != nil {
+			return time.Time{}, fmt.Errorf("invalid scheduled_at: %w", err)
+		}
+		return req.ScheduledAt.AsTime(), nil
+	}
+
+	return now.Add(time.Duration(req.DelaySeconds) * time.Second), nil
+}
+
 // convertTaskToProto 转换任务为 protobuf 格式
 func convertTaskToProto(task *model.Task) *pb.Task {
 	pbTask := &pb.Task{
@@ -143,10 +773,19 @@ func convertTaskToProto(task *model.Task) *pb.Task {
 		MaxRetry:     int32(task.MaxRetry),
 		ErrorMessage: task.ErrorMsg,
 		CreatedAt:    timestamppb.New(task.CreatedAt),
+		ScheduledAt:  timestamppb.New(task.ScheduledAt),
+
+		IdempotencyKey: task.IdempotencyKey,
+		Tenant:         task.Tenant,
+		ResultUri:      task.ResultURI,
+		ResultSize:     task.ResultSize,
 	}
 
-	// 转换 payload
+	// 转换 payload，payload_struct 保留原始类型，无法表示为 Struct 时只返回旧字段
 	if task.Payload != nil {
+		if payloadStruct, err := structpb.NewStruct(task.Payload); err == nil {
+			pbTask.PayloadStruct = payloadStruct
+		}
 		pbTask.Payload = make(map[string]string)
 		for k, v := range task.Payload {
 			if str, ok := v.(string); ok {
@@ -198,3 +837,92 @@ func convertTaskLogToProto(log *model.TaskLog) *pb.TaskLog {
 		CreatedAt:  timestamppb.New(log.CreatedAt),
 	}
 }
+
+// convertTaskEventToProto 转换任务事件为 protobuf 格式
+func convertTaskEventToProto(event *application.TaskEvent) *pb.TaskEvent {
+	return &pb.TaskEvent{
+		TaskId:     event.TaskID,
+		TaskType:   event.TaskType,
+		LogType:    string(event.LogType),
+		FromStatus: string(event.FromStatus),
+		ToStatus:   string(event.ToStatus),
+		Message:    event.Message,
+		WorkerId:   event.WorkerID,
+		RetryCount: int32(event.RetryCount),
+		OccurredAt: timestamppb.New(event.CreatedAt),
+	}
+}
+
+// convertTaskSnapshotToProto 将任务当前状态转换为快照事件
+func convertTaskSnapshotToProto(task *model.Task) *pb.TaskEvent {
+	return &pb.TaskEvent{
+		TaskId:     task.TaskID,
+		TaskType:   task.TaskType,
+		ToStatus:   string(task.Status),
+		Message:    task.ErrorMsg,
+		WorkerId:   task.WorkerID,
+		RetryCount: int32(task.RetryCount),
+		OccurredAt: timestamppb.New(task.UpdatedAt),
+		Snapshot:   true,
+	}
+}
+
+// buildWorkflowNodes 将节点定义转换为工作流节点
+func buildWorkflowNodes(specs []*pb.WorkflowNodeSpec) ([]*model.WorkflowNode, error) {
+	nodes := make([]*model.WorkflowNode, 0, len(specs))
+	for _, spec := range specs {
+		payload, err := payloadFromProto(spec.Payload, spec.PayloadStruct)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", spec.NodeId, err)
+		}
+
+		nodes = append(nodes, &model.WorkflowNode{
+			NodeID:    spec.NodeId,
+			TaskType:  spec.TaskType,
+			Priority:  model.TaskPriority(spec.Priority),
+			Payload:   payload,
+			DependsOn: spec.DependsOn,
+		})
+	}
+	return nodes, nil
+}
+
+// convertWorkflowToProto 转换工作流为 protobuf 格式
+func convertWorkflowToProto(workflow *model.Workflow) *pb.Workflow {
+	pbWorkflow := &pb.Workflow{
+		WorkflowId:    workflow.WorkflowID,
+		Name:          workflow.Name,
+		Status:        string(workflow.Status),
+		FailurePolicy: string(workflow.FailurePolicy),
+		CreatedAt:     timestamppb.New(workflow.CreatedAt),
+	}
+
+	for _, node := range workflow.Nodes {
+		pbWorkflow.Nodes = append(pbWorkflow.Nodes, &pb.WorkflowNode{
+			NodeId:    node.NodeID,
+			TaskType:  node.TaskType,
+			Priority:  int32(node.Priority.Value()),
+			DependsOn: node.DependsOn,
+			Status:    string(node.Status),
+			TaskId:    node.TaskID,
+		})
+	}
+
+	if workflow.CompletedAt != nil {
+		pbWorkflow.CompletedAt = timestamppb.New(*workflow.CompletedAt)
+	}
+
+	return pbWorkflow
+}
+
+// convertDeadLetterToProto 转换死信为 protobuf 格式
+func convertDeadLetterToProto(deadLetter *model.DeadLetter) *pb.DeadLetter {
+	return &pb.DeadLetter{
+		TaskId:       deadLetter.TaskID,
+		TaskType:     deadLetter.TaskType,
+		ErrorMessage: deadLetter.ErrorMsg,
+		RetryCount:   int32(deadLetter.RetryCount),
+		WorkerId:     deadLetter.WorkerID,
+		DeadAt:       timestamppb.New(deadLetter.CreatedAt),
+	}
+}
@@ -0,0 +1,579 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"bamboo/asynctaskmanager/application"
+	"bamboo/asynctaskmanager/domain/model"
+	"bamboo/asynctaskmanager/domain/repository"
+	"bamboo/asynctaskmanager/infrastructure/memory"
+	"bamboo/asynctaskmanager/infrastructure/redis"
+	pb "bamboo/cmd/asynctaskmanager/proto"
+)
+
+func TestResolveScheduledAt(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	at := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		req     *pb.CreateTaskRequest
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "立即执行",
+			req:  &pb.CreateTaskRequest{},
+			want: now,
+		},
+		{
+			name: "延迟秒数",
+			req:  &pb.CreateTaskRequest{DelaySeconds: 30},
+			want: now.Add(30 * time.Second),
+		},
+		{
+			name: "指定时间",
+			req:  &pb.CreateTaskRequest{ScheduledAt: timestamppb.New(at)},
+			want: at,
+		},
+		{
+			name:    "同时指定时间和延迟",
+			req:     &pb.CreateTaskRequest{ScheduledAt: timestamppb.New(at), DelaySeconds: 30},
+			wantErr: true,
+		},
+		{
+			name:    "负延迟",
+			req:     &pb.CreateTaskRequest{DelaySeconds: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveScheduledAt(tt.req, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveScheduledAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("resolveScheduledAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCreateTaskParams(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		req          *pb.CreateTaskRequest
+		wantPriority model.TaskPriority
+		wantTenant   string
+		wantErr      bool
+	}{
+		{
+			name:         "高优先级",
+			req:          &pb.CreateTaskRequest{TaskType: "example_task", Priority: 1, Payload: map[string]string{"k": "v"}},
+			wantPriority: model.PriorityHigh,
+		},
+		{
+			name:         "多级优先级",
+			req:          &pb.CreateTaskRequest{TaskType: "example_task", Priority: 7},
+			wantPriority: model.TaskPriority(7),
+		},
+		{
+			name:    "优先级超出范围",
+			req:     &pb.CreateTaskRequest{TaskType: "example_task", Priority: 10},
+			wantErr: true,
+		},
+		{
+			name:    "负优先级",
+			req:     &pb.CreateTaskRequest{TaskType: "example_task", Priority: -1},
+			wantErr: true,
+		},
+		{
+			name:    "幂等键过长",
+			req:     &pb.CreateTaskRequest{TaskType: "example_task", IdempotencyKey: strings.Repeat("k", application.MaxIdempotencyKeyLength+1)},
+			wantErr: true,
+		},
+		{
+			name:    "计划时间不合法",
+			req:     &pb.CreateTaskRequest{TaskType: "example_task", DelaySeconds: -1},
+			wantErr: true,
+		},
+		{
+			name:       "指定租户",
+			req:        &pb.CreateTaskRequest{TaskType: "example_task", Tenant: "acme"},
+			wantTenant: "acme",
+		},
+		{
+			name:    "租户名不合法",
+			req:     &pb.CreateTaskRequest{TaskType: "example_task", Tenant: "acme:prod"},
+			wantErr: true,
+		},
+		{
+			name: "payload 与 payload_struct 同时设置",
+			req: &pb.CreateTaskRequest{
+				TaskType:      "example_task",
+				Payload:       map[string]string{"k": "v"},
+				PayloadStruct: &structpb.Struct{Fields: map[string]*structpb.Value{"k": structpb.NewNumberValue(1)}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantTenant == "" {
+				tt.wantTenant = model.DefaultTenant
+			}
+			got, err := buildCreateTaskParams(tt.req, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildCreateTaskParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.TaskType != tt.req.TaskType || got.Priority != tt.wantPriority || got.Tenant != tt.wantTenant || !got.ScheduledAt.Equal(now) {
+				t.Errorf("buildCreateTaskParams() = %+v", got)
+			}
+			if len(got.Payload) != len(tt.req.Payload) {
+				t.Errorf("payload = %v, want %v", got.Payload, tt.req.Payload)
+			}
+		})
+	}
+}
+
+func TestPayloadFromProto(t *testing.T) {
+	typed, err := structpb.NewStruct(map[string]interface{}{
+		"url":   "http://example.com",
+		"count": 3,
+		"dry":   true,
+		"body":  map[string]interface{}{"ids": []interface{}{1, 2}},
+	})
+	if err != nil {
+		t.Fatalf("NewStruct() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		payload       map[string]string
+		payloadStruct *structpb.Struct
+		want          map[string]interface{}
+		wantErr       bool
+	}{
+		{
+			name:    "旧字段的值均为字符串",
+			payload: map[string]string{"count": "3"},
+			want:    map[string]interface{}{"count": "3"},
+		},
+		{
+			name:          "类型化 payload 保留原始类型",
+			payloadStruct: typed,
+			want: map[string]interface{}{
+				"url":   "http://example.com",
+				"count": float64(3),
+				"dry":   true,
+				"body":  map[string]interface{}{"ids": []interface{}{float64(1), float64(2)}},
+			},
+		},
+		{
+			name: "均未设置",
+			want: map[string]interface{}{},
+		},
+		{
+			name:          "同时设置",
+			payload:       map[string]string{"count": "3"},
+			payloadStruct: typed,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := payloadFromProto(tt.payload, tt.payloadStruct)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("payloadFromProto() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("payloadFromProto() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertTaskToProto_Payload(t *testing.T) {
+	task := &model.Task{
+		TaskID:   "task-1",
+		TaskType: "http_request",
+		Payload: map[string]interface{}{
+			"url":  "http://example.com",
+			"body": map[string]interface{}{"count": float64(3)},
+		},
+	}
+
+	got := convertTaskToProto(task)
+	if got.Payload["url"] != "http://example.com" || got.Payload["body"] != `{"count":3}` {
+		t.Errorf("payload = %v", got.Payload)
+	}
+	if !reflect.DeepEqual(got.PayloadStruct.AsMap(), task.Payload) {
+		t.Errorf("payload_struct = %v, want %v", got.PayloadStruct.AsMap(), task.Payload)
+	}
+}
+
+func TestCreateTaskError(t *testing.T) {
+	err := createTaskError(&model.PayloadValidationError{Errors: []model.FieldError{
+		{Field: "url", Message: "is required"},
+		{Field: "body.ids[0]", Message: "must be integer, got string"},
+		{Message: "must be object, got array"},
+	}})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("code = %v, want InvalidArgument", st.Code())
+	}
+	var violations []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range badRequest.FieldViolations {
+				violations = append(violations, v.Field+": "+v.Description)
+			}
+		}
+	}
+	want := []string{
+		"payload.url: is required",
+		"payload.body.ids[0]: must be integer, got string",
+		"payload: must be object, got array",
+	}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("field violations = %v, want %v", violations, want)
+	}
+
+	// 其他错误原样返回
+	other := errors.New("get task config failed")
+	if got := createTaskError(other); got != other {
+		t.Errorf("createTaskError() = %v, want %v", got, other)
+	}
+}
+
+func TestDeadLetterError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"不在死信队列", fmt.Errorf("task-1: %w", application.ErrNotDeadLettered), codes.NotFound},
+		{"工作流节点任务", fmt.Errorf("%w: task-1 belongs to a workflow node", application.ErrTaskNotRetryable), codes.FailedPrecondition},
+		{"修改后的 payload 不合法", &model.PayloadValidationError{Errors: []model.FieldError{{Field: "url", Message: "is required"}}}, codes.InvalidArgument},
+		{"其他错误", errors.New("connection refused"), codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(deadLetterError(tt.err)); got != tt.want {
+				t.Errorf("code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTaskQuery(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		req     *pb.ListTasksRequest
+		check   func(t *testing.T, q repository.TaskQuery)
+		wantErr bool
+	}{
+		{
+			name: "未设置优先级时不过滤",
+			req:  &pb.ListTasksRequest{PageSize: 10},
+			check: func(t *testing.T, q repository.TaskQuery) {
+				if q.Priority != nil || q.Limit != 10 || q.BeforeID != 0 {
+					t.Errorf("unexpected query: %+v", q)
+				}
+			},
+		},
+		{
+			name: "旧客户端传 -1 不过滤",
+			req:  &pb.ListTasksRequest{Priority: proto.Int32(-1)},
+			check: func(t *testing.T, q repository.TaskQuery) {
+				if q.Priority != nil {
+					t.Errorf("priority = %v, want nil", *q.Priority)
+				}
+			},
+		},
+		{
+			name: "按取值为 0 的优先级过滤",
+			req:  &pb.ListTasksRequest{Priority: proto.Int32(0)},
+			check: func(t *testing.T, q repository.TaskQuery) {
+				if q.Priority == nil || *q.Priority != model.PriorityNormal {
+					t.Errorf("priority = %v, want NORMAL", q.Priority)
+				}
+			},
+		},
+		{
+			name:    "优先级超出范围",
+			req:     &pb.ListTasksRequest{Priority: proto.Int32(10)},
+			wantErr: true,
+		},
+		{
+			name: "全部过滤条件",
+			req: &pb.ListTasksRequest{
+				Status:        "PENDING",
+				Priority:      proto.Int32(1),
+				TaskType:      "email",
+				WorkerId:      "worker-1",
+				PageToken:     "42",
+				CreatedAfter:  timestamppb.New(from),
+				CreatedBefore: timestamppb.New(to),
+			},
+			check: func(t *testing.T, q repository.TaskQuery) {
+				if q.Status != model.StatusPending || q.TaskType != "email" || q.WorkerID != "worker-1" {
+					t.Errorf("unexpected query: %+v", q)
+				}
+				if q.Priority == nil || *q.Priority != model.PriorityHigh {
+					t.Errorf("priority = %v, want HIGH", q.Priority)
+				}
+				if q.BeforeID != 42 {
+					t.Errorf("BeforeID = %d, want 42", q.BeforeID)
+				}
+				if !q.CreatedAfter.Equal(from) || !q.CreatedBefore.Equal(to) {
+					t.Errorf("created range = [%v, %v), want [%v, %v)", q.CreatedAfter, q.CreatedBefore, from, to)
+				}
+			},
+		},
+		{
+			name:    "非法翻页 token",
+			req:     &pb.ListTasksRequest{PageToken: "abc"},
+			wantErr: true,
+		},
+		{
+			name:    "负数分页大小",
+			req:     &pb.ListTasksRequest{PageSize: -1},
+			wantErr: true,
+		},
+		{
+			name: "创建时间范围颠倒",
+			req: &pb.ListTasksRequest{
+				CreatedAfter:  timestamppb.New(to),
+				CreatedBefore: timestamppb.New(from),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := buildTaskQuery(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildTaskQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, q)
+			}
+		})
+	}
+}
+
+func TestGRPCServer_ListTasks(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	configRepo := memory.NewTaskConfigRepository()
+	if err := configRepo.Create(ctx, &model.TaskConfig{TaskType: "example_task", ExecutorType: model.ExecutorTypeLocal, Enabled: true}); err != nil {
+		t.Fatalf("create task config failed: %v", err)
+	}
+	taskService := application.NewTaskService(memory.NewTaskRepository(), memory.NewTaskLogRepository(), configRepo, memory.NewDeadLetterRepository(), redis.NewQueueManager(client))
+	for _, priority := range []model.TaskPriority{model.PriorityNormal, model.PriorityHigh, model.PriorityHigh} {
+		if _, err := taskService.CreateTask(ctx, "example_task", priority, nil); err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+	}
+	server := NewGRPCServer(taskService, nil, nil, nil, nil, 0)
+
+	tests := []struct {
+		name string
+		req  *pb.ListTasksRequest
+		want int32
+	}{
+		{"未设置优先级时返回全部任务", &pb.ListTasksRequest{}, 3},
+		{"按取值为 0 的优先级过滤", &pb.ListTasksRequest{Priority: proto.Int32(int32(model.PriorityNormal))}, 1},
+		{"按高优先级过滤", &pb.ListTasksRequest{Priority: proto.Int32(int32(model.PriorityHigh))}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := server.ListTasks(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("ListTasks() error = %v", err)
+			}
+			if resp.Total != tt.want || int32(len(resp.Tasks)) != tt.want {
+				t.Errorf("ListTasks() total = %d, tasks = %d, want %d", resp.Total, len(resp.Tasks), tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildDeadLetterFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  *pb.DeadLetterFilter
+		all     bool
+		wantErr bool
+	}{
+		{
+			name:   "按任务类型与错误过滤",
+			filter: &pb.DeadLetterFilter{TaskType: "email", ErrorContains: "timeout"},
+		},
+		{
+			name:   "指定任务",
+			filter: &pb.DeadLetterFilter{TaskIds: []string{"task-1"}},
+		},
+		{
+			name:    "空条件未指定 all",
+			filter:  &pb.DeadLetterFilter{},
+			wantErr: true,
+		},
+		{
+			name:    "未传过滤条件",
+			wantErr: true,
+		},
+		{
+			name: "空条件指定 all",
+			all:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := buildDeadLetterFilter(tt.filter, tt.all)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildDeadLetterFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if q.TaskType != tt.filter.GetTaskType() || q.ErrorContains != tt.filter.GetErrorContains() || len(q.TaskIDs) != len(tt.filter.GetTaskIds()) {
+				t.Errorf("unexpected query: %+v", q)
+			}
+		})
+	}
+}
+
+func TestBuildRetryOptions(t *testing.T) {
+	high, p5, invalid := int32(1), int32(5), int32(10)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+
+	tests := []struct {
+		name            string
+		priority        *int32
+		triggeredBy     string
+		wantPriority    *model.TaskPriority
+		wantTriggeredBy string
+		wantErr         bool
+	}{
+		{
+			name:            "保持原优先级",
+			triggeredBy:     "alice",
+			wantTriggeredBy: "alice",
+		},
+		{
+			name:            "指定高优先级，触发者取调用方地址",
+			priority:        &high,
+			wantPriority:    func() *model.TaskPriority { p := model.PriorityHigh; return &p }(),
+			wantTriggeredBy: "peer 10.0.0.1:5000",
+		},
+		{
+			name:            "指定多级优先级",
+			priority:        &p5,
+			triggeredBy:     "alice",
+			wantPriority:    func() *model.TaskPriority { p := model.TaskPriority(5); return &p }(),
+			wantTriggeredBy: "alice",
+		},
+		{
+			name:     "非法优先级",
+			priority: &invalid,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := buildRetryOptions(ctx, true, tt.priority, tt.triggeredBy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildRetryOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !opts.ResetRetryCount || opts.TriggeredBy != tt.wantTriggeredBy {
+				t.Errorf("opts = %+v, want triggered by %q", opts, tt.wantTriggeredBy)
+			}
+			if (opts.Priority == nil) != (tt.wantPriority == nil) || (opts.Priority != nil && *opts.Priority != *tt.wantPriority) {
+				t.Errorf("priority = %v, want %v", opts.Priority, tt.wantPriority)
+			}
+		})
+	}
+}
+
+func TestSendTaskResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		wantChunks int
+	}{
+		{"空结果也返回一个分块", 0, 1},
+		{"小于分块大小", 100, 1},
+		{"恰好一个分块", taskResultChunkSize, 1},
+		{"多个分块", 2*taskResultChunkSize + 1, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := bytes.Repeat([]byte("x"), tt.size)
+			var chunks []*pb.TaskResultChunk
+			var got []byte
+			err := sendTaskResult(bytes.NewReader(data), int64(len(data)), func(chunk *pb.TaskResultChunk) error {
+				chunks = append(chunks, chunk)
+				got = append(got, chunk.Data...)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("sendTaskResult() error = %v", err)
+			}
+			if len(chunks) != tt.wantChunks {
+				t.Fatalf("chunks = %d, want %d", len(chunks), tt.wantChunks)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("received %d bytes, want %d", len(got), len(data))
+			}
+			if chunks[0].TotalSize != int64(tt.size) || chunks[0].ContentType != taskResultContentType {
+				t.Errorf("first chunk = (%d, %q), want (%d, %q)", chunks[0].TotalSize, chunks[0].ContentType, tt.size, taskResultContentType)
+			}
+			for _, chunk := range chunks[1:] {
+				if chunk.TotalSize != 0 || chunk.ContentType != "" {
+					t.Errorf("only the first chunk should carry size and content type")
+				}
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"bamboo/asynctaskmanager/infrastructure/executor"
 	"bamboo/asynctaskmanager/infrastructure/mysql"
 	"bamboo/asynctaskmanager/infrastructure/redis"
+	"bamboo/asynctaskmanager/infrastructure/resultstore"
 )
 
 type MysqlDSN string
@@ -100,6 +101,24 @@ func (dsn MysqlDSN) Parse() mysql.Config {
 	return config
 }
 
+// PriorityWeights 逗号分隔的各优先级出队权重，依次对应优先级 0 到 9
+type PriorityWeights string
+
+// Parse 解析出队权重
+// 格式: 1,2,3,4,5,6,7,8,9,10
+func (w PriorityWeights) Parse() ([]int, error) {
+	parts := strings.Split(string(w), ",")
+	weights := make([]int, 0, len(parts))
+	for _, part := range parts {
+		weight, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority weight %q: %w", part, err)
+		}
+		weights = append(weights, weight)
+	}
+	return weights, nil
+}
+
 // ServerConfig 服务器配置
 type ServerConfig struct {
 	ServerID   string
@@ -107,6 +126,32 @@ type ServerConfig struct {
 	RedisAddr  string
 	MysqlDSN   MysqlDSN
 	WorkerPort int
+	// PriorityWeights 出队权重，为空时使用默认权重（优先级 p 的权重为 p+1）
+	PriorityWeights PriorityWeights
+	// PriorityAging 老化阈值，任务在队首等待超过该时长后优先出队，0 表示不老化
+	PriorityAging time.Duration
+	// ResultStore 结果存储类型：local、s3，为空时结果全部保存在 MySQL 中
+	ResultStore string
+	// ResultDir local 结果存储的目录
+	ResultDir string
+	// ResultS3 s3 结果存储的配置
+	ResultS3 resultstore.S3Config
+	// ResultOffloadThreshold 结果转存阈值（字节），JSON 编码后超过该大小的结果写入结果存储，0 表示使用默认阈值
+	ResultOffloadThreshold int
+}
+
+// newResultStore 按配置创建结果存储，未配置时返回 nil
+func newResultStore(cfg *ServerConfig) (service.ResultStore, error) {
+	switch cfg.ResultStore {
+	case "":
+		return nil, nil
+	case "local":
+		return resultstore.NewLocalResultStore(cfg.ResultDir)
+	case "s3":
+		return resultstore.NewS3ResultStore(cfg.ResultS3, nil)
+	default:
+		return nil, fmt.Errorf("unknown result store %q", cfg.ResultStore)
+	}
 }
 
 // Server 服务器实例
@@ -116,6 +161,9 @@ type Server struct {
 	schedulerService *application.SchedulerService
 	workerService    *application.WorkerService
 	taskService      *application.TaskService
+	workflowService  *application.WorkflowService
+	taskWatchService *application.TaskWatchService
+	grpcExecutor     *executor.GRPCExecutor
 	redisClient      *redis.Client
 	mysqlClient      *mysql.Client
 	wg               sync.WaitGroup
@@ -137,15 +185,46 @@ func NewServer(cfg *ServerConfig) (*Server, error) {
 
 	// 创建仓储
 	taskRepo := mysql.NewTaskRepository(mysqlClient)
-	taskLogRepo := mysql.NewTaskLogRepository(mysqlClient)
+	// 状态变更日志写入后发布任务事件，供 WatchTask/WatchTasks 订阅
+	taskEventBus := redis.NewTaskEventBus(redisClient)
+	taskLogRepo := redis.NewEventTaskLogRepository(mysql.NewTaskLogRepository(mysqlClient), taskEventBus)
 	taskConfigRepo := mysql.NewTaskConfigRepository(mysqlClient)
+	tenantConfigRepo := mysql.NewTenantConfigRepository(mysqlClient)
+	workflowRepo := mysql.NewWorkflowRepository(mysqlClient)
+	deadLetterRepo := mysql.NewDeadLetterRepository(mysqlClient)
 	//workerRepo := mysql.NewWorkerRepository(mysqlClient)
 
+	// 创建结果存储，超过阈值的任务结果转存到其中
+	resultStore, err := newResultStore(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create result store failed: %w", err)
+	}
+	taskResultService := application.NewTaskResultService(taskRepo, resultStore, cfg.ResultOffloadThreshold)
+
 	// 使用redis存放worker
 	workerRepo := redis.NewWorkerRepository(redisClient)
 
 	// 创建队列管理器
 	queueManager := redis.NewQueueManager(redisClient)
+	dequeuePolicy := redis.DefaultDequeuePolicy()
+	dequeuePolicy.AgingThreshold = cfg.PriorityAging
+	if cfg.PriorityWeights != "" {
+		weights, err := cfg.PriorityWeights.Parse()
+		if err != nil {
+			return nil, err
+		}
+		dequeuePolicy.Weights = weights
+	}
+	if err := queueManager.SetDequeuePolicy(dequeuePolicy); err != nil {
+		return nil, fmt.Errorf("invalid dequeue policy: %w", err)
+	}
+	if err := queueManager.RebuildReadyIndex(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// 创建任务类型并发限制器与分发限流器
+	concurrencyLimiter := redis.NewConcurrencyLimiter(redisClient)
+	rateLimiter := redis.NewRateLimiter(redisClient)
 
 	// 创建执行器注册表
 	executorRegistry := executor.NewExecutorRegistry()
@@ -171,11 +250,28 @@ func NewServer(cfg *ServerConfig) (*Server, error) {
 		return nil, fmt.Errorf("register local executor failed: %w", err)
 	}
 
+	// 注册 gRPC 执行器，处理所有启用的 RPC 类型任务
+	taskConfigs, err := taskConfigRepo.FindEnabled(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("load task configs failed: %w", err)
+	}
+	var rpcTaskTypes []string
+	for _, taskConfig := range taskConfigs {
+		if taskConfig.ExecutorType == model.ExecutorTypeRPC {
+			rpcTaskTypes = append(rpcTaskTypes, taskConfig.TaskType)
+		}
+	}
+	grpcExecutor := executor.NewGRPCExecutor(taskConfigRepo, rpcTaskTypes)
+	if err := executorRegistry.Register(grpcExecutor); err != nil {
+		return nil, fmt.Errorf("register grpc executor failed: %w", err)
+	}
+
 	// 创建任务服务
 	taskService := application.NewTaskService(
 		taskRepo,
 		taskLogRepo,
 		taskConfigRepo,
+		deadLetterRepo,
 		queueManager,
 	)
 
@@ -186,14 +282,41 @@ func NewServer(cfg *ServerConfig) (*Server, error) {
 	schedulerService := application.NewSchedulerService(
 		taskRepo,
 		taskLogRepo,
+		taskConfigRepo,
+		tenantConfigRepo,
+		deadLetterRepo,
 		workerRepo,
 		leaderElection,
 		queueManager,
+		concurrencyLimiter,
+		rateLimiter,
 		loadBalancer,
-		5*time.Second,
+		time.Second,
 		30*time.Second,
 	)
 
+	// 创建工作流服务，由 Leader 推进
+	workflowService := application.NewWorkflowService(
+		workflowRepo,
+		taskRepo,
+		taskConfigRepo,
+		taskService,
+		leaderElection,
+		time.Second,
+	)
+
+	// 创建死信服务
+	deadLetterService := application.NewDeadLetterService(
+		deadLetterRepo,
+		taskRepo,
+		taskLogRepo,
+		taskConfigRepo,
+		queueManager,
+	)
+
+	// 创建任务事件订阅服务
+	taskWatchService := application.NewTaskWatchService(taskRepo, taskEventBus)
+
 	// 创建 Worker
 	worker := &model.Worker{
 		WorkerID:       fmt.Sprintf("%s-worker", cfg.ServerID),
@@ -210,14 +333,19 @@ func NewServer(cfg *ServerConfig) (*Server, error) {
 		worker,
 		taskRepo,
 		taskLogRepo,
+		taskConfigRepo,
+		deadLetterRepo,
 		workerRepo,
 		queueManager,
+		concurrencyLimiter,
 		executorRegistry,
+		taskResultService,
 		5*time.Second,
+		30*time.Second,
 	)
 
 	// 创建 gRPC 服务器
-	grpcServer := NewGRPCServer(taskService, cfg.GRPCPort)
+	grpcServer := NewGRPCServer(taskService, workflowService, deadLetterService, taskWatchService, taskResultService, cfg.GRPCPort)
 
 	return &Server{
 		config:           cfg,
@@ -225,6 +353,9 @@ func NewServer(cfg *ServerConfig) (*Server, error) {
 		schedulerService: schedulerService,
 		workerService:    workerService,
 		taskService:      taskService,
+		workflowService:  workflowService,
+		taskWatchService: taskWatchService,
+		grpcExecutor:     grpcExecutor,
 		redisClient:      redisClient,
 		mysqlClient:      mysqlClient,
 	}, nil
@@ -252,6 +383,24 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	}()
 
+	s.wg.Add(1)
+	// 启动工作流推进循环
+	go func() {
+		defer s.wg.Done()
+		if err := s.workflowService.Start(ctx); err != nil {
+			log.Printf("[%s] Workflow service stopped: %v", s.config.ServerID, err)
+		}
+	}()
+
+	s.wg.Add(1)
+	// 启动任务事件分发
+	go func() {
+		defer s.wg.Done()
+		if err := s.taskWatchService.Start(ctx); err != nil {
+			log.Printf("[%s] Task watch service stopped: %v", s.config.ServerID, err)
+		}
+	}()
+
 	s.wg.Add(1)
 	// 启动 gRPC 服务器
 	go func() {
@@ -271,6 +420,7 @@ func (s *Server) Stop() error {
 	s.grpcServer.Stop()
 	s.wg.Wait()
 	s.workerService.Stop()
+	s.grpcExecutor.Close()
 	s.redisClient.Close()
 	s.mysqlClient.Close()
 	return nil
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"slices"
+	"testing"
+
+	"bamboo/asynctaskmanager/infrastructure/resultstore"
+)
+
+func TestPriorityWeights_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights PriorityWeights
+		want    []int
+		wantErr bool
+	}{
+		{
+			name:    "逗号分隔",
+			weights: "1,2,3,4,5,6,7,8,9,10",
+			want:    []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		{
+			name:    "允许空格",
+			weights: "0, 1, 2",
+			want:    []int{0, 1, 2},
+		},
+		{
+			name:    "非数字",
+			weights: "1,a,3",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.weights.Parse()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResultStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ServerConfig
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "未配置时不转存", cfg: ServerConfig{}, wantNil: true},
+		{name: "本地目录", cfg: ServerConfig{ResultStore: "local", ResultDir: t.TempDir()}},
+		{
+			name: "S3 兼容存储",
+			cfg: ServerConfig{ResultStore: "s3", ResultS3: resultstore.S3Config{
+				Endpoint: "http://localhost:9000",
+				Bucket:   "results",
+			}},
+		},
+		{name: "S3 缺少 bucket", cfg: ServerConfig{ResultStore: "s3", ResultS3: resultstore.S3Config{Endpoint: "http://localhost:9000"}}, wantErr: true},
+		{name: "未知类型", cfg: ServerConfig{ResultStore: "ftp"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := newResultStore(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newResultStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (store == nil) != tt.wantNil {
+				t.Errorf("newResultStore() = %v, wantNil %v", store, tt.wantNil)
+			}
+		})
+	}
+}
@@ -12,32 +12,63 @@ import (
 	"bamboo/pkg/distributeschedule/infrastructure/redis"
 )
 
+const (
+	// DefaultScanInterval 未配置扫描间隔时使用的默认值
+	DefaultScanInterval = 5 * time.Second
+	// DefaultTriggerInterval 未配置 Cron 触发检查间隔时使用的默认值
+	DefaultTriggerInterval = time.Second
+)
+
 // ScheduleService 调度服务
 type ScheduleService struct {
 	taskRepo         repository.TaskRepository
 	workerRepo       repository.WorkerRepository
+	configRepo       repository.TaskConfigRepository
 	leaderElection   *redis.LeaderElection
 	loadBalancer     service.LoadBalancer
 	scanInterval     time.Duration
+	triggerInterval  time.Duration
 	heartbeatTimeout time.Duration
+
+	// cronEntries Leader 内存中的 Cron 触发状态（key: 配置ID）
+	cronEntries map[string]*cronEntry
+}
+
+// cronEntry 单个任务配置的 Cron 触发状态
+type cronEntry struct {
+	expr     string
+	schedule model.CronSchedule
+	next     time.Time
 }
 
 // NewScheduleService 创建调度服务
+// scanInterval、triggerInterval 小于等于 0 时分别使用 DefaultScanInterval、DefaultTriggerInterval
 func NewScheduleService(
 	taskRepo repository.TaskRepository,
 	workerRepo repository.WorkerRepository,
+	configRepo repository.TaskConfigRepository,
 	leaderElection *redis.LeaderElection,
 	loadBalancer service.LoadBalancer,
 	scanInterval time.Duration,
+	triggerInterval time.Duration,
 	heartbeatTimeout time.Duration,
 ) *ScheduleService {
+	if scanInterval <= 0 {
+		scanInterval = DefaultScanInterval
+	}
+	if triggerInterval <= 0 {
+		triggerInterval = DefaultTriggerInterval
+	}
 	return &ScheduleService{
 		taskRepo:         taskRepo,
 		workerRepo:       workerRepo,
+		configRepo:       configRepo,
 		leaderElection:   leaderElection,
 		loadBalancer:     loadBalancer,
 		scanInterval:     scanInterval,
+		triggerInterval:  triggerInterval,
 		heartbeatTimeout: heartbeatTimeout,
+		cronEntries:      make(map[string]*cronEntry),
 	}
 }
 
@@ -69,10 +100,15 @@ func (s *ScheduleService) Start(ctx context.Context) error {
 // runAsLeader 作为 Leader 运行
 func (s *ScheduleService) runAsLeader(ctx context.Context) error {
 	scanTicker := time.NewTicker(s.scanInterval)
+	triggerTicker := time.NewTicker(s.triggerInterval)
 	renewTicker := time.NewTicker(3 * time.Second)
 	defer scanTicker.Stop()
+	defer triggerTicker.Stop()
 	defer renewTicker.Stop()
 
+	// 新任期重新计算触发时间，避免重复触发上一任 Leader 已触发的任务
+	s.cronEntries = make(map[string]*cronEntry)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -86,6 +122,12 @@ func (s *ScheduleService) runAsLeader(ctx context.Context) error {
 				return fmt.Errorf("lost leadership")
 			}
 
+		case <-triggerTicker.C:
+			// 触发到期的 Cron 任务
+			if err := s.triggerCronTasks(ctx, time.Now()); err != nil {
+				log.Printf("trigger cron tasks failed: %v", err)
+			}
+
 		case <-scanTicker.C:
 			// 扫描并调度任务
 			if err := s.scanAndSchedule(ctx); err != nil {
@@ -100,6 +142,65 @@ func (s *ScheduleService) runAsLeader(ctx context.Context) error {
 	}
 }
 
+// triggerCronTasks 为到期的启用配置创建任务实例
+// 首次见到的配置只计算下一次触发时间，不补触发；错过多个周期时只触发一次
+func (s *ScheduleService) triggerCronTasks(ctx context.Context, now time.Time) error {
+	if s.configRepo == nil {
+		return nil
+	}
+
+	configs, err := s.configRepo.FindEnabled(ctx)
+	if err != nil {
+		return fmt.Errorf("find enabled configs failed: %w", err)
+	}
+
+	active := make(map[string]struct{}, len(configs))
+	for _, cfg := range configs {
+		if !cfg.IsEnabled() || cfg.CronExpr == "" {
+			continue
+		}
+		active[cfg.ID] = struct{}{}
+
+		entry, ok := s.cronEntries[cfg.ID]
+		if !ok || entry.expr != cfg.CronExpr {
+			schedule, err := model.ParseCron(cfg.CronExpr)
+			if err != nil {
+				log.Printf("parse cron expr of config %s failed: %v", cfg.ID, err)
+				delete(s.cronEntries, cfg.ID)
+				continue
+			}
+			s.cronEntries[cfg.ID] = &cronEntry{
+				expr:     cfg.CronExpr,
+				schedule: schedule,
+				next:     schedule.Next(now),
+			}
+			continue
+		}
+
+		if entry.next.IsZero() || entry.next.After(now) {
+			continue
+		}
+
+		task := cfg.CreateTask(entry.next)
+		if err := s.taskRepo.Save(ctx, task); err != nil {
+			log.Printf("save cron task of config %s failed: %v", cfg.ID, err)
+			continue
+		}
+		log.Printf("triggered task %s for config %s at %s", task.ID, cfg.ID, entry.next.Format(time.RFC3339))
+
+		entry.next = entry.schedule.Next(now)
+	}
+
+	// 清理已删除或已禁用的配置
+	for id := range s.cronEntries {
+		if _, ok := active[id]; !ok {
+			delete(s.cronEntries, id)
+		}
+	}
+
+	return nil
+}
+
 // scanAndSchedule 扫描并调度任务
 func (s *ScheduleService) scanAndSchedule(ctx context.Context) error {
 	// 查找待执行的任务
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"bamboo/pkg/distributeschedule/domain/model"
+	"bamboo/pkg/distributeschedule/infrastructure/memory"
+)
+
+// fakeTaskRepository 记录保存的任务实例
+type fakeTaskRepository struct {
+	mu    sync.Mutex
+	saved []*model.Task
+}
+
+func (r *fakeTaskRepository) Save(ctx context.Context, task *model.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved = append(r.saved, task)
+	return nil
+}
+
+func (r *fakeTaskRepository) FindByID(ctx context.Context, id string) (*model.Task, error) {
+	return nil, nil
+}
+
+func (r *fakeTaskRepository) FindPendingTasks(ctx context.Context, limit int) ([]*model.Task, error) {
+	return nil, nil
+}
+
+func (r *fakeTaskRepository) FindRunningTasks(ctx context.Context) ([]*model.Task, error) {
+	return nil, nil
+}
+
+func (r *fakeTaskRepository) FindTimeoutTasks(ctx context.Context, timeout time.Duration) ([]*model.Task, error) {
+	return nil, nil
+}
+
+func (r *fakeTaskRepository) Update(ctx context.Context, task *model.Task) error {
+	return nil
+}
+
+func (r *fakeTaskRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestScheduleService_TriggerCronTasks(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		config    *model.TaskConfig
+		now       time.Time
+		wantTimes []time.Time
+	}{
+		{
+			name:      "due expression creates task",
+			config:    &model.TaskConfig{ID: "due", CronExpr: "* * * * *", Executor: "local", Enabled: true},
+			now:       time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC),
+			wantTimes: []time.Time{time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC)},
+		},
+		{
+			name:   "not yet due expression waits",
+			config: &model.TaskConfig{ID: "later", CronExpr: "0 11 * * *", Executor: "local", Enabled: true},
+			now:    time.Date(2024, 1, 15, 10, 59, 59, 0, time.UTC),
+		},
+		{
+			name:   "invalid expression is skipped",
+			config: &model.TaskConfig{ID: "invalid", CronExpr: "61 * * * *", Executor: "local", Enabled: true},
+			now:    time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "disabled config never triggers",
+			config: &model.TaskConfig{ID: "disabled", CronExpr: "* * * * *", Executor: "local", Enabled: false},
+			now:    time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			taskRepo := &fakeTaskRepository{}
+			configRepo := memory.NewTaskConfigRepository()
+			if err := configRepo.Save(ctx, tt.config); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			s := NewScheduleService(taskRepo, nil, configRepo, nil, nil, time.Second, time.Second, time.Second)
+
+			// 首次只计算下一次触发时间
+			if err := s.triggerCronTasks(ctx, base); err != nil {
+				t.Fatalf("triggerCronTasks() error = %v", err)
+			}
+			if err := s.triggerCronTasks(ctx, tt.now); err != nil {
+				t.Fatalf("triggerCronTasks() error = %v", err)
+			}
+
+			if len(taskRepo.saved) != len(tt.wantTimes) {
+				t.Fatalf("saved %d tasks, want %d", len(taskRepo.saved), len(tt.wantTimes))
+			}
+			for i, task := range taskRepo.saved {
+				if task.ConfigID != tt.config.ID {
+					t.Errorf("task %d config = %s, want %s", i, task.ConfigID, tt.config.ID)
+				}
+				if task.Status != model.TaskPending {
+					t.Errorf("task %d status = %v, want pending", i, task.Status)
+				}
+				if !task.ScheduledTime.Equal(tt.wantTimes[i]) {
+					t.Errorf("task %d scheduled at %v, want %v", i, task.ScheduledTime, tt.wantTimes[i])
+				}
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ type WorkerService struct {
 	worker           *model.Worker
 	taskRepo         repository.TaskRepository
 	workerRepo       repository.WorkerRepository
+	configRepo       repository.TaskConfigRepository
 	executorRegistry service.ExecutorRegistry
 	heartbeatInterval time.Duration
 }
@@ -26,6 +27,7 @@ func NewWorkerService(
 	worker *model.Worker,
 	taskRepo repository.TaskRepository,
 	workerRepo repository.WorkerRepository,
+	configRepo repository.TaskConfigRepository,
 	executorRegistry service.ExecutorRegistry,
 	heartbeatInterval time.Duration,
 ) *WorkerService {
@@ -33,6 +35,7 @@ func NewWorkerService(
 		worker:           worker,
 		taskRepo:         taskRepo,
 		workerRepo:       workerRepo,
+		configRepo:       configRepo,
 		executorRegistry: executorRegistry,
 		heartbeatInterval: heartbeatInterval,
 	}
@@ -110,13 +113,14 @@ func (s *WorkerService) processTask(ctx context.Context) error {
 	log.Printf("worker %s processing task %s", s.worker.ID, task.ID)
 
 	// 获取执行器
-	executor, found := s.executorRegistry.Get(task.ConfigID)
+	executorType := s.resolveExecutorType(ctx, task)
+	executor, found := s.executorRegistry.Get(executorType)
 	if !found {
-		task.MarkAsFailed(fmt.Sprintf("executor not found: %s", task.ConfigID))
+		task.MarkAsFailed(fmt.Sprintf("executor not found: %s", executorType))
 		_ = taskRepo.Update(ctx, task)
 		s.worker.CompleteTask()
 		_ = s.workerRepo.Update(ctx, s.worker)
-		return fmt.Errorf("executor not found: %s", task.ConfigID)
+		return fmt.Errorf("executor not found: %s", executorType)
 	}
 
 	// 执行任务
@@ -147,3 +151,18 @@ func (s *WorkerService) processTask(ctx context.Context) error {
 
 	return nil
 }
+
+// resolveExecutorType 解析任务使用的执行器类型
+// 优先使用任务配置中的 Executor，找不到配置时回退为 ConfigID
+func (s *WorkerService) resolveExecutorType(ctx context.Context, task *model.Task) string {
+	if s.configRepo == nil {
+		return task.ConfigID
+	}
+
+	config, err := s.configRepo.FindByID(ctx, task.ConfigID)
+	if err != nil || config.Executor == "" {
+		return task.ConfigID
+	}
+
+	return config.Executor
+}
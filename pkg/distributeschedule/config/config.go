@@ -17,6 +17,7 @@ type ScheduleConfig struct {
 	LeaderLockTTL       time.Duration `yaml:"leader_lock_ttl"`
 	LeaderRenewInterval time.Duration `yaml:"leader_renew_interval"`
 	ScanInterval        time.Duration `yaml:"scan_interval"`
+	TriggerInterval     time.Duration `yaml:"trigger_interval"`
 	LoadBalanceStrategy string        `yaml:"load_balance_strategy"`
 }
 
@@ -52,6 +53,7 @@ func DefaultConfig() *Config {
 			LeaderLockTTL:       10 * time.Second,
 			LeaderRenewInterval: 3 * time.Second,
 			ScanInterval:        5 * time.Second,
+			TriggerInterval:     1 * time.Second,
 			LoadBalanceStrategy: "least_task",
 		},
 		Worker: WorkerConfig{
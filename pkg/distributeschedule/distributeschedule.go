@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"bamboo/pkg/distributeschedule/config"
+	"bamboo/pkg/distributeschedule/domain/model"
 	"bamboo/pkg/distributeschedule/domain/service"
 	"bamboo/pkg/distributeschedule/interfaces"
 )
@@ -40,6 +41,41 @@ func (ds *DistributeSchedule) RegisterExecutor(executor service.Executor) {
 	ds.scheduler.RegisterExecutor(executor)
 }
 
+// AddJob 注册任务配置（CronExpr 非空时由 Leader 按计划触发）
+func (ds *DistributeSchedule) AddJob(ctx context.Context, job *model.TaskConfig) error {
+	return ds.scheduler.AddJob(ctx, job)
+}
+
+// UpdateJob 更新任务配置
+func (ds *DistributeSchedule) UpdateJob(ctx context.Context, job *model.TaskConfig) error {
+	return ds.scheduler.UpdateJob(ctx, job)
+}
+
+// RemoveJob 删除任务配置
+func (ds *DistributeSchedule) RemoveJob(ctx context.Context, id string) error {
+	return ds.scheduler.RemoveJob(ctx, id)
+}
+
+// GetJob 获取任务配置
+func (ds *DistributeSchedule) GetJob(ctx context.Context, id string) (*model.TaskConfig, error) {
+	return ds.scheduler.GetJob(ctx, id)
+}
+
+// ListJobs 列出所有任务配置
+func (ds *DistributeSchedule) ListJobs(ctx context.Context) ([]*model.TaskConfig, error) {
+	return ds.scheduler.ListJobs(ctx)
+}
+
+// EnableJob 启用任务配置
+func (ds *DistributeSchedule) EnableJob(ctx context.Context, id string) error {
+	return ds.scheduler.EnableJob(ctx, id)
+}
+
+// DisableJob 禁用任务配置
+func (ds *DistributeSchedule) DisableJob(ctx context.Context, id string) error {
+	return ds.scheduler.DisableJob(ctx, id)
+}
+
 // Close 关闭调度框架
 func (ds *DistributeSchedule) Close() error {
 	return ds.scheduler.Close()
@@ -0,0 +1,260 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule Cron 调度计划（值对象）
+type CronSchedule interface {
+	// Next 返回严格晚于 t 的下一次触发时间，无可用时间时返回零值
+	Next(t time.Time) time.Time
+}
+
+// cronField 单个字段的取值范围
+type cronField struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	secondField = cronField{min: 0, max: 59}
+	minuteField = cronField{min: 0, max: 59}
+	hourField   = cronField{min: 0, max: 23}
+	domField    = cronField{min: 1, max: 31}
+	monthField  = cronField{min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 星期字段允许 7 表示周日
+	dowField = cronField{min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronDescriptors 预定义描述符
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// specSchedule 基于字段位图的调度计划
+type specSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	domStar, dowStar                      bool
+}
+
+// everySchedule 固定间隔调度计划（@every）
+type everySchedule struct {
+	interval time.Duration
+}
+
+// ParseCron 解析 Cron 表达式
+// 支持标准 5 段（分 时 日 月 周）、带秒的 6 段，以及 @every <duration>、@daily 等描述符
+func ParseCron(expr string) (CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty cron expression")
+	}
+
+	if strings.HasPrefix(expr, "@") {
+		if strings.HasPrefix(expr, "@every ") {
+			interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+			if err != nil {
+				return nil, fmt.Errorf("invalid @every interval: %w", err)
+			}
+			if interval < time.Second {
+				return nil, fmt.Errorf("@every interval must be at least 1s, got %s", interval)
+			}
+			return &everySchedule{interval: interval.Truncate(time.Second)}, nil
+		}
+
+		spec, ok := cronDescriptors[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron descriptor: %s", expr)
+		}
+		expr = spec
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("expected 5 or 6 fields, got %d: %s", len(fields), expr)
+	}
+
+	s := &specSchedule{}
+	var err error
+	if s.second, err = parseCronField(fields[0], secondField); err != nil {
+		return nil, fmt.Errorf("second: %w", err)
+	}
+	if s.minute, err = parseCronField(fields[1], minuteField); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, err = parseCronField(fields[2], hourField); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, err = parseCronField(fields[3], domField); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, err = parseCronField(fields[4], monthField); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, err = parseCronField(fields[5], dowField); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+
+	// 7 与 0 均表示周日
+	if s.dow&(1<<7) != 0 {
+		s.dow = (s.dow | 1) &^ (1 << 7)
+	}
+	s.domStar = isCronWildcard(fields[3])
+	s.dowStar = isCronWildcard(fields[5])
+
+	return s, nil
+}
+
+// isCronWildcard 判断字段是否为不限制取值
+func isCronWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parseCronField 解析单个字段为位图，支持 *、?、a-b、*/n、a-b/n、a/n 以及逗号列表
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty item in %q", field)
+		}
+
+		rangePart, step := part, uint(1)
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			n, err := strconv.ParseUint(part[idx+1:], 10, 32)
+			if err != nil || n == 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:idx], uint(n)
+		}
+
+		var start, end uint
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			start, end = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = parseCronValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(bounds[1], f); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			v, err := parseCronValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			start, end = v, v
+			// a/n 表示从 a 开始到最大值
+			if strings.Contains(part, "/") {
+				end = f.max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValue 解析单个取值（数字或名称）
+func parseCronValue(s string, f cronField) (uint, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if uint(n) < f.min || uint(n) > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, f.min, f.max)
+	}
+	return uint(n), nil
+}
+
+// Next 计算下一次触发时间
+func (s *specSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+	yearLimit := t.Year() + 5
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for s.second&(1<<uint(t.Second())) == 0 {
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t
+}
+
+// dayMatches 判断日期是否匹配（日与周同时受限时取并集，与标准 cron 一致）
+func (s *specSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next 计算下一次触发时间
+func (s *everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval - time.Duration(t.Nanosecond()))
+}
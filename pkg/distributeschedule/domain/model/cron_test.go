@@ -0,0 +1,168 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC) // 周一
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			from: base,
+			want: time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name: "every five minutes",
+			expr: "*/5 * * * *",
+			from: base,
+			want: time.Date(2024, 1, 15, 10, 35, 0, 0, time.UTC),
+		},
+		{
+			name: "with seconds field",
+			expr: "*/10 * * * * *",
+			from: base,
+			want: time.Date(2024, 1, 15, 10, 30, 50, 0, time.UTC),
+		},
+		{
+			name: "fixed time rolls to next day",
+			expr: "0 9 * * *",
+			from: base,
+			want: time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "range and list",
+			expr: "0 8-10,14 * * *",
+			from: base,
+			want: time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekday names",
+			expr: "0 0 * * FRI",
+			from: base,
+			want: time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as seven",
+			expr: "0 0 * * 7",
+			from: base,
+			want: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "month names roll to next year",
+			expr: "0 0 1 JAN *",
+			from: base,
+			want: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 20 * MON",
+			from: base,
+			want: time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 2 *",
+			from: base,
+			want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "daily descriptor",
+			expr: "@daily",
+			from: base,
+			want: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "hourly descriptor",
+			expr: "@hourly",
+			from: base,
+			want: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "every descriptor",
+			expr: "@every 90s",
+			from: base,
+			want: time.Date(2024, 1, 15, 10, 32, 15, 0, time.UTC),
+		},
+		{
+			name: "next is strictly after from",
+			expr: "0 31 10 * * *",
+			from: time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 16, 10, 31, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * * FOO",
+		"@every abc",
+		"@every 100ms",
+		"@fortnightly",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseCron(expr); err == nil {
+				t.Errorf("ParseCron(%q) expected error", expr)
+			}
+		})
+	}
+}
+
+func TestParseCron_Impossible(t *testing.T) {
+	schedule, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron() error = %v", err)
+	}
+	if got := schedule.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
+
+func TestTaskConfig_NextScheduleTime(t *testing.T) {
+	tc := &TaskConfig{CronExpr: "0 * * * *"}
+	from := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	got, err := tc.NextScheduleTime(from)
+	if err != nil {
+		t.Fatalf("NextScheduleTime() error = %v", err)
+	}
+	if want := time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("NextScheduleTime() = %v, want %v", got, want)
+	}
+
+	tc.CronExpr = "bad"
+	if _, err := tc.NextScheduleTime(from); err == nil {
+		t.Error("NextScheduleTime() expected error for invalid expression")
+	}
+}
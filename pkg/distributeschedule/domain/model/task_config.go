@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -40,6 +41,31 @@ func (tc *TaskConfig) IsEnabled() bool {
 	return tc.Enabled
 }
 
+// Validate 校验任务配置
+func (tc *TaskConfig) Validate() error {
+	if tc.ID == "" {
+		return fmt.Errorf("task config id is required")
+	}
+	if tc.Executor == "" {
+		return fmt.Errorf("task config executor is required")
+	}
+	if tc.CronExpr != "" {
+		if _, err := ParseCron(tc.CronExpr); err != nil {
+			return fmt.Errorf("invalid cron expr: %w", err)
+		}
+	}
+	return nil
+}
+
+// NextScheduleTime 根据 CronExpr 计算 after 之后的下一次调度时间
+func (tc *TaskConfig) NextScheduleTime(after time.Time) (time.Time, error) {
+	schedule, err := ParseCron(tc.CronExpr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(after), nil
+}
+
 // CreateTask 创建任务实例
 func (tc *TaskConfig) CreateTask(scheduledTime time.Time) *Task {
 	return &Task{
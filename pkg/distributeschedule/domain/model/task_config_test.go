@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *TaskConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid cron config",
+			config:  &TaskConfig{ID: "report", Executor: "http", CronExpr: "0 2 * * *"},
+			wantErr: false,
+		},
+		{
+			name:    "valid config without cron",
+			config:  &TaskConfig{ID: "adhoc", Executor: "local"},
+			wantErr: false,
+		},
+		{
+			name:    "missing id",
+			config:  &TaskConfig{Executor: "http"},
+			wantErr: true,
+		},
+		{
+			name:    "missing executor",
+			config:  &TaskConfig{ID: "report"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid cron",
+			config:  &TaskConfig{ID: "report", Executor: "http", CronExpr: "every day"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("TaskConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTaskConfig_CreateTask(t *testing.T) {
+	tc := &TaskConfig{ID: "report"}
+	scheduled := time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC)
+
+	task := tc.CreateTask(scheduled)
+
+	if task.ConfigID != tc.ID {
+		t.Errorf("Task.ConfigID = %s, want %s", task.ConfigID, tc.ID)
+	}
+	if task.Status != TaskPending {
+		t.Errorf("Task.Status = %s, want %s", task.Status, TaskPending)
+	}
+	if !task.ScheduledTime.Equal(scheduled) {
+		t.Errorf("Task.ScheduledTime = %v, want %v", task.ScheduledTime, scheduled)
+	}
+}
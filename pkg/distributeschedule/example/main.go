@@ -42,10 +42,26 @@ func main() {
 	})
 	ds.RegisterExecutor(localExecutor)
 
-	// 启动调度框架
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// 注册定时任务（已存在时更新）
+	job := &model.TaskConfig{
+		ID:       "hello-every-minute",
+		Name:     "hello",
+		CronExpr: "0 * * * * *",
+		Executor: "local",
+		Payload:  map[string]interface{}{"handler": "hello", "data": "from cron"},
+		Enabled:  true,
+	}
+	if err := ds.AddJob(ctx, job); err != nil {
+		if err := ds.UpdateJob(ctx, job); err != nil {
+			log.Fatalf("register job failed: %v", err)
+		}
+	}
+
+	// 启动调度框架
+
 	go func() {
 		if err := ds.Start(ctx); err != nil {
 			log.Printf("scheduler stopped: %v", err)
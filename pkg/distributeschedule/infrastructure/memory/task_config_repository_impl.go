@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"bamboo/pkg/distributeschedule/domain/model"
+	"bamboo/pkg/distributeschedule/domain/repository"
+)
+
+type taskConfigRepositoryImpl struct {
+	mu      sync.RWMutex
+	configs map[string]*model.TaskConfig
+}
+
+// NewTaskConfigRepository 创建内存任务配置仓储实现
+// 配置只保存在当前进程中，适用于单实例部署和测试
+func NewTaskConfigRepository() repository.TaskConfigRepository {
+	return &taskConfigRepositoryImpl{configs: make(map[string]*model.TaskConfig)}
+}
+
+func (r *taskConfigRepositoryImpl) Save(ctx context.Context, config *model.TaskConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.configs[config.ID]; ok {
+		return fmt.Errorf("task config already exists: %s", config.ID)
+	}
+	stored := *config
+	r.configs[config.ID] = &stored
+	return nil
+}
+
+func (r *taskConfigRepositoryImpl) FindByID(ctx context.Context, id string) (*model.TaskConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	config, ok := r.configs[id]
+	if !ok {
+		return nil, fmt.Errorf("task config not found: %s", id)
+	}
+	found := *config
+	return &found, nil
+}
+
+func (r *taskConfigRepositoryImpl) FindAll(ctx context.Context) ([]*model.TaskConfig, error) {
+	return r.find(func(*model.TaskConfig) bool { return true }), nil
+}
+
+func (r *taskConfigRepositoryImpl) FindEnabled(ctx context.Context) ([]*model.TaskConfig, error) {
+	return r.find((*model.TaskConfig).IsEnabled), nil
+}
+
+func (r *taskConfigRepositoryImpl) Update(ctx context.Context, config *model.TaskConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.configs[config.ID]; !ok {
+		return fmt.Errorf("task config not found: %s", config.ID)
+	}
+	stored := *config
+	r.configs[config.ID] = &stored
+	return nil
+}
+
+func (r *taskConfigRepositoryImpl) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.configs, id)
+	return nil
+}
+
+// find 按 ID 排序返回满足条件的配置副本
+func (r *taskConfigRepositoryImpl) find(match func(*model.TaskConfig) bool) []*model.TaskConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	configs := make([]*model.TaskConfig, 0, len(r.configs))
+	for _, config := range r.configs {
+		if !match(config) {
+			continue
+		}
+		found := *config
+		configs = append(configs, &found)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].ID < configs[j].ID })
+
+	return configs
+}
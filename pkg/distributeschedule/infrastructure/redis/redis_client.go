@@ -83,3 +83,28 @@ func (c *Client) RPop(ctx context.Context, key string) (string, error) {
 func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
 	return c.client.LLen(ctx, key).Result()
 }
+
+// SetXX 设置键值（存在时）
+func (c *Client) SetXX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return c.client.SetXX(ctx, key, value, expiration).Result()
+}
+
+// MGet 批量获取键值
+func (c *Client) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	return c.client.MGet(ctx, keys...).Result()
+}
+
+// SAdd 添加集合成员
+func (c *Client) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	return c.client.SAdd(ctx, key, members...).Err()
+}
+
+// SRem 移除集合成员
+func (c *Client) SRem(ctx context.Context, key string, members ...interface{}) error {
+	return c.client.SRem(ctx, key, members...).Err()
+}
+
+// SMembers 获取集合所有成员
+func (c *Client) SMembers(ctx context.Context, key string) ([]string, error) {
+	return c.client.SMembers(ctx, key).Result()
+}
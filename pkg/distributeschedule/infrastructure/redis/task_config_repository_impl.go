@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"bamboo/pkg/distributeschedule/domain/model"
+	"bamboo/pkg/distributeschedule/domain/repository"
+)
+
+const (
+	taskConfigPrefix     = "task:config:"
+	taskConfigAllKey     = "task:configs:all"
+	taskConfigEnabledKey = "task:configs:enabled"
+)
+
+type taskConfigRepositoryImpl struct {
+	client *Client
+}
+
+// NewTaskConfigRepository 创建任务配置仓储实现
+// 配置详情存放在 task:config:{id}，并维护全部配置与启用配置两个集合索引
+func NewTaskConfigRepository(client *Client) repository.TaskConfigRepository {
+	return &taskConfigRepositoryImpl{client: client}
+}
+
+func (r *taskConfigRepositoryImpl) Save(ctx context.Context, config *model.TaskConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("marshal task config failed: %w", err)
+	}
+
+	created, err := r.client.SetNX(ctx, taskConfigPrefix+config.ID, data, 0)
+	if err != nil {
+		return fmt.Errorf("save task config failed: %w", err)
+	}
+	if !created {
+		return fmt.Errorf("task config already exists: %s", config.ID)
+	}
+
+	if err := r.client.SAdd(ctx, taskConfigAllKey, config.ID); err != nil {
+		return fmt.Errorf("index task config failed: %w", err)
+	}
+
+	return r.updateEnabledIndex(ctx, config)
+}
+
+func (r *taskConfigRepositoryImpl) FindByID(ctx context.Context, id string) (*model.TaskConfig, error) {
+	data, err := r.client.Get(ctx, taskConfigPrefix+id)
+	if err != nil {
+		return nil, fmt.Errorf("task config not found: %s", id)
+	}
+
+	var config model.TaskConfig
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		return nil, fmt.Errorf("unmarshal task config failed: %w", err)
+	}
+
+	return &config, nil
+}
+
+func (r *taskConfigRepositoryImpl) FindAll(ctx context.Context) ([]*model.TaskConfig, error) {
+	return r.findByIndex(ctx, taskConfigAllKey)
+}
+
+func (r *taskConfigRepositoryImpl) FindEnabled(ctx context.Context) ([]*model.TaskConfig, error) {
+	return r.findByIndex(ctx, taskConfigEnabledKey)
+}
+
+func (r *taskConfigRepositoryImpl) Update(ctx context.Context, config *model.TaskConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("marshal task config failed: %w", err)
+	}
+
+	updated, err := r.client.SetXX(ctx, taskConfigPrefix+config.ID, data, 0)
+	if err != nil {
+		return fmt.Errorf("update task config failed: %w", err)
+	}
+	if !updated {
+		return fmt.Errorf("task config not found: %s", config.ID)
+	}
+
+	return r.updateEnabledIndex(ctx, config)
+}
+
+func (r *taskConfigRepositoryImpl) Delete(ctx context.Context, id string) error {
+	if err := r.client.Del(ctx, taskConfigPrefix+id); err != nil {
+		return fmt.Errorf("delete task config failed: %w", err)
+	}
+	if err := r.client.SRem(ctx, taskConfigEnabledKey, id); err != nil {
+		return fmt.Errorf("unindex task config failed: %w", err)
+	}
+	return r.client.SRem(ctx, taskConfigAllKey, id)
+}
+
+// updateEnabledIndex 根据启用状态维护启用配置索引
+func (r *taskConfigRepositoryImpl) updateEnabledIndex(ctx context.Context, config *model.TaskConfig) error {
+	if config.IsEnabled() {
+		return r.client.SAdd(ctx, taskConfigEnabledKey, config.ID)
+	}
+	return r.client.SRem(ctx, taskConfigEnabledKey, config.ID)
+}
+
+// findByIndex 按索引集合批量读取配置，索引中残留的无效 ID 会被跳过
+func (r *taskConfigRepositoryImpl) findByIndex(ctx context.Context, indexKey string) ([]*model.TaskConfig, error) {
+	ids, err := r.client.SMembers(ctx, indexKey)
+	if err != nil {
+		return nil, fmt.Errorf("find task config ids failed: %w", err)
+	}
+	if len(ids) == 0 {
+		return []*model.TaskConfig{}, nil
+	}
+	sort.Strings(ids)
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = taskConfigPrefix + id
+	}
+
+	values, err := r.client.MGet(ctx, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("get task configs failed: %w", err)
+	}
+
+	configs := make([]*model.TaskConfig, 0, len(values))
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var config model.TaskConfig
+		if err := json.Unmarshal([]byte(data), &config); err != nil {
+			continue
+		}
+
+		configs = append(configs, &config)
+	}
+
+	return configs, nil
+}
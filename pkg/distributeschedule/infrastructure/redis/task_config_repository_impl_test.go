@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"bamboo/pkg/distributeschedule/domain/model"
+	"bamboo/pkg/distributeschedule/domain/repository"
+)
+
+func newTestTaskConfigRepository(t *testing.T) (repository.TaskConfigRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := NewClient(mr.Addr(), "", 0)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewTaskConfigRepository(client), mr
+}
+
+func configIDs(configs []*model.TaskConfig) []string {
+	ids := make([]string, len(configs))
+	for i, config := range configs {
+		ids[i] = config.ID
+	}
+	return ids
+}
+
+func TestTaskConfigRepository_SaveAndFindByID(t *testing.T) {
+	repo, mr := newTestTaskConfigRepository(t)
+	ctx := context.Background()
+
+	config := &model.TaskConfig{ID: "daily-report", Name: "日报", CronExpr: "0 2 * * *", Executor: "http", Enabled: true}
+	if err := repo.Save(ctx, config); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := repo.Save(ctx, config); err == nil {
+		t.Error("Save() expected error for duplicate id")
+	}
+
+	got, err := repo.FindByID(ctx, "daily-report")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if got.Name != config.Name || got.CronExpr != config.CronExpr || got.Executor != config.Executor || !got.Enabled {
+		t.Errorf("FindByID() = %+v, want %+v", got, config)
+	}
+	if _, err := repo.FindByID(ctx, "missing"); err == nil {
+		t.Error("FindByID() expected error for missing id")
+	}
+
+	if ok, _ := mr.SIsMember(taskConfigAllKey, "daily-report"); !ok {
+		t.Error("saved config should be in the all index")
+	}
+	if ok, _ := mr.SIsMember(taskConfigEnabledKey, "daily-report"); !ok {
+		t.Error("enabled config should be in the enabled index")
+	}
+}
+
+func TestTaskConfigRepository_FindEnabled(t *testing.T) {
+	repo, mr := newTestTaskConfigRepository(t)
+	ctx := context.Background()
+
+	_ = repo.Save(ctx, &model.TaskConfig{ID: "b", Executor: "local", Enabled: true})
+	_ = repo.Save(ctx, &model.TaskConfig{ID: "a", Executor: "local", Enabled: true})
+	_ = repo.Save(ctx, &model.TaskConfig{ID: "c", Executor: "local", Enabled: false})
+
+	// 索引中残留的无效 ID 被跳过
+	_, _ = mr.SAdd(taskConfigEnabledKey, "stale")
+
+	enabled, err := repo.FindEnabled(ctx)
+	if err != nil {
+		t.Fatalf("FindEnabled() error = %v", err)
+	}
+	if got := configIDs(enabled); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("FindEnabled() = %v, want [a b]", got)
+	}
+
+	all, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if got := configIDs(all); len(got) != 3 {
+		t.Errorf("FindAll() = %v, want 3 configs", got)
+	}
+}
+
+func TestTaskConfigRepository_Disable(t *testing.T) {
+	repo, mr := newTestTaskConfigRepository(t)
+	ctx := context.Background()
+
+	config := &model.TaskConfig{ID: "job", Executor: "local", Enabled: true}
+	_ = repo.Save(ctx, config)
+
+	config.Enabled = false
+	if err := repo.Update(ctx, config); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if ok, _ := mr.SIsMember(taskConfigEnabledKey, "job"); ok {
+		t.Error("disabled config should be removed from the enabled index")
+	}
+	if ok, _ := mr.SIsMember(taskConfigAllKey, "job"); !ok {
+		t.Error("disabled config should stay in the all index")
+	}
+	if enabled, _ := repo.FindEnabled(ctx); len(enabled) != 0 {
+		t.Errorf("FindEnabled() = %v, want none", configIDs(enabled))
+	}
+
+	config.Enabled = true
+	_ = repo.Update(ctx, config)
+	if ok, _ := mr.SIsMember(taskConfigEnabledKey, "job"); !ok {
+		t.Error("re-enabled config should be back in the enabled index")
+	}
+
+	if err := repo.Update(ctx, &model.TaskConfig{ID: "missing", Executor: "local"}); err == nil {
+		t.Error("Update() expected error for missing id")
+	}
+}
+
+func TestTaskConfigRepository_Delete(t *testing.T) {
+	repo, mr := newTestTaskConfigRepository(t)
+	ctx := context.Background()
+
+	_ = repo.Save(ctx, &model.TaskConfig{ID: "job", Executor: "local", Enabled: true})
+	if err := repo.Delete(ctx, "job"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if mr.Exists(taskConfigPrefix + "job") {
+		t.Error("config detail should be deleted")
+	}
+	if ok, _ := mr.SIsMember(taskConfigAllKey, "job"); ok {
+		t.Error("deleted config should be removed from the all index")
+	}
+	if ok, _ := mr.SIsMember(taskConfigEnabledKey, "job"); ok {
+		t.Error("deleted config should be removed from the enabled index")
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"bamboo/pkg/distributeschedule/application"
 	"bamboo/pkg/distributeschedule/config"
 	"bamboo/pkg/distributeschedule/domain/model"
+	"bamboo/pkg/distributeschedule/domain/repository"
 	"bamboo/pkg/distributeschedule/domain/service"
 	"bamboo/pkg/distributeschedule/infrastructure/executor"
 	"bamboo/pkg/distributeschedule/infrastructure/redis"
@@ -17,6 +19,7 @@ import (
 type Scheduler struct {
 	config           *config.Config
 	redisClient      *redis.Client
+	configRepo       repository.TaskConfigRepository
 	scheduleService  *application.ScheduleService
 	workerService    *application.WorkerService
 	executorRegistry service.ExecutorRegistry
@@ -36,6 +39,7 @@ func NewScheduler(cfg *config.Config) (*Scheduler, error) {
 	// 创建仓储
 	taskRepo := redis.NewTaskRepository(redisClient)
 	workerRepo := redis.NewWorkerRepository(redisClient)
+	configRepo := redis.NewTaskConfigRepository(redisClient)
 
 	// 创建执行器注册表
 	executorRegistry := executor.NewExecutorRegistry()
@@ -54,9 +58,11 @@ func NewScheduler(cfg *config.Config) (*Scheduler, error) {
 	scheduleService := application.NewScheduleService(
 		taskRepo,
 		workerRepo,
+		configRepo,
 		leaderElection,
 		loadBalancer,
 		cfg.Schedule.ScanInterval,
+		cfg.Schedule.TriggerInterval,
 		cfg.Worker.HeartbeatTimeout,
 	)
 
@@ -74,6 +80,7 @@ func NewScheduler(cfg *config.Config) (*Scheduler, error) {
 		worker,
 		taskRepo,
 		workerRepo,
+		configRepo,
 		executorRegistry,
 		cfg.Worker.HeartbeatInterval,
 	)
@@ -81,6 +88,7 @@ func NewScheduler(cfg *config.Config) (*Scheduler, error) {
 	return &Scheduler{
 		config:           cfg,
 		redisClient:      redisClient,
+		configRepo:       configRepo,
 		scheduleService:  scheduleService,
 		workerService:    workerService,
 		executorRegistry: executorRegistry,
@@ -107,6 +115,71 @@ func (s *Scheduler) RegisterExecutor(executor service.Executor) {
 	s.executorRegistry.Register(executor)
 }
 
+// AddJob 注册任务配置
+func (s *Scheduler) AddJob(ctx context.Context, job *model.TaskConfig) error {
+	if err := job.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	return s.configRepo.Save(ctx, job)
+}
+
+// UpdateJob 更新任务配置
+func (s *Scheduler) UpdateJob(ctx context.Context, job *model.TaskConfig) error {
+	if err := job.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := s.configRepo.FindByID(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+
+	job.CreatedAt = existing.CreatedAt
+	job.UpdatedAt = time.Now()
+	return s.configRepo.Update(ctx, job)
+}
+
+// RemoveJob 删除任务配置
+func (s *Scheduler) RemoveJob(ctx context.Context, id string) error {
+	return s.configRepo.Delete(ctx, id)
+}
+
+// GetJob 获取任务配置
+func (s *Scheduler) GetJob(ctx context.Context, id string) (*model.TaskConfig, error) {
+	return s.configRepo.FindByID(ctx, id)
+}
+
+// ListJobs 列出所有任务配置
+func (s *Scheduler) ListJobs(ctx context.Context) ([]*model.TaskConfig, error) {
+	return s.configRepo.FindAll(ctx)
+}
+
+// EnableJob 启用任务配置
+func (s *Scheduler) EnableJob(ctx context.Context, id string) error {
+	return s.setJobEnabled(ctx, id, true)
+}
+
+// DisableJob 禁用任务配置
+func (s *Scheduler) DisableJob(ctx context.Context, id string) error {
+	return s.setJobEnabled(ctx, id, false)
+}
+
+// setJobEnabled 切换任务配置启用状态
+func (s *Scheduler) setJobEnabled(ctx context.Context, id string, enabled bool) error {
+	job, err := s.configRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	job.Enabled = enabled
+	job.UpdatedAt = time.Now()
+	return s.configRepo.Update(ctx, job)
+}
+
 // Close 关闭调度器
 func (s *Scheduler) Close() error {
 	return s.redisClient.Close()